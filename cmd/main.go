@@ -4,13 +4,23 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"online-subscribe-rest-service/internal/alerts"
 	"online-subscribe-rest-service/internal/api/handler"
 	"online-subscribe-rest-service/internal/api/router"
+	"online-subscribe-rest-service/internal/exchange"
+	"online-subscribe-rest-service/internal/jobs"
+	"online-subscribe-rest-service/internal/notifier"
+	"online-subscribe-rest-service/internal/outbox"
 	"online-subscribe-rest-service/internal/repository"
+	"online-subscribe-rest-service/internal/repository/memory"
+	sqliterepo "online-subscribe-rest-service/internal/repository/sqlite"
+	"online-subscribe-rest-service/internal/scheduler"
 	"online-subscribe-rest-service/internal/service"
+	"online-subscribe-rest-service/internal/webhooks"
 	"online-subscribe-rest-service/pkg/config"
 	"online-subscribe-rest-service/pkg/logger"
 	"online-subscribe-rest-service/pkg/postgres"
+	"online-subscribe-rest-service/pkg/sqlite"
 )
 
 func main() {
@@ -28,20 +38,131 @@ func main() {
 		return
 	}
 
-	pgConn, err := postgres.ConnectToPostgres(ctx, cfg.Postgres.DSN)
-	if err != nil {
-		log.ErrorF("failed to connect to postgres: %w", err)
+	// The storage backs the service as well as the notifier, the outbox relay and the webhook sender.
+	var (
+		repo interface {
+			service.Repo
+			notifier.Store
+			outbox.Store
+			webhooks.Store
+		}
+		locker scheduler.Locker
+	)
+
+	switch cfg.Storage.Driver {
+	case config.StorageMemory:
+		log.Warn("storage driver is memory, subscriptions are lost on restart")
+
+		repo = memory.NewSubscriptionRepo()
+	case config.StoragePostgres:
+		pgPool, err := postgres.ConnectToPostgres(ctx, cfg.Postgres)
+		if err != nil {
+			log.ErrorF("failed to connect to postgres: %w", err)
+			return
+		}
+
+		defer pgPool.Close()
+
+		if err := postgres.UpMigrations(cfg.Postgres.DSN); err != nil {
+			log.ErrorF("failed to up migrations: %w", err)
+			return
+		}
+
+		repo = repository.NewSubscriptionRepo(pgPool)
+		locker = postgres.NewAdvisoryLocker(pgPool)
+	case config.StorageSQLite:
+		db, err := sqlite.Open(ctx, cfg.SQLite.Path)
+		if err != nil {
+			log.ErrorF("failed to open sqlite database: %w", err)
+			return
+		}
+
+		defer db.Close()
+
+		if err := sqlite.UpMigrations(db); err != nil {
+			log.ErrorF("failed to up migrations: %w", err)
+			return
+		}
+
+		repo = sqliterepo.NewSubscriptionRepo(db)
+	default:
+		log.ErrorF("unsupported storage driver: %s", cfg.Storage.Driver)
 		return
 	}
 
-	if err := postgres.UpMigrations(cfg.Postgres.DSN); err != nil {
-		log.ErrorF("failed to up migrations: %w", err)
+	rates := exchange.NewStaticRates(cfg.Currency.Default, nil)
+	if cfg.Currency.RatesFile != "" {
+		rates, err = exchange.LoadFile(cfg.Currency.RatesFile)
+		if err != nil {
+			log.ErrorF("failed to load exchange rates: %w", err)
+			return
+		}
+	}
+
+	channels := []notifier.Channel{notifier.NewWebhook(cfg.Notify.WebhookTimeout)}
+	if cfg.Notify.SMTPHost != "" {
+		channels = append(channels, notifier.NewEmail(cfg.Notify.SMTPHost, cfg.Notify.SMTPPort,
+			cfg.Notify.SMTPFrom, cfg.Notify.SMTPUsername, cfg.Notify.SMTPPassword, cfg.Notify.SMTPTimeout))
+	}
+
+	dispatcher := notifier.NewDispatcher(log, repo, locker, cfg.Notify.MaxAttempts, cfg.Notify.RetryBackoff, cfg.Notify.PollInterval, channels...)
+
+	service := service.NewService(log, repo, rates, alerts.NewSender(log, dispatcher), cfg.Currency.Default)
+
+	var publisher outbox.Publisher
+
+	switch cfg.Outbox.Publisher {
+	case config.PublisherLog:
+		publisher = outbox.NewLogPublisher(log)
+	case config.PublisherHTTP:
+		if cfg.Outbox.WebhookURL == "" {
+			log.Error("the http outbox publisher needs OUTBOX_WEBHOOK_URL")
+			return
+		}
+
+		publisher = outbox.NewHTTPPublisher(cfg.Outbox.WebhookURL, cfg.Outbox.WebhookTimeout)
+	default:
+		log.ErrorF("unsupported outbox publisher: %s", cfg.Outbox.Publisher)
 		return
 	}
 
-	repo := repository.NewSubscriptionRepo(pgConn)
-	service := service.NewService(repo)
-	handler := handler.NewHandler(log, service)
+	publishers := outbox.Publishers{publisher, webhooks.NewPublisher(repo)}
+
+	relay := outbox.NewRelay(log, repo, publishers, locker, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize, cfg.Outbox.RetryBackoff)
+
+	backgroundJobs := []scheduler.Job{
+		{Name: "expire", Schedule: cfg.Scheduler.Expire, Exclusive: true, Run: jobs.NewExpireJob(log, service).Run},
+		{Name: "trials", Schedule: cfg.Scheduler.Trials, Exclusive: true, Run: jobs.NewTrialsJob(log, service, cfg.Trials.NoticeDays).Run},
+		{Name: "renewals", Schedule: cfg.Scheduler.Renewals, Exclusive: true, Run: jobs.NewRenewalsJob(service, cfg.Notify.RenewalNoticeDays).Run},
+		{Name: "purge", Schedule: cfg.Scheduler.Purge, Exclusive: true, Run: jobs.NewPurgeJob(service, cfg.Trash.Retention).Run},
+		{Name: "outbox", Schedule: cfg.Scheduler.Outbox, Exclusive: true, Run: jobs.NewOutboxPurgeJob(relay, cfg.Outbox.Retention).Run},
+	}
+
+	scheduler := scheduler.New(log, locker)
+
+	for _, job := range backgroundJobs {
+		if err := scheduler.Add(job); err != nil {
+			log.ErrorF("failed to schedule job: %w", err)
+			return
+		}
+	}
+
+	go scheduler.Run(ctx)
+
+	go dispatcher.Run(ctx)
+
+	go relay.Run(ctx)
+
+	go webhooks.NewSender(log, repo, locker, webhooks.Options{
+		Timeout:      cfg.Webhooks.Timeout,
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		Backoff:      cfg.Webhooks.RetryBackoff,
+		DisableAfter: cfg.Webhooks.DisableAfter,
+		PollInterval: cfg.Webhooks.PollInterval,
+		Concurrency:  cfg.Webhooks.Concurrency,
+	}).Run(ctx)
+
+	handler := handler.NewHandler(log, service, scheduler, cfg.HTTP.PublicURL)
 	router := router.NewRouter(handler)
 
 	server := &http.Server{
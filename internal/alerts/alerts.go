@@ -0,0 +1,81 @@
+// Package alerts turns the alerts the service raises into notifications for users.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/internal/notifier"
+	"online-subscribe-rest-service/pkg/logger"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Dispatcher sends a message to the user, see notifier.Dispatcher.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, userID uuid.UUID, msg notifier.Message) error
+}
+
+// Sender logs every alert and notifies the user of it.
+type Sender struct {
+	log        logger.Logger
+	dispatcher Dispatcher
+}
+
+func NewSender(log logger.Logger, dispatcher Dispatcher) *Sender {
+	return &Sender{log: log, dispatcher: dispatcher}
+}
+
+func (s *Sender) BudgetExceeded(ctx context.Context, status entity.BudgetStatus) {
+	s.log.WarnW("budget exceeded", map[string]any{
+		"budget_id":    status.ID.String(),
+		"user_id":      status.UserID.String(),
+		"period":       string(status.Period),
+		"category":     string(status.Category),
+		"limit":        status.Limit.String(),
+		"projected":    status.Projected.String(),
+		"currency":     status.Limit.Currency,
+		"period_start": status.PeriodStart.Format(time.DateOnly),
+	})
+
+	// Every time the budget is exceeded is worth a notification, so the message has no key.
+	s.send(ctx, status.UserID, notifier.Message{
+		Event:   entity.EventBudgetExceeded,
+		Subject: "Budget exceeded",
+		Text:    fmt.Sprintf("Your %s.", status.Warning()),
+		Data:    status,
+	})
+}
+
+func (s *Sender) TrialEnding(ctx context.Context, sub entity.Subscription) {
+	trialEnd := sub.TrialEnd.Format(time.DateOnly)
+
+	s.send(ctx, sub.UserID, notifier.Message{
+		Event:   entity.EventTrialEnding,
+		Key:     fmt.Sprintf("trial_ending:%s:%s", sub.ID, trialEnd),
+		Subject: fmt.Sprintf("%s trial ends on %s", sub.ServiceName, trialEnd),
+		Text: fmt.Sprintf("The free trial of %s ends on %s, the first charge of %s %s follows the next day. "+
+			"Cancel the subscription before then if you don't want to keep it.",
+			sub.ServiceName, trialEnd, sub.Price, sub.Price.Currency),
+		Data: sub,
+	})
+}
+
+func (s *Sender) RenewalDue(ctx context.Context, renewal entity.Renewal) {
+	date := renewal.Date.Format(time.DateOnly)
+
+	s.send(ctx, renewal.UserID, notifier.Message{
+		Event:   entity.EventRenewal,
+		Key:     fmt.Sprintf("renewal:%s:%s", renewal.SubscriptionID, date),
+		Subject: fmt.Sprintf("%s renews on %s", renewal.ServiceName, date),
+		Text:    fmt.Sprintf("%s renews on %s for %s %s.", renewal.ServiceName, date, renewal.Price, renewal.Price.Currency),
+		Data:    renewal,
+	})
+}
+
+func (s *Sender) send(ctx context.Context, userID uuid.UUID, msg notifier.Message) {
+	if err := s.dispatcher.Dispatch(ctx, userID, msg); err != nil {
+		s.log.ErrorF("alerts: failed to dispatch %s notification: %v", msg.Event, err)
+	}
+}
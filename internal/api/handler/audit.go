@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"online-subscribe-rest-service/internal/audit"
+	"online-subscribe-rest-service/internal/entity"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// anonymous is the actor of the requests without the X-Actor header.
+const anonymous = "anonymous"
+
+// maxHeaderLength caps the X-Actor and X-Request-ID values kept in the audit log.
+const maxHeaderLength = 64
+
+// Actor is the middleware passing who makes the request on to the audit log: the X-Actor header
+// and the X-Request-ID header, generated when missing and echoed in the response.
+//
+// The service does not verify X-Actor, the actor is whoever the caller claims to be. It can be
+// trusted only when the gateway in front of the service authenticates the caller and overwrites
+// the header. The job: prefix belongs to the background jobs, a request claiming it is anonymous.
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := headerValue(r, "X-Actor")
+		if actor == "" || strings.HasPrefix(actor, audit.JobPrefix) {
+			actor = anonymous
+		}
+
+		requestID := headerValue(r, "X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.Must(uuid.NewV4()).String()
+		}
+
+		w.Header().Set("X-Request-ID", requestID)
+
+		next.ServeHTTP(w, r.WithContext(audit.WithActor(r.Context(), actor, requestID)))
+	})
+}
+
+// headerValue returns the header without the characters that are not printable and cut to
+// maxHeaderLength characters, so a caller cannot forge log lines or flood the audit log.
+func headerValue(r *http.Request, name string) string {
+	value := strings.Map(func(r rune) rune {
+		if !unicode.IsPrint(r) {
+			return -1
+		}
+
+		return r
+	}, r.Header.Get(name))
+
+	if runes := []rune(value); len(runes) > maxHeaderLength {
+		value = string(runes[:maxHeaderLength])
+	}
+
+	return strings.TrimSpace(value)
+}
+
+// @Summary Get subscription history
+// @Description История изменений подписки из журнала аудита, от новых к старым: кто (actor), в каком запросе (request_id), какое действие (create, update, delete, restore, purge) и какие поля изменились — значения до и после. История сохраняется и после удаления подписки
+// @Tags Subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param limit query int false "Page size (1-100, default 50)"
+// @Param before query int false "next_before of the previous page"
+// @Success 200 {object} entity.AuditPage
+// @Failure 400 {string} string "Invalid parameters"
+// @Failure 404 {string} string "Subscription not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /subscriptions/{id}/history [get]
+func (h *Handler) SubscriptionHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid subscription id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	limit, before, err := parseAuditPage(r.URL.Query())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.subscriptionsService.SubscriptionHistory(ctx, id, limit, before)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("subscriptions by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to get history of subscription %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeAuditPage(w, page)
+}
+
+// @Summary Query audit log
+// @Description Журнал аудита всех изменений, от новых к старым, с фильтрами по типу и ID сущности, автору (actor), ID запроса, действию и времени изменения [from, to)
+// @Tags Admin
+// @Produce json
+// @Param entity_type query string false "Entity type, e.g. subscription"
+// @Param entity_id query string false "Entity ID (UUID)"
+// @Param actor query string false "Actor, the X-Actor header of the request or job:<name>"
+// @Param request_id query string false "Request ID, the X-Request-ID header"
+// @Param action query string false "create, update or delete"
+// @Param from query string false "Changes made at or after, RFC 3339"
+// @Param to query string false "Changes made before, RFC 3339"
+// @Param limit query int false "Page size (1-100, default 50)"
+// @Param before query int false "next_before of the previous page"
+// @Success 200 {object} entity.AuditPage
+// @Failure 400 {string} string "Invalid parameters"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /admin/audit [get]
+func (h *Handler) AuditLog(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if err := filter.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.subscriptionsService.AuditLog(r.Context(), filter)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get audit log: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeAuditPage(w, page)
+}
+
+func (h *Handler) writeAuditPage(w http.ResponseWriter, page entity.AuditPage) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		h.log.ErrorF("handler: failed to encode audit entries %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseAuditFilter(q url.Values) (entity.AuditFilter, error) {
+	limit, before, err := parseAuditPage(q)
+	if err != nil {
+		return entity.AuditFilter{}, err
+	}
+
+	filter := entity.AuditFilter{
+		EntityType: q.Get("entity_type"),
+		Actor:      q.Get("actor"),
+		RequestID:  q.Get("request_id"),
+		Action:     entity.AuditAction(q.Get("action")),
+		Before:     before,
+		Limit:      limit,
+	}
+
+	if qID := q.Get("entity_id"); qID != "" {
+		id, err := uuid.FromString(qID)
+		if err != nil {
+			return entity.AuditFilter{}, fmt.Errorf("invalid entity_id: %s", qID)
+		}
+
+		filter.EntityID = &id
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return entity.AuditFilter{}, fmt.Errorf("%s must be an RFC 3339 time", p.name)
+			}
+
+			*p.dst = &t
+		}
+	}
+
+	return filter, nil
+}
+
+func parseAuditPage(q url.Values) (int, *int64, error) {
+	limit := entity.DefaultPageLimit
+	if qLimit := q.Get("limit"); qLimit != "" {
+		var err error
+
+		limit, err = strconv.Atoi(qLimit)
+		if err != nil || limit < 1 || limit > entity.MaxAuditEntries {
+			return 0, nil, fmt.Errorf("limit must be between 1 and %d", entity.MaxAuditEntries)
+		}
+	}
+
+	var before *int64
+	if qBefore := q.Get("before"); qBefore != "" {
+		id, err := strconv.ParseInt(qBefore, 10, 64)
+		if err != nil || id < 1 {
+			return 0, nil, fmt.Errorf("invalid before: %s", qBefore)
+		}
+
+		before = &id
+	}
+
+	return limit, before, nil
+}
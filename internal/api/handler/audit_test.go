@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"online-subscribe-rest-service/internal/audit"
+	"strings"
+	"testing"
+)
+
+func TestActor(t *testing.T) {
+	tests := []struct {
+		name      string
+		actor     string
+		requestID string
+		want      string
+		wantID    string
+	}{
+		{name: "header", actor: "alice", requestID: "r1", want: "alice", wantID: "r1"},
+		{name: "no header", want: anonymous},
+		{name: "control characters", actor: "alice\r\n\x1b[31mbob‮", requestID: "r\t1", want: "alice[31mbob", wantID: "r1"},
+		{name: "only control characters", actor: "\x00\x07", want: anonymous},
+		{name: "long", actor: strings.Repeat("a", 100), requestID: strings.Repeat("r", 100),
+			want: strings.Repeat("a", maxHeaderLength), wantID: strings.Repeat("r", maxHeaderLength)},
+		{name: "job prefix", actor: "job:expire", want: anonymous},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actor, requestID string
+
+			h := Actor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actor, requestID = audit.Actor(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("X-Actor", tt.actor)
+			r.Header.Set("X-Request-ID", tt.requestID)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if actor != tt.want {
+				t.Errorf("actor = %q, want %q", actor, tt.want)
+			}
+
+			if tt.wantID != "" && requestID != tt.wantID {
+				t.Errorf("request ID = %q, want %q", requestID, tt.wantID)
+			}
+
+			if requestID == "" || w.Header().Get("X-Request-ID") != requestID {
+				t.Errorf("X-Request-ID = %q, want %q", w.Header().Get("X-Request-ID"), requestID)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// @Summary Create budget
+// @Description Создаёт месячный или годовой бюджет пользователя, общий или на категорию; у пользователя не больше одного бюджета на период и категорию
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Param budget body entity.Budget true "Budget payload"
+// @Success 201 {string} string "Budget created (ID)"
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 409 {string} string "Budget for the period and category already exists"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /budgets [post]
+func (h *Handler) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var b entity.Budget
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		h.log.ErrorF("failed to decode request body to struct: %w", err)
+		http.Error(w, "handler: failed to decode request body to struct", http.StatusBadRequest)
+		return
+	}
+
+	if b.UserID.IsNil() {
+		http.Error(w, "handler: incorrect params: user id is empty", http.StatusBadRequest)
+		return
+	}
+
+	if err := b.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.subscriptionsService.CreateBudget(ctx, b)
+	if err != nil {
+		if errors.Is(err, entity.ErrAlreadyExists) {
+			http.Error(w, "handler: budget for the period and category already exists", http.StatusConflict)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to create budget %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.log.ErrorF("handler: failed to encode id %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary List user budgets
+// @Description Возвращает бюджеты пользователя с тратами за текущий период: spent — списано на сегодня, projected — вместе со списаниями до конца периода, remaining — остаток лимита после projected
+// @Tags Budgets
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Success 200 {array} entity.BudgetStatus
+// @Failure 400 {string} string "Invalid user ID"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/budgets [get]
+func (h *Handler) Budgets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	budgets, err := h.subscriptionsService.Budgets(ctx, userID)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get budgets %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(budgets); err != nil {
+		h.log.ErrorF("handler: failed to encode budgets %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Get budget
+// @Description Возвращает бюджет с тратами за текущий период и остатком
+// @Tags Budgets
+// @Produce json
+// @Param id path string true "Budget ID (UUID)"
+// @Success 200 {object} entity.BudgetStatus
+// @Failure 400 {string} string "Invalid budget ID"
+// @Failure 404 {string} string "Budget not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /budgets/{id} [get]
+func (h *Handler) BudgetByID(w http.ResponseWriter, r *http.Request) {
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid budget id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	h.writeBudget(w, r, id)
+}
+
+// @Summary Update budget
+// @Description Заменяет период, категорию и лимит бюджета; пользователь бюджета не меняется
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Param id path string true "Budget ID (UUID)"
+// @Param budget body entity.Budget true "Budget payload"
+// @Success 200 {object} entity.BudgetStatus
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 404 {string} string "Budget not found"
+// @Failure 409 {string} string "Budget for the period and category already exists"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /budgets/{id} [put]
+func (h *Handler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid budget id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	var b entity.Budget
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		h.log.ErrorF("failed to decode request body to struct: %w", err)
+		http.Error(w, "handler: failed to decode request body to struct", http.StatusBadRequest)
+		return
+	}
+
+	b.ID = id
+	if err := b.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionsService.UpdateBudget(ctx, b); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrNotFound):
+			http.Error(w, fmt.Sprintf("budget by id %s not found", id), http.StatusNotFound)
+		case errors.Is(err, entity.ErrAlreadyExists):
+			http.Error(w, "handler: budget for the period and category already exists", http.StatusConflict)
+		default:
+			h.log.ErrorF("handler: failed to update budget %s: %v", id, err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeBudget(w, r, id)
+}
+
+// @Summary Delete budget
+// @Description Удаляет бюджет
+// @Tags Budgets
+// @Param id path string true "Budget ID (UUID)"
+// @Success 204
+// @Failure 400 {string} string "Invalid budget ID"
+// @Failure 404 {string} string "Budget not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /budgets/{id} [delete]
+func (h *Handler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid budget id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionsService.DeleteBudget(ctx, id); err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("budget by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to delete budget %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeBudget responds with the stored budget and where it stands.
+func (h *Handler) writeBudget(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	status, err := h.subscriptionsService.BudgetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("budget by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to get budget %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		h.log.ErrorF("handler: failed to encode budget %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// budgetWarnings adds a Warning header for every budget a change pushed over its limit.
+func budgetWarnings(w http.ResponseWriter, exceeded []entity.BudgetStatus) {
+	for _, status := range exceeded {
+		w.Header().Add("Warning", fmt.Sprintf("299 - %q", status.Warning()))
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestBudgetWarnings(t *testing.T) {
+	h, svc := newTestRouter(t)
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+
+	budgets := []entity.Budget{
+		{UserID: user, Period: entity.BudgetMonthly, Limit: entity.NewMoney(10000, "RUB")},
+		{UserID: user, Period: entity.BudgetMonthly, Category: entity.CategoryStreaming, Limit: entity.NewMoney(5000, "RUB")},
+	}
+
+	for _, b := range budgets {
+		if _, err := svc.CreateBudget(ctx, b); err != nil {
+			t.Fatalf("CreateBudget() error = %v", err)
+		}
+	}
+
+	// Charged on the first of the current month, so every change counts against this month's budgets.
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+
+	subscription := func(id, amount string) string {
+		if id != "" {
+			id = `"id":"` + id + `",`
+		}
+
+		return `{` + id + `"service_name":"Okko","category":"streaming","price":{"amount":"` + amount + `","currency":"RUB"},` +
+			`"user_id":"` + user.String() + `","start_date":"` + start + `","price_effective_from":"` + start + `"}`
+	}
+
+	// The first subscription goes through the service, so its ID is known to the update below.
+	first, _, err := svc.CreateSubscription(ctx, entity.Subscription{
+		ServiceName: "Okko",
+		Category:    entity.CategoryStreaming,
+		Price:       entity.NewMoney(4000, "RUB"),
+		UserID:      user,
+		StartDate:   time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+
+	steps := []struct {
+		name         string
+		method       string
+		ifMatch      string
+		body         string
+		wantWarnings []string
+	}{
+		{"create over the streaming budget", http.MethodPost, "", subscription("", "20"), []string{"monthly streaming budget of 50.00 RUB exceeded: 60.00 RUB projected"}},
+		{"create while already over", http.MethodPost, "", subscription("", "10"), nil},
+		{"update over the overall budget", http.MethodPut, `"1"`, subscription(first.String(), "80"), []string{"monthly overall budget of 100.00 RUB exceeded: 110.00 RUB projected"}},
+		{"update under both", http.MethodPut, `"2"`, subscription(first.String(), "1"), nil},
+	}
+
+	for _, step := range steps {
+		w := serve(t, h, step.method, "/subscriptions", step.ifMatch, step.body)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d, body %q", step.name, w.Code, http.StatusOK, w.Body.String())
+		}
+
+		got := w.Header().Values("Warning")
+		if len(got) != len(step.wantWarnings) {
+			t.Fatalf("%s: Warning = %q, want %q", step.name, got, step.wantWarnings)
+		}
+
+		for i, want := range step.wantWarnings {
+			if want = `299 - "` + want + `"`; got[i] != want {
+				t.Errorf("%s: Warning = %s, want %s", step.name, got[i], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// @Summary Create catalog service
+// @Description Добавляет сервис в каталог; имя и алиасы не должны совпадать (без учёта регистра) с именами других сервисов
+// @Tags Services
+// @Accept json
+// @Produce json
+// @Param service body entity.CatalogEntry true "Service payload"
+// @Success 201 {string} string "Service created (ID)"
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 409 {string} string "Name or alias already taken"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /services [post]
+func (h *Handler) CreateCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var e entity.CatalogEntry
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		h.log.ErrorF("failed to decode request body to struct: %w", err)
+		http.Error(w, "handler: failed to decode request body to struct", http.StatusBadRequest)
+		return
+	}
+
+	if err := e.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.subscriptionsService.CreateCatalogEntry(ctx, e)
+	if err != nil {
+		if errors.Is(err, entity.ErrAlreadyExists) {
+			http.Error(w, "handler: service name or alias already taken", http.StatusConflict)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to create catalog entry %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.log.ErrorF("handler: failed to encode id %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary List catalog services
+// @Description Возвращает все сервисы каталога, отсортированные по имени
+// @Tags Services
+// @Produce json
+// @Success 200 {array} entity.CatalogEntry
+// @Failure 500 {string} string "Internal server error"
+// @Router       /services [get]
+func (h *Handler) CatalogEntries(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.subscriptionsService.CatalogEntries(r.Context())
+	if err != nil {
+		h.log.ErrorF("handler: failed to get catalog %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		h.log.ErrorF("handler: failed to encode catalog %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Get catalog service
+// @Description Возвращает сервис каталога по его ID
+// @Tags Services
+// @Produce json
+// @Param id path string true "Service ID (UUID)"
+// @Success 200 {object} entity.CatalogEntry
+// @Failure 400 {string} string "Invalid service ID"
+// @Failure 404 {string} string "Service not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /services/{id} [get]
+func (h *Handler) CatalogEntryByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid service id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	e, err := h.subscriptionsService.CatalogEntryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("service by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to get catalog entry %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(e); err != nil {
+		h.log.ErrorF("handler: failed to encode catalog entry %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Update catalog service
+// @Description Заменяет сервис каталога; подписки на сервис получают его новое имя
+// @Tags Services
+// @Accept json
+// @Produce json
+// @Param id path string true "Service ID (UUID)"
+// @Param service body entity.CatalogEntry true "Service payload"
+// @Success 200 {object} entity.CatalogEntry
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 404 {string} string "Service not found"
+// @Failure 409 {string} string "Name or alias already taken"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /services/{id} [put]
+func (h *Handler) UpdateCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid service id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	var e entity.CatalogEntry
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		h.log.ErrorF("failed to decode request body to struct: %w", err)
+		http.Error(w, "handler: failed to decode request body to struct", http.StatusBadRequest)
+		return
+	}
+
+	e.ID = id
+	if err := e.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionsService.UpdateCatalogEntry(ctx, e); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrNotFound):
+			http.Error(w, fmt.Sprintf("service by id %s not found", id), http.StatusNotFound)
+		case errors.Is(err, entity.ErrAlreadyExists):
+			http.Error(w, "handler: service name or alias already taken", http.StatusConflict)
+		default:
+			h.log.ErrorF("handler: failed to update catalog entry %s: %v", id, err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeCatalogEntry(w, r, id)
+}
+
+// @Summary Delete catalog service
+// @Description Удаляет сервис из каталога, если на него нет подписок
+// @Tags Services
+// @Param id path string true "Service ID (UUID)"
+// @Success 204
+// @Failure 400 {string} string "Invalid service ID"
+// @Failure 409 {string} string "Service has subscriptions"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /services/{id} [delete]
+func (h *Handler) DeleteCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid service id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionsService.DeleteCatalogEntry(ctx, id); err != nil {
+		if errors.Is(err, entity.ErrServiceInUse) {
+			http.Error(w, "handler: service has subscriptions", http.StatusConflict)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to delete catalog entry %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeCatalogEntry responds with the stored catalog entry.
+func (h *Handler) writeCatalogEntry(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	e, err := h.subscriptionsService.CatalogEntryByID(r.Context(), id)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get catalog entry %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(e); err != nil {
+		h.log.ErrorF("handler: failed to encode catalog entry %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
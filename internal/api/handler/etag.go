@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// etag formats the subscription's version as a strong entity tag.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatch reads the version the client based its change on from the If-Match header. Without
+// the header it answers 428, with a tag that isn't a version of ours 412, and reports false.
+// If-Match: * matches whatever version the subscription is at now, 412 when there is none.
+func (h *Handler) ifMatch(w http.ResponseWriter, r *http.Request, id uuid.UUID) (int64, bool) {
+	tag := strings.TrimSpace(r.Header.Get("If-Match"))
+	if tag == "" {
+		http.Error(w, "handler: If-Match header with the subscription's ETag is required", http.StatusPreconditionRequired)
+		return 0, false
+	}
+
+	if tag == "*" {
+		sub, err := h.subscriptionsService.SubscriptionByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, entity.ErrNotFound) {
+				http.Error(w, "handler: subscription not found", http.StatusPreconditionFailed)
+				return 0, false
+			}
+
+			h.log.ErrorF("handler: failed to get subscription %s: %v", id, err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return 0, false
+		}
+
+		return sub.Version, true
+	}
+
+	// Weak tags never match, If-Match compares strongly.
+	version, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(tag, `"`), `"`), 10, 64)
+	if err != nil || etag(version) != tag {
+		http.Error(w, "handler: subscription version mismatch", http.StatusPreconditionFailed)
+		return 0, false
+	}
+
+	return version, true
+}
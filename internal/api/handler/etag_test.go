@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/internal/exchange"
+	"online-subscribe-rest-service/internal/repository/memory"
+	"online-subscribe-rest-service/internal/service"
+	"online-subscribe-rest-service/pkg/logger"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// noAlerts drops the alerts of the service under test.
+type noAlerts struct{}
+
+func (noAlerts) BudgetExceeded(context.Context, entity.BudgetStatus) {}
+func (noAlerts) TrialEnding(context.Context, entity.Subscription)    {}
+func (noAlerts) RenewalDue(context.Context, entity.Renewal)          {}
+
+// newTestRouter serves the subscription routes from a service over a memory store.
+func newTestRouter(t *testing.T) (http.Handler, *service.Service) {
+	t.Helper()
+
+	log, err := logger.New("mock")
+	if err != nil {
+		t.Fatalf("logger.New() error = %v", err)
+	}
+
+	svc := service.NewService(log, memory.NewSubscriptionRepo(), exchange.NewStaticRates("RUB", nil), noAlerts{}, "RUB")
+	h := NewHandler(log, svc, nil, "")
+
+	r := chi.NewRouter()
+	r.Get("/subscriptions/{id}", h.SubscriptionByID)
+	r.Post("/subscriptions", h.CreateSubscription)
+	r.Put("/subscriptions", h.UpdateSubscription)
+	r.Delete("/subscriptions/{id}", h.DeleteSubscription)
+
+	return r, svc
+}
+
+func serve(t *testing.T, h http.Handler, method, target, ifMatch, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if ifMatch != "" {
+		r.Header.Set("If-Match", ifMatch)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestSubscriptionETags(t *testing.T) {
+	h, svc := newTestRouter(t)
+
+	user := uuid.Must(uuid.NewV4())
+
+	id, _, err := svc.CreateSubscription(context.Background(), entity.Subscription{
+		ServiceName: "Okko",
+		Price:       entity.NewMoney(39900, "RUB"),
+		UserID:      user,
+		StartDate:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+
+	update := func(amount string) string {
+		return `{"id":"` + id.String() + `","service_name":"Okko","price":{"amount":"` + amount + `","currency":"RUB"},` +
+			`"user_id":"` + user.String() + `","start_date":"2026-10-01T00:00:00Z"}`
+	}
+
+	missing := uuid.Must(uuid.NewV4())
+
+	steps := []struct {
+		name     string
+		method   string
+		target   string
+		ifMatch  string
+		body     string
+		wantCode int
+		wantETag string
+	}{
+		{"get", http.MethodGet, "/subscriptions/" + id.String(), "", "", http.StatusOK, `"1"`},
+		{"put without If-Match", http.MethodPut, "/subscriptions", "", update("450"), http.StatusPreconditionRequired, ""},
+		{"put with a weak tag", http.MethodPut, "/subscriptions", `W/"1"`, update("450"), http.StatusPreconditionFailed, ""},
+		{"put", http.MethodPut, "/subscriptions", `"1"`, update("450"), http.StatusOK, `"2"`},
+		{"put with a stale version", http.MethodPut, "/subscriptions", `"1"`, update("500"), http.StatusPreconditionFailed, ""},
+		{"get after put", http.MethodGet, "/subscriptions/" + id.String(), "", "", http.StatusOK, `"2"`},
+		{"put with any version", http.MethodPut, "/subscriptions", "*", update("500"), http.StatusOK, `"3"`},
+		{"delete without If-Match", http.MethodDelete, "/subscriptions/" + id.String(), "", "", http.StatusPreconditionRequired, ""},
+		{"delete with a stale version", http.MethodDelete, "/subscriptions/" + id.String(), `"2"`, "", http.StatusPreconditionFailed, ""},
+		{"delete any version of a missing subscription", http.MethodDelete, "/subscriptions/" + missing.String(), "*", "", http.StatusPreconditionFailed, ""},
+		{"delete", http.MethodDelete, "/subscriptions/" + id.String(), `"3"`, "", http.StatusOK, ""},
+	}
+
+	for _, step := range steps {
+		w := serve(t, h, step.method, step.target, step.ifMatch, step.body)
+
+		if w.Code != step.wantCode {
+			t.Fatalf("%s: status = %d, want %d, body %q", step.name, w.Code, step.wantCode, w.Body.String())
+		}
+
+		if got := w.Header().Get("ETag"); got != step.wantETag {
+			t.Fatalf("%s: ETag = %s, want %s", step.name, got, step.wantETag)
+		}
+
+		if step.wantCode != http.StatusOK || step.method == http.MethodDelete {
+			continue
+		}
+
+		var sub entity.Subscription
+		if err := json.NewDecoder(w.Body).Decode(&sub); err != nil {
+			t.Fatalf("%s: decode body: %v", step.name, err)
+		}
+
+		if etag(sub.Version) != step.wantETag {
+			t.Fatalf("%s: body version = %d, want ETag %s", step.name, sub.Version, step.wantETag)
+		}
+	}
+}
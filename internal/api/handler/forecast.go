@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"online-subscribe-rest-service/internal/entity"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// @Summary Get spending forecast
+// @Description Прогнозирует списания по подпискам пользователя на ближайшие months месяцев начиная с завтрашнего дня: даты, суммы, итоги по месяцам и общий итог
+// @Tags Subscriptions
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Param months query int false "Forecast length in months (1-60, default 12)"
+// @Param exclude_cancelling query bool false "Leave out cancelled subscriptions still paid until their end date"
+// @Param target_currency query string false "Currency of the result (ISO 4217), defaults to the service currency"
+// @Success 200 {object} entity.Forecast
+// @Failure 400 {string} string "Invalid parameters"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/forecast [get]
+func (h *Handler) Forecast(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	params, err := parseForecastParams(r.URL.Query())
+	if err != nil {
+		h.log.ErrorF("handler: failed to get parsed params %w", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params.UserID = userID
+
+	if err := params.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	forecast, err := h.subscriptionsService.Forecast(ctx, params)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get forecast %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(forecast); err != nil {
+		h.log.ErrorF("handler: failed to encode forecast %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseForecastParams(url url.Values) (entity.ForecastParams, error) {
+	params := entity.ForecastParams{
+		Months:         entity.DefaultForecastMonths,
+		TargetCurrency: strings.ToUpper(url.Get("target_currency")),
+	}
+
+	if qMonths := url.Get("months"); qMonths != "" {
+		months, err := strconv.Atoi(qMonths)
+		if err != nil {
+			return entity.ForecastParams{}, fmt.Errorf("invalid months: %w", err)
+		}
+
+		params.Months = months
+	}
+
+	if qExclude := url.Get("exclude_cancelling"); qExclude != "" {
+		exclude, err := strconv.ParseBool(qExclude)
+		if err != nil {
+			return entity.ForecastParams{}, fmt.Errorf("invalid exclude_cancelling: %w", err)
+		}
+
+		params.ExcludeCancelling = exclude
+	}
+
+	return params, nil
+}
@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"online-subscribe-rest-service/internal/entity"
 	"online-subscribe-rest-service/pkg/logger"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -20,32 +22,89 @@ import (
 
 type SubscriptionsService interface {
 	SubscriptionByID(context.Context, uuid.UUID) (entity.Subscription, error)
-	SubscriptionsList(context.Context, uuid.UUID) ([]entity.Subscription, error)
-	CreateSubscription(context.Context, entity.Subscription) (uuid.UUID, error)
-	UpdateSubscription(context.Context, entity.Subscription) error
-	DeleteSubscription(context.Context, uuid.UUID) error
+	SubscriptionsList(ctx context.Context, params entity.SubscriptionsListParams) (entity.SubscriptionsPage, error)
+	SearchSubscriptions(ctx context.Context, filter entity.SubscriptionFilter) (entity.SubscriptionsPage, int, error)
+	CreateSubscription(context.Context, entity.Subscription) (uuid.UUID, []entity.BudgetStatus, error)
+	UpdateSubscription(context.Context, entity.Subscription) (entity.Subscription, []entity.BudgetStatus, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID, version int64) error
+	RestoreSubscription(context.Context, uuid.UUID) (entity.Subscription, error)
+	DeletedSubscriptions(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Subscription, error)
 	SubscriptionsSum(ctx context.Context, params entity.SubscriptionsSumParams) (entity.UserSubscriptionsSum, error)
+	ChangeStatus(ctx context.Context, id uuid.UUID, to entity.SubscriptionStatus, on time.Time) (entity.Subscription, error)
+	StatusHistory(ctx context.Context, id uuid.UUID) ([]entity.StatusChange, error)
+	PriceHistory(ctx context.Context, id uuid.UUID) ([]entity.PricePeriod, error)
+	CreateCatalogEntry(context.Context, entity.CatalogEntry) (uuid.UUID, error)
+	UpdateCatalogEntry(context.Context, entity.CatalogEntry) error
+	DeleteCatalogEntry(context.Context, uuid.UUID) error
+	CatalogEntryByID(context.Context, uuid.UUID) (entity.CatalogEntry, error)
+	CatalogEntries(context.Context) ([]entity.CatalogEntry, error)
+	SpendingByCategory(ctx context.Context, params entity.SpendingParams) (entity.SpendingByCategory, error)
+	MonthlyReport(ctx context.Context, params entity.MonthlyReportParams) (entity.MonthlyReport, error)
+	Forecast(ctx context.Context, params entity.ForecastParams) (entity.Forecast, error)
+	Renewals(ctx context.Context, params entity.RenewalsParams) ([]entity.Renewal, error)
+	CreateCalendarToken(ctx context.Context, userID uuid.UUID) (string, error)
+	DeleteCalendarToken(ctx context.Context, userID uuid.UUID) error
+	CalendarRenewals(ctx context.Context, token string) ([]entity.Renewal, error)
+	CreateBudget(context.Context, entity.Budget) (uuid.UUID, error)
+	UpdateBudget(context.Context, entity.Budget) error
+	DeleteBudget(context.Context, uuid.UUID) error
+	BudgetByID(context.Context, uuid.UUID) (entity.BudgetStatus, error)
+	Budgets(ctx context.Context, userID uuid.UUID) ([]entity.BudgetStatus, error)
+	NotificationPreferences(ctx context.Context, userID uuid.UUID) ([]entity.NotificationPreference, error)
+	SetNotificationPreferences(ctx context.Context, userID uuid.UUID, prefs []entity.NotificationPreference) error
+	Deliveries(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Delivery, error)
+	CreateWebhook(context.Context, entity.Webhook) (uuid.UUID, error)
+	WebhookByID(context.Context, uuid.UUID) (entity.Webhook, error)
+	Webhooks(context.Context) ([]entity.Webhook, error)
+	DeleteWebhook(context.Context, uuid.UUID) error
+	EnableWebhook(context.Context, uuid.UUID) error
+	WebhookDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]entity.WebhookDelivery, error)
+	SubscriptionHistory(ctx context.Context, id uuid.UUID, limit int, before *int64) (entity.AuditPage, error)
+	AuditLog(ctx context.Context, filter entity.AuditFilter) (entity.AuditPage, error)
+}
+
+// Jobs reports the state of the background jobs.
+type Jobs interface {
+	Jobs() []entity.JobStatus
 }
 
 type Handler struct {
 	log                  logger.Logger
 	subscriptionsService SubscriptionsService
+	jobs                 Jobs
+	// publicURL is the base of the calendar feed links, empty means the request's host.
+	publicURL string
 }
 
-func NewHandler(log logger.Logger, subscriptionsService SubscriptionsService) *Handler {
+func NewHandler(log logger.Logger, subscriptionsService SubscriptionsService, jobs Jobs, publicURL string) *Handler {
 	return &Handler{
 		log:                  log,
 		subscriptionsService: subscriptionsService,
+		jobs:                 jobs,
+		publicURL:            strings.TrimSuffix(publicURL, "/"),
 	}
 }
 
 
-// @Summary Get all subscriptions by user_id
-// @Description Возвращает список подписок по user_id
+// @Summary Get subscriptions by user_id
+// @Description Возвращает страницу подписок пользователя; следующая страница запрашивается с cursor из next_cursor
 // @Tags Subscriptions
 // @Param user_id path string true "User ID (UUID)"
-// @Success 200 {array} entity.Subscription
-// @Failure 400 {string} string "Invalid user_id"
+// @Param limit query int false "Page size (1-100, default 50)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "start_date, price or service_name, prefix with - for descending order"
+// @Param service_name query string false "Service name"
+// @Param active_on query string false "Only subscriptions active on date (YYYY-MM-DD)"
+// @Param currency query string false "Only subscriptions priced in currency (ISO 4217), required with price_min and price_max"
+// @Param price_min query string false "Minimal price in currency, e.g. 100.50"
+// @Param price_max query string false "Maximal price in currency, e.g. 999.99"
+// @Param has_end_date query bool false "Only subscriptions with (true) or without (false) end date"
+// @Param trial_on query string false "Only subscriptions in free trial on date (YYYY-MM-DD)"
+// @Param category query string false "Only subscriptions of category (streaming, music, cloud, software, gaming, news, education, fitness, other)"
+// @Param tag query []string false "Only subscriptions carrying all of the tags (comma-separated or repeated)"
+// @Param period query string false "Normalize prices to period (day, week, month, quarter, year)"
+// @Success 200 {object} entity.SubscriptionsPage
+// @Failure 400 {string} string "Invalid parameters"
 // @Failure 404 {string} string "Subscriptions not found"
 // @Failure 500 {string} string "Internal server error"
 // @Router       /users/{user_id}/subscriptions [get]
@@ -61,7 +120,22 @@ func (h *Handler) SubscriptionsList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	subscriptions, err := h.subscriptionsService.SubscriptionsList(ctx, userID)
+	params, err := parseSubscriptionsListParams(r.URL.Query())
+	if err != nil {
+		h.log.ErrorF("handler: failed to get parsed params %w", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params.UserID = userID
+
+	if err := params.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	subscriptions, err := h.subscriptionsService.SubscriptionsList(ctx, params)
 	if err != nil {
 		if errors.Is(err, entity.ErrNotFound) {
 			http.Error(w, fmt.Sprintf("subscriptions for user_id %s not found", userID), http.StatusNotFound)
@@ -84,11 +158,64 @@ func (h *Handler) SubscriptionsList(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// @Summary Search subscriptions of all users
+// @Description Ищет подписки всех пользователей по фильтрам; общее число найденных подписок возвращается в заголовке X-Total-Count
+// @Tags Subscriptions
+// @Produce json
+// @Param service_name query string false "Part of the service name, case-insensitive"
+// @Param user_id query []string false "User IDs (UUID), repeat or separate with commas" collectionFormat(csv)
+// @Param active_between query string false "Active at some point between dates: YYYY-MM-DD,YYYY-MM-DD, either side may be empty"
+// @Param currency query string false "Only subscriptions priced in currency (ISO 4217), required with price_min and price_max"
+// @Param price_min query string false "Minimal price in currency, e.g. 100.50"
+// @Param price_max query string false "Maximal price in currency, e.g. 999.99"
+// @Param limit query int false "Page size (1-100, default 50)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "start_date, price or service_name, prefix with - for descending order"
+// @Success 200 {object} entity.SubscriptionsPage
+// @Header 200 {integer} X-Total-Count "Number of matching subscriptions on all pages"
+// @Failure 400 {string} string "Invalid parameters"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /subscriptions [get]
+func (h *Handler) SearchSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := parseSubscriptionFilter(r.URL.Query())
+	if err != nil {
+		h.log.ErrorF("handler: failed to get parsed params %w", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := filter.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	page, total, err := h.subscriptionsService.SearchSubscriptions(ctx, filter)
+	if err != nil {
+		h.log.ErrorF("handler: failed to search subscriptions: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.WriteHeader(http.StatusOK)
+
+	if err = json.NewEncoder(w).Encode(page); err != nil {
+		h.log.ErrorF("handler: failed to encode subscriptions: %v", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 // @Summary Get subscription by ID
-// @Description Возвращает одну подписку по её ID
+// @Description Возвращает одну подписку по её ID; заголовок ETag содержит версию подписки, которую нужно передать в If-Match при изменении или удалении
 // @Tags Subscriptions
 // @Param id path string true "Subscription ID (UUID)"
 // @Success 200 {object} entity.Subscription
+// @Header 200 {string} ETag "Subscription version"
 // @Failure 400 {string} string "Invalid subscription ID"
 // @Failure 404 {string} string "Subscription not found"
 // @Failure 500 {string} string "Internal server error"
@@ -115,9 +242,11 @@ func (h *Handler) SubscriptionByID(w http.ResponseWriter, r *http.Request) {
 
 		h.log.ErrorF("handler: failed to get subscriptions by id %s: %v", id, err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(subscription.Version))
 	w.WriteHeader(http.StatusOK)
 
 	if err = json.NewEncoder(w).Encode(subscription); err != nil {
@@ -129,12 +258,12 @@ func (h *Handler) SubscriptionByID(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Create subscription
-// @Description Создаёт новую подписку
+// @Description Создаёт новую подписку; сервис берётся из каталога по service_id или по service_name (с учётом алиасов), неизвестное имя добавляется в каталог. Без цены подписка получает цену сервиса по умолчанию
 // @Tags Subscriptions
 // @Accept json
 // @Produce json
 // @Param subscription body entity.Subscription true "Subscription payload"
-// @Success 200 {string} string "Subscription created (ID)"
+// @Success 200 {string} string "Subscription created (ID), a Warning header names every budget it pushed over the limit"
 // @Failure 400 {string} string "Invalid request body"
 // @Failure 500 {string} string "Internal server error"
 // @Router       /subscriptions [post]
@@ -147,6 +276,7 @@ func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 	if err := json.NewDecoder(r.Body).Decode(&subscription); err != nil {
 		h.log.ErrorF("failed to decode request body to struct: %w", err)
 		http.Error(w, "failed to decode request body to struct", http.StatusBadRequest)
+		return
 	}
 
 	if err := subscription.Validate(); err != nil {
@@ -155,14 +285,22 @@ func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := h.subscriptionsService.CreateSubscription(ctx, subscription)
+	id, exceeded, err := h.subscriptionsService.CreateSubscription(ctx, subscription)
+
+	if errors.Is(err, entity.ErrInvalidSubscription) {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
 
 	if err != nil {
 		h.log.ErrorF("handler: failed to create subscription %w", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
-
+		return
 	}
 
+	budgetWarnings(w, exceeded)
+
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(http.StatusOK)
 
@@ -175,14 +313,18 @@ func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Update subscription
-// @Description Обновляет существующую подписку
+// @Description Обновляет существующую подписку, если она не менялась с версии из заголовка If-Match (иначе 412); новая цена действует с price_effective_from (по умолчанию с сегодняшнего дня), прошлые списания считаются по старой цене
 // @Tags Subscriptions
 // @Accept json
 // @Produce json
 // @Param subscription body entity.Subscription true "Subscription payload"
-// @Success 200 {object} entity.Subscription
+// @Param If-Match header string true "ETag of the subscription the change is based on, * for whatever version it is at"
+// @Success 200 {object} entity.Subscription "Updated subscription, a Warning header names every budget the change pushed over the limit"
+// @Header 200 {string} ETag "New subscription version"
 // @Failure 400 {string} string "Invalid request body"
 // @Failure 404 {string} string "Subscription not found"
+// @Failure 412 {string} string "Subscription was changed since the version in If-Match"
+// @Failure 428 {string} string "If-Match header is missing"
 // @Failure 500 {string} string "Internal server error"
 // @Router       /subscriptions [put]
 func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
@@ -197,21 +339,51 @@ func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.subscriptionsService.UpdateSubscription(ctx, subscription); err != nil {
+	if err := subscription.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	version, ok := h.ifMatch(w, r, subscription.ID)
+	if !ok {
+		return
+	}
+
+	subscription.Version = version
+
+	updated, exceeded, err := h.subscriptionsService.UpdateSubscription(ctx, subscription)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidSubscription) {
+			h.log.ErrorF("handler: incorrect params: %w", err)
+			http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+
 		if errors.Is(err, entity.ErrNotFound) {
 			h.log.ErrorF("handler: failed to update subscription %w", err)
 			http.Error(w, "handler: subscription not found", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, entity.ErrVersionMismatch) {
+			http.Error(w, "handler: subscription version mismatch", http.StatusPreconditionFailed)
+			return
 		}
 
+		h.log.ErrorF("handler: failed to update subscription %w", err)
 		http.Error(w, "handler: failed to update subscription", http.StatusInternalServerError)
 		return
 	}
 
+	budgetWarnings(w, exceeded)
+
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(updated.Version))
 	w.WriteHeader(http.StatusOK)
 
-	if err := json.NewEncoder(w).Encode(subscription); err != nil {
-		h.log.ErrorF("handler: failed to encode id %w", err)
+	if err := json.NewEncoder(w).Encode(updated); err != nil {
+		h.log.ErrorF("handler: failed to encode subscription %w", err)
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 		return
 	}
@@ -219,11 +391,15 @@ func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Delete subscription
-// @Description Удаляет подписку по её ID
+// @Description Перемещает подписку в корзину, если она не менялась с версии из заголовка If-Match (иначе 412); оттуда её можно восстановить, пока не истёк срок хранения
 // @Tags Subscriptions
 // @Param id path string true "Subscription ID (UUID)"
+// @Param If-Match header string true "ETag of the subscription, * for whatever version it is at"
 // @Success 200 {string} string "Subscription successfully deleted"
 // @Failure 400 {string} string "Invalid subscription ID"
+// @Failure 404 {string} string "Subscription not found"
+// @Failure 412 {string} string "Subscription was changed since the version in If-Match"
+// @Failure 428 {string} string "If-Match header is missing"
 // @Failure 500 {string} string "Internal server error"
 // @Router       /subscriptions/{id} [delete]
 func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
@@ -237,9 +413,26 @@ func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.subscriptionsService.DeleteSubscription(ctx, id); err != nil {
-		h.log.ErrorF("handler: failed to delete subscription", err)
+	version, ok := h.ifMatch(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptionsService.DeleteSubscription(ctx, id, version); err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			h.log.ErrorF("handler: failed to delete subscription %w", err)
+			http.Error(w, "handler: subscription not found", http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, entity.ErrVersionMismatch) {
+			http.Error(w, "handler: subscription version mismatch", http.StatusPreconditionFailed)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to delete subscription: %v", err)
 		http.Error(w, "handler: failed to delete subscription", http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "text/plain")
@@ -254,14 +447,17 @@ func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Get total subscription cost
-// @Description Подсчитывает суммарную стоимость подписок за период с фильтрами
+// @Description Подсчитывает сумму всех списаний по подпискам за период с разбивкой по месяцам
 // @Tags Subscriptions
 // @Accept json
 // @Produce json
 // @Param user_id query string true "User ID (UUID)"
-// @Param service_name query string true "Service name"
+// @Param service_name query string false "Service name or alias, required without service_id"
+// @Param service_id query string false "Catalog service ID (UUID)"
 // @Param start_date query string true "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param period query string false "Also return price per period (day, week, month, quarter, year)"
+// @Param target_currency query string false "Currency of the result (ISO 4217), defaults to the service currency"
 // @Success 200 {object} entity.UserSubscriptionsSum
 // @Failure 400 {string} string "Invalid or missing parameters"
 // @Failure 500 {string} string "Internal server error"
@@ -287,7 +483,8 @@ func (h *Handler) SubscriptionsSum(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		h.log.ErrorF("handler: failed to get subscriptions sum %w", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -303,8 +500,11 @@ func (h *Handler) SubscriptionsSum(w http.ResponseWriter, r *http.Request) {
 func parseSubscriptionsSumParams(url url.Values) (entity.SubscriptionsSumParams, error) {
 	qUserID := url.Get("user_id")
 	serviceName := url.Get("service_name")
+	qServiceID := url.Get("service_id")
 	qStartDate := url.Get("start_date")
 	qEndDate := url.Get("end_date")
+	qPeriod := url.Get("period")
+	targetCurrency := url.Get("target_currency")
 
 	userID, err := uuid.FromString(qUserID)
 	if err != nil {
@@ -317,9 +517,16 @@ func parseSubscriptionsSumParams(url url.Values) (entity.SubscriptionsSumParams,
 	}
 
 	param := entity.SubscriptionsSumParams{
-		UserID:      userID,
-		ServiceName: serviceName,
-		StartDate:   startDate,
+		UserID:         userID,
+		ServiceName:    serviceName,
+		StartDate:      startDate,
+		TargetCurrency: strings.ToUpper(targetCurrency),
+	}
+
+	if qServiceID != "" {
+		if param.ServiceID, err = uuid.FromString(qServiceID); err != nil {
+			return entity.SubscriptionsSumParams{}, fmt.Errorf("invalid service_id: %w", err)
+		}
 	}
 
 	if qEndDate != "" {
@@ -331,5 +538,212 @@ func parseSubscriptionsSumParams(url url.Values) (entity.SubscriptionsSumParams,
 		param.EndDate = &endDate
 	}
 
+	if qPeriod != "" {
+		period, err := entity.ParseBillingPeriod(qPeriod)
+		if err != nil {
+			return entity.SubscriptionsSumParams{}, fmt.Errorf("invalid period: %w", err)
+		}
+
+		param.Period = &period
+	}
+
 	return param, nil
 }
+
+func parseSubscriptionsListParams(url url.Values) (entity.SubscriptionsListParams, error) {
+	params := entity.SubscriptionsListParams{
+		Sort:        entity.SortStartDate,
+		Limit:       entity.DefaultPageLimit,
+		ServiceName: url.Get("service_name"),
+	}
+
+	if qLimit := url.Get("limit"); qLimit != "" {
+		limit, err := strconv.Atoi(qLimit)
+		if err != nil {
+			return entity.SubscriptionsListParams{}, fmt.Errorf("invalid limit: %w", err)
+		}
+
+		params.Limit = limit
+	}
+
+	if qSort := url.Get("sort"); qSort != "" {
+		sort, desc, err := entity.ParseSubscriptionSort(qSort)
+		if err != nil {
+			return entity.SubscriptionsListParams{}, fmt.Errorf("invalid sort: %w", err)
+		}
+
+		params.Sort, params.Desc = sort, desc
+	}
+
+	if qCursor := url.Get("cursor"); qCursor != "" {
+		cursor, err := entity.DecodeListCursor(qCursor)
+		if err != nil {
+			return entity.SubscriptionsListParams{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+
+		params.Cursor = &cursor
+	}
+
+	if qActiveOn := url.Get("active_on"); qActiveOn != "" {
+		activeOn, err := time.Parse(time.DateOnly, qActiveOn)
+		if err != nil {
+			return entity.SubscriptionsListParams{}, fmt.Errorf("invalid active_on: %w", err)
+		}
+
+		params.ActiveOn = &activeOn
+	}
+
+	params.Currency = url.Get("currency")
+
+	// Prices are parsed in the currency's minor units, Validate rejects them without one.
+	if qPriceMin := url.Get("price_min"); qPriceMin != "" {
+		priceMin, err := entity.ParseMoney(qPriceMin, params.Currency)
+		if err != nil {
+			return entity.SubscriptionsListParams{}, fmt.Errorf("invalid price_min: %w", err)
+		}
+
+		params.PriceMin = &priceMin.Amount
+	}
+
+	if qPriceMax := url.Get("price_max"); qPriceMax != "" {
+		priceMax, err := entity.ParseMoney(qPriceMax, params.Currency)
+		if err != nil {
+			return entity.SubscriptionsListParams{}, fmt.Errorf("invalid price_max: %w", err)
+		}
+
+		params.PriceMax = &priceMax.Amount
+	}
+
+	if qHasEndDate := url.Get("has_end_date"); qHasEndDate != "" {
+		hasEndDate, err := strconv.ParseBool(qHasEndDate)
+		if err != nil {
+			return entity.SubscriptionsListParams{}, fmt.Errorf("invalid has_end_date: %w", err)
+		}
+
+		params.HasEndDate = &hasEndDate
+	}
+
+	if qTrialOn := url.Get("trial_on"); qTrialOn != "" {
+		trialOn, err := time.Parse(time.DateOnly, qTrialOn)
+		if err != nil {
+			return entity.SubscriptionsListParams{}, fmt.Errorf("invalid trial_on: %w", err)
+		}
+
+		params.TrialOn = &trialOn
+	}
+
+	params.Category = entity.Category(url.Get("category"))
+
+	for _, qTags := range url["tag"] {
+		for _, tag := range strings.Split(qTags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				params.Tags = append(params.Tags, tag)
+			}
+		}
+	}
+
+	if qPeriod := url.Get("period"); qPeriod != "" {
+		period, err := entity.ParseBillingPeriod(qPeriod)
+		if err != nil {
+			return entity.SubscriptionsListParams{}, fmt.Errorf("invalid period: %w", err)
+		}
+
+		params.Period = &period
+	}
+
+	return params, nil
+}
+
+func parseSubscriptionFilter(url url.Values) (entity.SubscriptionFilter, error) {
+	filter := entity.SubscriptionFilter{
+		Sort:        entity.SortStartDate,
+		Limit:       entity.DefaultPageLimit,
+		ServiceName: url.Get("service_name"),
+	}
+
+	if qLimit := url.Get("limit"); qLimit != "" {
+		limit, err := strconv.Atoi(qLimit)
+		if err != nil {
+			return entity.SubscriptionFilter{}, fmt.Errorf("invalid limit: %w", err)
+		}
+
+		filter.Limit = limit
+	}
+
+	if qSort := url.Get("sort"); qSort != "" {
+		sort, desc, err := entity.ParseSubscriptionSort(qSort)
+		if err != nil {
+			return entity.SubscriptionFilter{}, fmt.Errorf("invalid sort: %w", err)
+		}
+
+		filter.Sort, filter.Desc = sort, desc
+	}
+
+	if qCursor := url.Get("cursor"); qCursor != "" {
+		cursor, err := entity.DecodeListCursor(qCursor)
+		if err != nil {
+			return entity.SubscriptionFilter{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+
+		filter.Cursor = &cursor
+	}
+
+	for _, qUserIDs := range url["user_id"] {
+		for _, qUserID := range strings.Split(qUserIDs, ",") {
+			userID, err := uuid.FromString(strings.TrimSpace(qUserID))
+			if err != nil {
+				return entity.SubscriptionFilter{}, fmt.Errorf("invalid user_id: %w", err)
+			}
+
+			filter.UserIDs = append(filter.UserIDs, userID)
+		}
+	}
+
+	if qActiveBetween := url.Get("active_between"); qActiveBetween != "" {
+		qFrom, qTo, ok := strings.Cut(qActiveBetween, ",")
+		if !ok {
+			return entity.SubscriptionFilter{}, errors.New("invalid active_between: expected from,to")
+		}
+
+		if qFrom != "" {
+			from, err := time.Parse(time.DateOnly, qFrom)
+			if err != nil {
+				return entity.SubscriptionFilter{}, fmt.Errorf("invalid active_between: %w", err)
+			}
+
+			filter.ActiveFrom = &from
+		}
+
+		if qTo != "" {
+			to, err := time.Parse(time.DateOnly, qTo)
+			if err != nil {
+				return entity.SubscriptionFilter{}, fmt.Errorf("invalid active_between: %w", err)
+			}
+
+			filter.ActiveTo = &to
+		}
+	}
+
+	filter.Currency = url.Get("currency")
+
+	// Prices are parsed in the currency's minor units, Validate rejects them without one.
+	if qPriceMin := url.Get("price_min"); qPriceMin != "" {
+		priceMin, err := entity.ParseMoney(qPriceMin, filter.Currency)
+		if err != nil {
+			return entity.SubscriptionFilter{}, fmt.Errorf("invalid price_min: %w", err)
+		}
+
+		filter.PriceMin = &priceMin.Amount
+	}
+
+	if qPriceMax := url.Get("price_max"); qPriceMax != "" {
+		priceMax, err := entity.ParseMoney(qPriceMax, filter.Currency)
+		if err != nil {
+			return entity.SubscriptionFilter{}, fmt.Errorf("invalid price_max: %w", err)
+		}
+
+		filter.PriceMax = &priceMax.Amount
+	}
+
+	return filter, nil
+}
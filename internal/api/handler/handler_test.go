@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/logger"
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// sumService answers every subscription sum with err, or with an empty sum when err is nil.
+type sumService struct {
+	SubscriptionsService
+	err error
+}
+
+func (s sumService) SubscriptionsSum(context.Context, entity.SubscriptionsSumParams) (entity.UserSubscriptionsSum, error) {
+	return entity.UserSubscriptionsSum{}, s.err
+}
+
+func TestSubscriptionsSumStatus(t *testing.T) {
+	log, err := logger.New("mock")
+	if err != nil {
+		t.Fatalf("logger.New() error = %v", err)
+	}
+
+	user := uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name     string
+		query    string
+		err      error
+		wantCode int
+	}{
+		{"invalid user", "?user_id=1&service_name=Okko&start_date=2026-01-01", nil, http.StatusBadRequest},
+		{"no service", "?user_id=" + user.String() + "&start_date=2026-01-01", nil, http.StatusBadRequest},
+		{"storage failure", "?user_id=" + user.String() + "&service_name=Okko&start_date=2026-01-01", errors.New("connection refused"), http.StatusInternalServerError},
+		{"ok", "?user_id=" + user.String() + "&service_name=Okko&start_date=2026-01-01", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(log, sumService{err: tt.err}, nil, "")
+
+			w := httptest.NewRecorder()
+			h.SubscriptionsSum(w, httptest.NewRequest(http.MethodGet, "/subscriptions/sum"+tt.query, nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d, body %q", w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// @Summary Get background jobs
+// @Description Возвращает фоновые задачи этой реплики: расписание, следующий запуск и итог последнего запуска (succeeded, failed или skipped, если задачу выполняла другая реплика)
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} entity.JobStatus
+// @Failure 500 {string} string "Internal server error"
+// @Router       /admin/jobs [get]
+func (h *Handler) Jobs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(h.jobs.Jobs()); err != nil {
+		h.log.ErrorF("handler: failed to encode jobs %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// @Summary Get notification preferences
+// @Description Возвращает, куда и о каких событиях (renewal, trial_ending, budget_exceeded) уведомлять пользователя
+// @Tags Notifications
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Success 200 {array} entity.NotificationPreference
+// @Failure 400 {string} string "Invalid user ID"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/notifications [get]
+func (h *Handler) NotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.subscriptionsService.NotificationPreferences(ctx, userID)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get notification preferences %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(prefs); err != nil {
+		h.log.ErrorF("handler: failed to encode notification preferences %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Set notification preferences
+// @Description Заменяет настройки уведомлений пользователя: канал (email или webhook), адрес и события; пустой список событий — все события, пустой массив отключает уведомления
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Param preferences body []entity.NotificationPreference true "Notification preferences"
+// @Success 200 {array} entity.NotificationPreference
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/notifications [put]
+func (h *Handler) SetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	var prefs []entity.NotificationPreference
+	if err := json.NewDecoder(r.Body).Decode(&prefs); err != nil {
+		h.log.ErrorF("failed to decode request body to struct: %w", err)
+		http.Error(w, "handler: failed to decode request body to struct", http.StatusBadRequest)
+		return
+	}
+
+	if err := entity.ValidatePreferences(prefs); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionsService.SetNotificationPreferences(ctx, userID, prefs); err != nil {
+		h.log.ErrorF("handler: failed to set notification preferences %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.NotificationPreferences(w, r)
+}
+
+// @Summary Get notification deliveries
+// @Description Журнал отправленных пользователю уведомлений, от новых к старым: статус (pending, sent, failed), число попыток и последняя ошибка
+// @Tags Notifications
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Param limit query int false "Number of deliveries (1-100, default 50)"
+// @Success 200 {array} entity.Delivery
+// @Failure 400 {string} string "Invalid parameters"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/notifications/deliveries [get]
+func (h *Handler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	limit := entity.DefaultPageLimit
+	if qLimit := r.URL.Query().Get("limit"); qLimit != "" {
+		limit, err = strconv.Atoi(qLimit)
+		if err != nil || limit < 1 || limit > entity.MaxDeliveries {
+			http.Error(w, fmt.Sprintf("handler: incorrect params: limit must be between 1 and %d", entity.MaxDeliveries), http.StatusBadRequest)
+			return
+		}
+	}
+
+	deliveries, err := h.subscriptionsService.Deliveries(ctx, userID, limit)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get deliveries %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(deliveries); err != nil {
+		h.log.ErrorF("handler: failed to encode deliveries %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// @Summary Get subscription price history
+// @Description Возвращает историю цен подписки: каждая цена действует с effective_from до следующей записи
+// @Tags Subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {array} entity.PricePeriod
+// @Failure 400 {string} string "Invalid subscription ID"
+// @Failure 404 {string} string "Subscription not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /subscriptions/{id}/prices [get]
+func (h *Handler) PriceHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid subscription id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.subscriptionsService.PriceHistory(ctx, id)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("subscription by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to get price history of subscription %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		h.log.ErrorF("handler: failed to encode price history %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
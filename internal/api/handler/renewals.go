@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/ical"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	// defaultRenewalsMonths is how far ahead renewals are listed when the query has no end date.
+	defaultRenewalsMonths = 3
+
+	calendarProdID = "-//online-subscribe-rest-service//renewals//EN"
+	calendarDomain = "online-subscribe-rest-service"
+)
+
+// @Summary Get subscription renewals
+// @Description Возвращает даты списаний по подпискам пользователя в диапазоне from-to (включительно) с ценой на каждую дату, по порядку дат
+// @Tags Subscriptions
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Param from query string false "First day (YYYY-MM-DD), defaults to today"
+// @Param to query string false "Last day (YYYY-MM-DD), defaults to 3 months after from"
+// @Success 200 {array} entity.Renewal
+// @Failure 400 {string} string "Invalid parameters"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/renewals [get]
+func (h *Handler) Renewals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	params, err := parseRenewalsParams(r.URL.Query(), time.Now())
+	if err != nil {
+		h.log.ErrorF("handler: failed to get parsed params %w", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params.UserID = userID
+
+	if err := params.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	renewals, err := h.subscriptionsService.Renewals(ctx, params)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get renewals %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(renewals); err != nil {
+		h.log.ErrorF("handler: failed to encode renewals %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Create renewals calendar feed
+// @Description Создаёт секретную ссылку на календарь списаний пользователя в формате iCalendar для подписки в Google Calendar или Outlook; прежняя ссылка перестаёт работать. Ссылка показывается только один раз
+// @Tags Subscriptions
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Success 201 {object} entity.CalendarFeed
+// @Failure 400 {string} string "Invalid user ID"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/calendar [post]
+func (h *Handler) CreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.subscriptionsService.CreateCalendarToken(ctx, userID)
+	if err != nil {
+		h.log.ErrorF("handler: failed to create calendar feed of user %s: %w", userID, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	feed := entity.CalendarFeed{
+		URL: fmt.Sprintf("%s/calendar/%s.ics", h.baseURL(r), token),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(feed); err != nil {
+		h.log.ErrorF("handler: failed to encode calendar feed %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Delete renewals calendar feed
+// @Description Отключает календарь списаний пользователя, его ссылка перестаёт работать
+// @Tags Subscriptions
+// @Param user_id path string true "User ID (UUID)"
+// @Success 204
+// @Failure 400 {string} string "Invalid user ID"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/calendar [delete]
+func (h *Handler) DeleteCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionsService.DeleteCalendarToken(ctx, userID); err != nil {
+		h.log.ErrorF("handler: failed to delete calendar feed of user %s: %w", userID, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get renewals calendar
+// @Description Календарь списаний в формате iCalendar (RFC 5545): одно событие на весь день на каждое списание, цена в описании. Охватывает прошлый месяц и год вперёд
+// @Tags Subscriptions
+// @Produce text/calendar
+// @Param token path string true "Secret token of the feed"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 404 {string} string "Calendar not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /calendar/{token}.ics [get]
+func (h *Handler) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	renewals, err := h.subscriptionsService.CalendarRenewals(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, "calendar not found", http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to get calendar renewals %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	cal := ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   "Subscription renewals",
+		Events: make([]ical.Event, 0, len(renewals)),
+	}
+
+	for _, renewal := range renewals {
+		cal.Events = append(cal.Events, ical.Event{
+			UID:         fmt.Sprintf("%s-%s@%s", renewal.SubscriptionID, renewal.Date.Format("20060102"), calendarDomain),
+			Date:        renewal.Date,
+			Summary:     fmt.Sprintf("%s renewal", renewal.ServiceName),
+			Description: fmt.Sprintf("%s renewal: %s %s", renewal.ServiceName, renewal.Price, renewal.Price.Currency),
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if err := ical.Write(w, cal, time.Now()); err != nil {
+		h.log.ErrorF("handler: failed to write calendar %w", err)
+		return
+	}
+}
+
+// baseURL is the address calendar links point at, the configured public URL or the request's host.
+func (h *Handler) baseURL(r *http.Request) string {
+	if h.publicURL != "" {
+		return h.publicURL
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host
+}
+
+func parseRenewalsParams(url url.Values, now time.Time) (entity.RenewalsParams, error) {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if qFrom := url.Get("from"); qFrom != "" {
+		parsed, err := time.Parse(time.DateOnly, qFrom)
+		if err != nil {
+			return entity.RenewalsParams{}, fmt.Errorf("invalid from: %w", err)
+		}
+
+		from = parsed
+	}
+
+	to := from.AddDate(0, defaultRenewalsMonths, 0)
+	if qTo := url.Get("to"); qTo != "" {
+		parsed, err := time.Parse(time.DateOnly, qTo)
+		if err != nil {
+			return entity.RenewalsParams{}, fmt.Errorf("invalid to: %w", err)
+		}
+
+		to = parsed
+	}
+
+	return entity.RenewalsParams{From: from, To: to}, nil
+}
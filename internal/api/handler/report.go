@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"online-subscribe-rest-service/internal/entity"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+const monthLayout = "2006-01"
+
+// @Summary Get monthly spending report
+// @Description Возвращает траты пользователя по месяцам с разбивкой по сервисам: строки (month, service_name, amount) и итоги по месяцам, сервисам и за весь период. Списания считаются так же, как в /subscriptions/sum
+// @Tags Subscriptions
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Param from query string true "First month (YYYY-MM)"
+// @Param to query string false "Last month (YYYY-MM), defaults to the current month"
+// @Param target_currency query string false "Currency of the result (ISO 4217), defaults to the service currency"
+// @Success 200 {object} entity.MonthlyReport
+// @Failure 400 {string} string "Invalid or missing parameters"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/reports/monthly [get]
+func (h *Handler) MonthlyReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	params, err := parseMonthlyReportParams(r.URL.Query())
+	if err != nil {
+		h.log.ErrorF("handler: failed to get parsed params %w", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params.UserID = userID
+
+	if err := params.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.subscriptionsService.MonthlyReport(ctx, params)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get monthly report %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.log.ErrorF("handler: failed to encode monthly report %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseMonthlyReportParams(url url.Values) (entity.MonthlyReportParams, error) {
+	from, err := time.Parse(monthLayout, url.Get("from"))
+	if err != nil {
+		return entity.MonthlyReportParams{}, fmt.Errorf("invalid from: %w", err)
+	}
+
+	params := entity.MonthlyReportParams{
+		From:           from,
+		TargetCurrency: strings.ToUpper(url.Get("target_currency")),
+	}
+
+	if qTo := url.Get("to"); qTo != "" {
+		to, err := time.Parse(monthLayout, qTo)
+		if err != nil {
+			return entity.MonthlyReportParams{}, fmt.Errorf("invalid to: %w", err)
+		}
+
+		params.To = &to
+	}
+
+	return params, nil
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"online-subscribe-rest-service/internal/entity"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// @Summary Get spending by category and tag
+// @Description Считает списания по всем подпискам пользователя за период так же, как /subscriptions/sum, и разбивает их по категориям и тегам (по убыванию суммы). Подписка с несколькими тегами учитывается в каждом из них
+// @Tags Subscriptions
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param target_currency query string false "Currency of the result (ISO 4217), defaults to the service currency"
+// @Success 200 {object} entity.SpendingByCategory
+// @Failure 400 {string} string "Invalid or missing parameters"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/spending/by-category [get]
+func (h *Handler) SpendingByCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	params, err := parseSpendingParams(r.URL.Query())
+	if err != nil {
+		h.log.ErrorF("handler: failed to get parsed params %w", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params.UserID = userID
+
+	if err := params.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	spending, err := h.subscriptionsService.SpendingByCategory(ctx, params)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get spending by category %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(spending); err != nil {
+		h.log.ErrorF("handler: failed to encode spending %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseSpendingParams(url url.Values) (entity.SpendingParams, error) {
+	startDate, err := time.Parse(time.DateOnly, url.Get("start_date"))
+	if err != nil {
+		return entity.SpendingParams{}, fmt.Errorf("invalid start_date: %w", err)
+	}
+
+	params := entity.SpendingParams{
+		StartDate:      startDate,
+		TargetCurrency: strings.ToUpper(url.Get("target_currency")),
+	}
+
+	if qEndDate := url.Get("end_date"); qEndDate != "" {
+		endDate, err := time.Parse(time.DateOnly, qEndDate)
+		if err != nil {
+			return entity.SpendingParams{}, fmt.Errorf("invalid end_date: %w", err)
+		}
+
+		params.EndDate = &endDate
+	}
+
+	return params, nil
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// @Summary Pause subscription
+// @Description Приостанавливает активную подписку; списания на время паузы не учитываются в сумме
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param request body entity.StatusChangeRequest false "Date the pause starts on, today by default"
+// @Success 200 {object} entity.Subscription
+// @Failure 400 {string} string "Invalid subscription ID or request body"
+// @Failure 404 {string} string "Subscription not found"
+// @Failure 409 {string} string "Subscription can't be paused"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /subscriptions/{id}/pause [post]
+func (h *Handler) PauseSubscription(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, entity.StatusPaused)
+}
+
+// @Summary Resume subscription
+// @Description Возобновляет приостановленную подписку
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param request body entity.StatusChangeRequest false "Date the subscription resumes on, today by default"
+// @Success 200 {object} entity.Subscription
+// @Failure 400 {string} string "Invalid subscription ID or request body"
+// @Failure 404 {string} string "Subscription not found"
+// @Failure 409 {string} string "Subscription can't be resumed"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /subscriptions/{id}/resume [post]
+func (h *Handler) ResumeSubscription(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, entity.StatusActive)
+}
+
+// @Summary Cancel subscription
+// @Description Отменяет подписку; активная подписка остаётся оплаченной до конца текущего периода, её end_date переносится на последний оплаченный день
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param request body entity.StatusChangeRequest false "Date of the cancellation, today by default"
+// @Success 200 {object} entity.Subscription
+// @Failure 400 {string} string "Invalid subscription ID or request body"
+// @Failure 404 {string} string "Subscription not found"
+// @Failure 409 {string} string "Subscription can't be cancelled"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /subscriptions/{id}/cancel [post]
+func (h *Handler) CancelSubscription(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, entity.StatusCancelled)
+}
+
+func (h *Handler) changeStatus(w http.ResponseWriter, r *http.Request, to entity.SubscriptionStatus) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid subscription id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional, an empty one means the change takes effect today.
+	var req entity.StatusChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.log.ErrorF("failed to decode request body to struct: %w", err)
+		http.Error(w, "handler: failed to decode request body to struct", http.StatusBadRequest)
+		return
+	}
+
+	on := time.Now()
+	if req.Date != nil {
+		on = *req.Date
+	}
+
+	subscription, err := h.subscriptionsService.ChangeStatus(ctx, id, to, on)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrNotFound):
+			http.Error(w, fmt.Sprintf("subscription by id %s not found", id), http.StatusNotFound)
+		case errors.Is(err, entity.ErrInvalidTransition):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			h.log.ErrorF("handler: failed to change status of subscription %s: %v", id, err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(subscription); err != nil {
+		h.log.ErrorF("handler: failed to encode subscription %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Get subscription status history
+// @Description Возвращает историю смены статусов подписки, от старых к новым
+// @Tags Subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {array} entity.StatusChange
+// @Failure 400 {string} string "Invalid subscription ID"
+// @Failure 404 {string} string "Subscription not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /subscriptions/{id}/status-history [get]
+func (h *Handler) StatusHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid subscription id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.subscriptionsService.StatusHistory(ctx, id)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("subscription by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to get status history of subscription %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		h.log.ErrorF("handler: failed to encode status history %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
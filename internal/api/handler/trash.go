@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// @Summary Restore subscription
+// @Description Возвращает удалённую подписку из корзины; потребители событий получают subscription.restored
+// @Tags Subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {object} entity.Subscription
+// @Failure 400 {string} string "Invalid subscription ID"
+// @Failure 404 {string} string "Subscription not found in the trash"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /subscriptions/{id}/restore [post]
+func (h *Handler) RestoreSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qID := chi.URLParam(r, "id")
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid subscription id: %s", qID), http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.subscriptionsService.RestoreSubscription(ctx, id)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, "handler: subscription not found in the trash", http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to restore subscription %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(sub); err != nil {
+		h.log.ErrorF("handler: failed to encode subscription %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Get deleted subscriptions
+// @Description Корзина пользователя: удалённые подписки, от недавно удалённых к давним, с временем удаления deleted_at. Подписки хранятся в корзине, пока задача purge не удалит их по истечении срока хранения
+// @Tags Subscriptions
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Param limit query int false "Number of subscriptions (1-100, default 50)"
+// @Success 200 {array} entity.Subscription
+// @Failure 400 {string} string "Invalid parameters"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /users/{user_id}/subscriptions/trash [get]
+func (h *Handler) DeletedSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	qUserID := chi.URLParam(r, "user_id")
+
+	userID, err := uuid.FromString(qUserID)
+	if err != nil {
+		h.log.ErrorF("invalid user_id %s: %w", qUserID, err)
+		http.Error(w, fmt.Sprintf("invalid user_id: %s", qUserID), http.StatusBadRequest)
+		return
+	}
+
+	limit := entity.DefaultPageLimit
+	if qLimit := r.URL.Query().Get("limit"); qLimit != "" {
+		limit, err = strconv.Atoi(qLimit)
+		if err != nil || limit < 1 || limit > entity.MaxPageLimit {
+			http.Error(w, fmt.Sprintf("handler: incorrect params: limit must be between 1 and %d", entity.MaxPageLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	subs, err := h.subscriptionsService.DeletedSubscriptions(ctx, userID, limit)
+	if err != nil {
+		h.log.ErrorF("handler: failed to get deleted subscriptions %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(subs); err != nil {
+		h.log.ErrorF("handler: failed to encode subscriptions %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
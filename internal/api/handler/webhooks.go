@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid/v5"
+)
+
+// @Summary Create webhook
+// @Description Регистрирует адрес, на который POST-запросом отправляются события подписок; events — какие события слать (все, если пусто), user_id — обязательный пользователь, события чьих подписок отправляются. Адреса localhost, loopback, частных и link-local сетей не принимаются. Тело запроса подписывается заголовком X-Signature: sha256=<hex HMAC-SHA256 тела с ключом secret>
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body entity.Webhook true "Webhook payload"
+// @Success 201 {string} string "Webhook created (ID)"
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /webhooks [post]
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var webhook entity.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		h.log.ErrorF("failed to decode request body to struct: %w", err)
+		http.Error(w, "handler: failed to decode request body to struct", http.StatusBadRequest)
+		return
+	}
+
+	if err := webhook.Validate(); err != nil {
+		h.log.ErrorF("handler: incorrect params: %w", err)
+		http.Error(w, fmt.Sprintf("handler: incorrect params: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.subscriptionsService.CreateWebhook(ctx, webhook)
+	if err != nil {
+		h.log.ErrorF("handler: failed to create webhook %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.log.ErrorF("handler: failed to encode id %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary List webhooks
+// @Description Возвращает все вебхуки без секретов; enabled=false у вебхука, отключённого после серии неудачных доставок
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {array} entity.Webhook
+// @Failure 500 {string} string "Internal server error"
+// @Router       /webhooks [get]
+func (h *Handler) Webhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.subscriptionsService.Webhooks(r.Context())
+	if err != nil {
+		h.log.ErrorF("handler: failed to get webhooks %w", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(webhooks); err != nil {
+		h.log.ErrorF("handler: failed to encode webhooks %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Get webhook
+// @Description Возвращает вебхук без секрета: включён ли он и сколько доставок подряд не удалось
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 200 {object} entity.Webhook
+// @Failure 400 {string} string "Invalid webhook ID"
+// @Failure 404 {string} string "Webhook not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /webhooks/{id} [get]
+func (h *Handler) WebhookByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.webhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.subscriptionsService.WebhookByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("webhook by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to get webhook %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(webhook); err != nil {
+		h.log.ErrorF("handler: failed to encode webhook %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary Delete webhook
+// @Description Удаляет вебхук вместе с журналом его доставок
+// @Tags Webhooks
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 204
+// @Failure 400 {string} string "Invalid webhook ID"
+// @Failure 404 {string} string "Webhook not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /webhooks/{id} [delete]
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.webhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptionsService.DeleteWebhook(r.Context(), id); err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("webhook by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to delete webhook %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Enable webhook
+// @Description Снова включает вебхук, отключённый после серии неудачных доставок, и сбрасывает счётчик неудач; ожидавшие доставки уходят заново
+// @Tags Webhooks
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 204
+// @Failure 400 {string} string "Invalid webhook ID"
+// @Failure 404 {string} string "Webhook not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /webhooks/{id}/enable [post]
+func (h *Handler) EnableWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.webhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptionsService.EnableWebhook(r.Context(), id); err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("webhook by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to enable webhook %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get webhook deliveries
+// @Description Журнал доставок вебхука, от новых к старым: событие, статус (pending, sent, failed), число попыток, HTTP-статус ответа, последняя ошибка и время следующей попытки
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Param limit query int false "Number of deliveries (1-100, default 50)"
+// @Success 200 {array} entity.WebhookDelivery
+// @Failure 400 {string} string "Invalid parameters"
+// @Failure 404 {string} string "Webhook not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *Handler) WebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.webhookID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := entity.DefaultPageLimit
+	if qLimit := r.URL.Query().Get("limit"); qLimit != "" {
+		var err error
+
+		limit, err = strconv.Atoi(qLimit)
+		if err != nil || limit < 1 || limit > entity.MaxDeliveries {
+			http.Error(w, fmt.Sprintf("handler: incorrect params: limit must be between 1 and %d", entity.MaxDeliveries), http.StatusBadRequest)
+			return
+		}
+	}
+
+	deliveries, err := h.subscriptionsService.WebhookDeliveries(r.Context(), id, limit)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("webhook by id %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		h.log.ErrorF("handler: failed to get deliveries of webhook %s: %v", id, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(deliveries); err != nil {
+		h.log.ErrorF("handler: failed to encode webhook deliveries %w", err)
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// webhookID parses the webhook ID of the path, answering 400 when it isn't one.
+func (h *Handler) webhookID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	qID := chi.URLParam(r, "id")
+
+	id, err := uuid.FromString(qID)
+	if err != nil {
+		h.log.ErrorF("invalid id %s: %w", qID, err)
+		http.Error(w, fmt.Sprintf("invalid webhook id: %s", qID), http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
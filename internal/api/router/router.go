@@ -12,12 +12,52 @@ import (
 func NewRouter(h *handler.Handler) http.Handler {
 	r := chi.NewRouter()
 
+	r.Use(handler.Actor)
+
 	r.Get("/subscriptions/{user_id}/list", h.SubscriptionsList)
+	r.Get("/subscriptions", h.SearchSubscriptions)
 	r.Get("/subscriptions/{id}", h.SubscriptionByID)
 	r.Post("/subscriptions", h.CreateSubscription)
+	// PUT replaces the whole subscription, partial updates with PATCH are out of scope and get 405.
 	r.Put("/subscriptions", h.UpdateSubscription)
 	r.Delete("/subscriptions/{id}", h.DeleteSubscription)
+	r.Post("/subscriptions/{id}/pause", h.PauseSubscription)
+	r.Post("/subscriptions/{id}/resume", h.ResumeSubscription)
+	r.Post("/subscriptions/{id}/cancel", h.CancelSubscription)
+	r.Get("/subscriptions/{id}/status-history", h.StatusHistory)
+	r.Get("/subscriptions/{id}/prices", h.PriceHistory)
+	r.Get("/subscriptions/{id}/history", h.SubscriptionHistory)
+	r.Post("/subscriptions/{id}/restore", h.RestoreSubscription)
 	r.Get("/subscriptions/sum", h.SubscriptionsSum)
+	r.Get("/users/{user_id}/spending/by-category", h.SpendingByCategory)
+	r.Get("/users/{user_id}/reports/monthly", h.MonthlyReport)
+	r.Get("/users/{user_id}/subscriptions/trash", h.DeletedSubscriptions)
+	r.Get("/users/{user_id}/forecast", h.Forecast)
+	r.Get("/users/{user_id}/renewals", h.Renewals)
+	r.Post("/users/{user_id}/calendar", h.CreateCalendarFeed)
+	r.Delete("/users/{user_id}/calendar", h.DeleteCalendarFeed)
+	r.Get("/calendar/{token}.ics", h.CalendarFeed)
+	r.Get("/users/{user_id}/budgets", h.Budgets)
+	r.Post("/budgets", h.CreateBudget)
+	r.Get("/budgets/{id}", h.BudgetByID)
+	r.Put("/budgets/{id}", h.UpdateBudget)
+	r.Delete("/budgets/{id}", h.DeleteBudget)
+	r.Get("/users/{user_id}/notifications", h.NotificationPreferences)
+	r.Put("/users/{user_id}/notifications", h.SetNotificationPreferences)
+	r.Get("/users/{user_id}/notifications/deliveries", h.Deliveries)
+	r.Post("/services", h.CreateCatalogEntry)
+	r.Get("/services", h.CatalogEntries)
+	r.Get("/services/{id}", h.CatalogEntryByID)
+	r.Put("/services/{id}", h.UpdateCatalogEntry)
+	r.Delete("/services/{id}", h.DeleteCatalogEntry)
+	r.Post("/webhooks", h.CreateWebhook)
+	r.Get("/webhooks", h.Webhooks)
+	r.Get("/webhooks/{id}", h.WebhookByID)
+	r.Delete("/webhooks/{id}", h.DeleteWebhook)
+	r.Post("/webhooks/{id}/enable", h.EnableWebhook)
+	r.Get("/webhooks/{id}/deliveries", h.WebhookDeliveries)
+	r.Get("/admin/jobs", h.Jobs)
+	r.Get("/admin/audit", h.AuditLog)
 	r.Get("/swagger/*", httpSwagger.Handler())
 	return r
 }
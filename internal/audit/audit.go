@@ -0,0 +1,83 @@
+// Package audit carries who makes a change through the context and works out what the change was,
+// for the audit log.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"online-subscribe-rest-service/internal/entity"
+)
+
+// System is the actor of the changes nobody in particular asked for.
+const System = "system"
+
+// JobPrefix starts the actor of the changes a background job makes, job:<name>.
+const JobPrefix = "job:"
+
+type actorKey struct{}
+
+type actor struct {
+	name      string
+	requestID string
+}
+
+// WithActor returns a context whose changes are recorded as made by the actor in the request.
+func WithActor(ctx context.Context, name, requestID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor{name: name, requestID: requestID})
+}
+
+// Actor returns the actor and the request ID of the context, System and no request without them.
+func Actor(ctx context.Context) (name, requestID string) {
+	a, ok := ctx.Value(actorKey{}).(actor)
+	if !ok {
+		return System, ""
+	}
+
+	return a.name, a.requestID
+}
+
+// Diff compares the JSON forms of the values and returns the fields that differ. A nil value has no
+// fields, so a created entity has every field changed from nothing.
+func Diff(before, after any) (map[string]entity.FieldChange, error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]entity.FieldChange)
+
+	for name, value := range beforeFields {
+		if other, ok := afterFields[name]; !ok || !bytes.Equal(value, other) {
+			changes[name] = entity.FieldChange{Before: value, After: other}
+		}
+	}
+
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = entity.FieldChange{After: value}
+		}
+	}
+
+	return changes, nil
+}
+
+// fields splits the JSON object of the value into its fields, json.Marshal keeps them compact.
+func fields(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// MaxAuditEntries bounds the audit log page.
+const MaxAuditEntries = 100
+
+// AuditAction is the kind of change an audit entry records.
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	AuditPurge   AuditAction = "purge"
+)
+
+// AuditActions lists every kind of change.
+var AuditActions = []AuditAction{AuditCreate, AuditUpdate, AuditDelete, AuditRestore, AuditPurge}
+
+// AuditEntitySubscription is the entity type of the subscriptions' audit entries.
+const AuditEntitySubscription = "subscription"
+
+// AuditEntry records one change of an entity. Entries are only ever added, IDs grow in the order
+// the changes were made.
+type AuditEntry struct {
+	ID         int64     `json:"id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// Actor is who made the change: the X-Actor header of the request, as claimed by the caller and
+	// not verified, or the background job.
+	Actor string `json:"actor"`
+	// RequestID ties the entries of one API request together.
+	RequestID  string      `json:"request_id,omitempty"`
+	Action     AuditAction `json:"action"`
+	EntityType string      `json:"entity_type"`
+	EntityID   uuid.UUID   `json:"entity_id"`
+	// Changes maps the JSON fields of the entity that changed to their values before and after.
+	Changes map[string]FieldChange `json:"changes"`
+}
+
+// FieldChange is the value of a field before and after a change, a side is missing when the field
+// had no value.
+type FieldChange struct {
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After  json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+}
+
+// AuditFilter selects audit entries, newest first. Empty fields match everything.
+type AuditFilter struct {
+	EntityType string
+	EntityID   *uuid.UUID
+	Actor      string
+	RequestID  string
+	Action     AuditAction
+	// From and To bound the time of the change to [From, To).
+	From *time.Time
+	To   *time.Time
+	// Before continues the log after the entry with that ID.
+	Before *int64
+	Limit  int
+}
+
+func (f AuditFilter) Validate() error {
+	if f.Limit < 1 || f.Limit > MaxAuditEntries {
+		return fmt.Errorf("limit must be between 1 and %d", MaxAuditEntries)
+	}
+
+	if f.Action != "" && !slices.Contains(AuditActions, f.Action) {
+		return fmt.Errorf("unknown action %q", f.Action)
+	}
+
+	if f.From != nil && f.To != nil && !f.To.After(*f.From) {
+		return errors.New("to must be after from")
+	}
+
+	return nil
+}
+
+// AuditPage is a page of the audit log, NextBefore continues it.
+type AuditPage struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextBefore *int64       `json:"next_before,omitempty"`
+}
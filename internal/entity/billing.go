@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+type BillingUnit string
+
+const (
+	BillingDay   BillingUnit = "day"
+	BillingWeek  BillingUnit = "week"
+	BillingMonth BillingUnit = "month"
+	BillingYear  BillingUnit = "year"
+)
+
+// daysPerMonth is the length of an average Gregorian month, used to compare day and month based periods.
+const daysPerMonth = 365.2425 / 12
+
+// BillingPeriod is how often a subscription is charged, e.g. 1 month, 3 months, 1 year or 14 days.
+type BillingPeriod struct {
+	Unit  BillingUnit `json:"unit"`
+	Count int         `json:"count"`
+}
+
+var (
+	Monthly   = BillingPeriod{Unit: BillingMonth, Count: 1}
+	Quarterly = BillingPeriod{Unit: BillingMonth, Count: 3}
+	Yearly    = BillingPeriod{Unit: BillingYear, Count: 1}
+)
+
+// ParseBillingPeriod parses the period names accepted in query parameters.
+func ParseBillingPeriod(s string) (BillingPeriod, error) {
+	switch s {
+	case "day":
+		return BillingPeriod{Unit: BillingDay, Count: 1}, nil
+	case "week":
+		return BillingPeriod{Unit: BillingWeek, Count: 1}, nil
+	case "month":
+		return Monthly, nil
+	case "quarter":
+		return Quarterly, nil
+	case "year":
+		return Yearly, nil
+	default:
+		return BillingPeriod{}, fmt.Errorf("unknown period %q", s)
+	}
+}
+
+func (p BillingPeriod) IsZero() bool {
+	return p == BillingPeriod{}
+}
+
+func (p BillingPeriod) Validate() error {
+	switch p.Unit {
+	case BillingDay, BillingWeek, BillingMonth, BillingYear:
+	default:
+		return fmt.Errorf("unknown billing unit %q", p.Unit)
+	}
+
+	if p.Count <= 0 {
+		return errors.New("billing count must be greater than 0")
+	}
+
+	return nil
+}
+
+// Months returns the length of a month or year based period in months, ok is false for day based periods.
+func (p BillingPeriod) Months() (months int, ok bool) {
+	switch p.Unit {
+	case BillingMonth:
+		return p.Count, true
+	case BillingYear:
+		return p.Count * 12, true
+	default:
+		return 0, false
+	}
+}
+
+// Days returns the length of the period in days, months are counted as average Gregorian months.
+func (p BillingPeriod) Days() float64 {
+	switch p.Unit {
+	case BillingDay:
+		return float64(p.Count)
+	case BillingWeek:
+		return float64(p.Count * 7)
+	case BillingMonth:
+		return float64(p.Count) * daysPerMonth
+	case BillingYear:
+		return float64(p.Count) * daysPerMonth * 12
+	default:
+		return 0
+	}
+}
+
+// Normalize converts a price charged every p into the equivalent price per target period,
+// so a 12 000 yearly plan normalizes to 1 000 per month. It fails with ErrMoneyOverflow when the
+// normalized price doesn't fit into Money.
+func (p BillingPeriod) Normalize(price Money, target BillingPeriod) (Money, error) {
+	if from, ok := p.Months(); ok {
+		if to, ok := target.Months(); ok {
+			return price.MulRat(big.NewRat(int64(to), int64(from)))
+		}
+	}
+
+	ratio := new(big.Rat).SetFloat64(target.Days() / p.Days())
+	if ratio == nil {
+		return Money{Currency: price.Currency}, nil
+	}
+
+	return price.MulRat(ratio)
+}
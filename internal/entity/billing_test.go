@@ -0,0 +1,65 @@
+package entity
+
+import "testing"
+
+func TestBillingPeriodNormalize(t *testing.T) {
+	weekly := BillingPeriod{Unit: BillingWeek, Count: 1}
+	daily := BillingPeriod{Unit: BillingDay, Count: 1}
+
+	tests := []struct {
+		name   string
+		period BillingPeriod
+		price  Money
+		target BillingPeriod
+		want   Money
+	}{
+		{"yearly to monthly", Yearly, NewMoney(1200000, "RUB"), Monthly, NewMoney(100000, "RUB")},
+		{"monthly to yearly", Monthly, NewMoney(29900, "RUB"), Yearly, NewMoney(358800, "RUB")},
+		{"quarterly to monthly rounds", Quarterly, NewMoney(1000, "USD"), Monthly, NewMoney(333, "USD")},
+		{"quarterly to monthly rounds half up", Quarterly, NewMoney(500, "USD"), Monthly, NewMoney(167, "USD")},
+		{"same period", Monthly, NewMoney(12345, "EUR"), Monthly, NewMoney(12345, "EUR")},
+		{"weekly to daily", weekly, NewMoney(700, "RUB"), daily, NewMoney(100, "RUB")},
+		{"daily to monthly uses average months", daily, NewMoney(100, "RUB"), Monthly, NewMoney(3044, "RUB")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.period.Normalize(tt.price, tt.target)
+			if err != nil {
+				t.Fatalf("Normalize() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBillingPeriod(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    BillingPeriod
+		wantErr bool
+	}{
+		{"day", BillingPeriod{Unit: BillingDay, Count: 1}, false},
+		{"week", BillingPeriod{Unit: BillingWeek, Count: 1}, false},
+		{"month", Monthly, false},
+		{"quarter", Quarterly, false},
+		{"year", Yearly, false},
+		{"fortnight", BillingPeriod{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseBillingPeriod(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBillingPeriod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseBillingPeriod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
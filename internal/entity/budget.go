@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// BudgetPeriod is the calendar period a budget limits spending over.
+type BudgetPeriod string
+
+const (
+	BudgetMonthly BudgetPeriod = "month"
+	BudgetYearly  BudgetPeriod = "year"
+)
+
+func (p BudgetPeriod) Validate() error {
+	if p != BudgetMonthly && p != BudgetYearly {
+		return fmt.Errorf("unknown budget period %q", p)
+	}
+
+	return nil
+}
+
+// Budget limits what the user spends on subscriptions per calendar month or year. A user has
+// at most one budget per period and category.
+type Budget struct {
+	ID     uuid.UUID    `json:"id"`
+	UserID uuid.UUID    `json:"user_id"`
+	Period BudgetPeriod `json:"period"`
+	// Category limits the budget to the subscriptions of the category, empty means all of them.
+	Category Category `json:"category,omitempty"`
+	// Limit is in the currency spending is counted in, the default currency when it names none.
+	Limit Money `json:"limit"`
+}
+
+// Validate checks everything but the user, which only a new budget names.
+func (b Budget) Validate() error {
+	if err := b.Period.Validate(); err != nil {
+		return err
+	}
+
+	if b.Category != "" {
+		if err := b.Category.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if b.Limit.Amount <= 0 {
+		return errors.New("limit must be greater than 0")
+	}
+
+	if b.Limit.Currency != "" {
+		if err := b.Limit.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BudgetStatus is where a budget stands in the current period.
+type BudgetStatus struct {
+	Budget
+	// PeriodStart and PeriodEnd bound the current month or year, both days included.
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	// Spent is what was charged from the start of the period up to today.
+	Spent Money `json:"spent"`
+	// Projected adds the charges still due until the end of the period.
+	Projected Money `json:"projected"`
+	// Remaining is Limit less Projected, negative once the budget is exceeded.
+	Remaining  Money `json:"remaining"`
+	OverBudget bool  `json:"over_budget"`
+}
+
+// Warning describes the exceeded budget in a line.
+func (s BudgetStatus) Warning() string {
+	scope := "overall"
+	if s.Category != "" {
+		scope = string(s.Category)
+	}
+
+	return fmt.Sprintf("%sly %s budget of %s %s exceeded: %s %s projected",
+		s.Period, scope, s.Limit, s.Limit.Currency, s.Projected, s.Projected.Currency)
+}
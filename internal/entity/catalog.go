@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var (
+	ErrServiceInUse        = errors.New("service is in use")
+	ErrInvalidSubscription = errors.New("invalid subscription")
+)
+
+// CatalogEntry is a service in the catalog. Subscriptions reference it by ID, and a subscription
+// created with a free-text name is matched to the entry by its name or one of its aliases.
+type CatalogEntry struct {
+	ID uuid.UUID `json:"id"`
+	// Name is the canonical name subscriptions to the service are shown with.
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases"`
+	Category Category `json:"category,omitempty"`
+	Homepage string   `json:"homepage,omitempty"`
+	// DefaultPrice is used for subscriptions created without a price.
+	DefaultPrice *Money `json:"default_price,omitempty"`
+}
+
+func (e CatalogEntry) Validate() error {
+	if strings.TrimSpace(e.Name) == "" {
+		return errors.New("service name is empty")
+	}
+
+	keys := map[string]bool{NameKey(e.Name): true}
+	for _, alias := range e.Aliases {
+		key := NameKey(alias)
+		if key == "" {
+			return errors.New("alias is empty")
+		}
+
+		if keys[key] {
+			return fmt.Errorf("duplicate name or alias %q", alias)
+		}
+
+		keys[key] = true
+	}
+
+	if e.Category != "" {
+		if err := e.Category.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if e.Homepage != "" {
+		u, err := url.Parse(e.Homepage)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid homepage %q", e.Homepage)
+		}
+	}
+
+	if e.DefaultPrice != nil {
+		if e.DefaultPrice.Amount <= 0 {
+			return errors.New("default price must be greater than 0")
+		}
+
+		if err := e.DefaultPrice.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Names returns the canonical name followed by the aliases.
+func (e CatalogEntry) Names() []string {
+	return append([]string{e.Name}, e.Aliases...)
+}
+
+// NameKey is the form service names are matched in: "Yandex Plus" and " yandex plus" are the same service.
+func NameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,125 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const maxTagLength = 64
+
+// Category is a subscription's place in the fixed taxonomy spending is broken down by.
+type Category string
+
+const (
+	CategoryStreaming Category = "streaming"
+	CategoryMusic     Category = "music"
+	CategoryCloud     Category = "cloud"
+	CategorySoftware  Category = "software"
+	CategoryGaming    Category = "gaming"
+	CategoryNews      Category = "news"
+	CategoryEducation Category = "education"
+	CategoryFitness   Category = "fitness"
+	CategoryOther     Category = "other"
+)
+
+// Categories lists the whole taxonomy.
+var Categories = []Category{
+	CategoryStreaming,
+	CategoryMusic,
+	CategoryCloud,
+	CategorySoftware,
+	CategoryGaming,
+	CategoryNews,
+	CategoryEducation,
+	CategoryFitness,
+	CategoryOther,
+}
+
+func (c Category) Validate() error {
+	if !slices.Contains(Categories, c) {
+		return fmt.Errorf("unknown category %q", c)
+	}
+
+	return nil
+}
+
+// NormalizeTags trims and lower-cases user-defined tags, drops repeated ones and sorts them.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(tag)))
+	}
+
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized)
+}
+
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return errors.New("tag is empty")
+		}
+
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+	}
+
+	return nil
+}
+
+type SpendingParams struct {
+	UserID    uuid.UUID
+	StartDate time.Time
+	EndDate   *time.Time
+	// TargetCurrency is the currency every amount is converted to, empty means the default currency.
+	TargetCurrency string
+}
+
+func (p SpendingParams) Validate() error {
+	if p.StartDate.IsZero() {
+		return errors.New("start date is empty")
+	}
+
+	if p.EndDate != nil && p.EndDate.Before(p.StartDate) {
+		return errors.New("end date must be greater than start date")
+	}
+
+	if p.TargetCurrency != "" {
+		if err := ValidateCurrency(p.TargetCurrency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CategorySpending is what the user was charged for subscriptions of one category.
+type CategorySpending struct {
+	Category Category `json:"category"`
+	Amount   Money    `json:"amount"`
+}
+
+// TagSpending is what the user was charged for subscriptions carrying one tag.
+type TagSpending struct {
+	Tag    string `json:"tag"`
+	Amount Money  `json:"amount"`
+}
+
+// SpendingByCategory splits the amount charged within a period by category and by tag, biggest first.
+// A subscription counts towards each of its tags, so the tags may add up to more than Total.
+type SpendingByCategory struct {
+	UserID     uuid.UUID          `json:"user_id"`
+	Total      Money              `json:"total"`
+	Categories []CategorySpending `json:"categories"`
+	Tags       []TagSpending      `json:"tags"`
+	// Untagged is the part of Total charged for subscriptions without tags.
+	Untagged Money `json:"untagged"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "fmt"
+
+// DefaultCurrency is used for subscriptions created before prices had a currency.
+const DefaultCurrency = "RUB"
+
+// ValidateCurrency checks that code looks like an ISO 4217 alphabetic code.
+func ValidateCurrency(code string) error {
+	if len(code) != 3 {
+		return fmt.Errorf("invalid currency code %q", code)
+	}
+
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return fmt.Errorf("invalid currency code %q", code)
+		}
+	}
+
+	return nil
+}
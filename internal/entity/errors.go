@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrNotFound = errors.New("not found")
 	ErrAlreadyExists=errors.New("already exists")
-)
\ No newline at end of file
+	ErrRateNotFound = errors.New("exchange rate not found")
+)
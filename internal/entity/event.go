@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// SubscriptionEventType names what happened to a subscription.
+type SubscriptionEventType string
+
+const (
+	SubscriptionCreated SubscriptionEventType = "subscription.created"
+	SubscriptionUpdated SubscriptionEventType = "subscription.updated"
+	SubscriptionDeleted SubscriptionEventType = "subscription.deleted"
+	// SubscriptionRestored is a subscription taken out of the trash.
+	SubscriptionRestored SubscriptionEventType = "subscription.restored"
+	// SubscriptionPurged is a subscription the purge job removed from the trash for good.
+	SubscriptionPurged SubscriptionEventType = "subscription.purged"
+)
+
+// SubscriptionEventTypes lists every type of subscription event.
+var SubscriptionEventTypes = []SubscriptionEventType{SubscriptionCreated, SubscriptionUpdated, SubscriptionDeleted, SubscriptionRestored, SubscriptionPurged}
+
+// SubscriptionEvent is a domain event other services learn about subscription changes from.
+// Before is nil for a created or restored subscription and After for a deleted or purged one. Events are delivered
+// at least once and in order for each subscription, repeats carry the same ID.
+type SubscriptionEvent struct {
+	ID             uuid.UUID             `json:"id"`
+	Type           SubscriptionEventType `json:"type"`
+	SubscriptionID uuid.UUID             `json:"subscription_id"`
+	UserID         uuid.UUID             `json:"user_id"`
+	OccurredAt     time.Time             `json:"occurred_at"`
+	Before         *Subscription         `json:"before"`
+	After          *Subscription         `json:"after"`
+}
+
+// OutboxEvent is an event waiting in the outbox to be published, Seq orders the events as they were written.
+type OutboxEvent struct {
+	Seq       int64
+	Event     SubscriptionEvent
+	Attempts  int
+	LastError string
+	// NextAttemptAt holds an event that failed to publish back until then.
+	NextAttemptAt time.Time
+}
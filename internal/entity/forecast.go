@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	DefaultForecastMonths = 12
+	MaxForecastMonths     = 60
+)
+
+type ForecastParams struct {
+	UserID uuid.UUID
+	// Months is how far ahead of today the forecast looks.
+	Months int
+	// ExcludeCancelling leaves out cancelled subscriptions that are still paid until their end date.
+	ExcludeCancelling bool
+	// TargetCurrency is the currency every amount is converted to, empty means the default currency.
+	TargetCurrency string
+}
+
+func (p ForecastParams) Validate() error {
+	if p.Months < 1 || p.Months > MaxForecastMonths {
+		return fmt.Errorf("months must be between 1 and %d", MaxForecastMonths)
+	}
+
+	if p.TargetCurrency != "" {
+		if err := ValidateCurrency(p.TargetCurrency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ForecastCharge is one upcoming charge: Price is what the subscription costs on that date
+// and Amount is the price converted to the requested currency.
+type ForecastCharge struct {
+	Date           time.Time `json:"date"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	ServiceName    string    `json:"service_name"`
+	Price          Money     `json:"price"`
+	Amount         Money     `json:"amount"`
+}
+
+// Forecast lists the charges expected from the day after today to the end of the forecast, oldest first.
+type Forecast struct {
+	UserID  uuid.UUID        `json:"user_id"`
+	From    time.Time        `json:"from"`
+	To      time.Time        `json:"to"`
+	Charges []ForecastCharge `json:"charges"`
+	Months  []MonthlySum     `json:"months"`
+	Total   Money            `json:"total"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// JobOutcome is how a run of a scheduled job ended.
+type JobOutcome string
+
+const (
+	JobSucceeded JobOutcome = "succeeded"
+	JobFailed    JobOutcome = "failed"
+	// JobSkipped means another replica held the job's lock, so this one didn't run it.
+	JobSkipped JobOutcome = "skipped"
+)
+
+// JobRun is one run of a scheduled job.
+type JobRun struct {
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt time.Time  `json:"finished_at"`
+	Outcome    JobOutcome `json:"outcome"`
+	// Result sums up what a successful run did, Error is why a failed one failed.
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// JobStatus is the state of a scheduled job as this replica sees it.
+type JobStatus struct {
+	Name string `json:"name"`
+	// Schedule is the cron expression the job runs on, in UTC.
+	Schedule string `json:"schedule"`
+	// Exclusive jobs run on one replica at a time.
+	Exclusive bool      `json:"exclusive"`
+	Running   bool      `json:"running"`
+	NextRun   time.Time `json:"next_run"`
+	LastRun   *JobRun   `json:"last_run,omitempty"`
+	// LastSuccess is when the last successful run finished.
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
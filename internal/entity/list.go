@@ -0,0 +1,235 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+// SubscriptionSort is the field a subscriptions list is ordered by, ties are broken by ID.
+type SubscriptionSort string
+
+const (
+	SortStartDate   SubscriptionSort = "start_date"
+	SortPrice       SubscriptionSort = "price"
+	SortServiceName SubscriptionSort = "service_name"
+)
+
+// ParseSubscriptionSort parses a sort query parameter, a leading "-" asks for descending order.
+func ParseSubscriptionSort(s string) (sort SubscriptionSort, desc bool, err error) {
+	desc = strings.HasPrefix(s, "-")
+	sort = SubscriptionSort(strings.TrimPrefix(s, "-"))
+
+	switch sort {
+	case SortStartDate, SortPrice, SortServiceName:
+		return sort, desc, nil
+	default:
+		return "", false, fmt.Errorf("unknown sort %q", s)
+	}
+}
+
+// Value returns the sort key of sub as it is written into a cursor.
+func (s SubscriptionSort) Value(sub Subscription) string {
+	switch s {
+	case SortPrice:
+		return strconv.FormatInt(sub.Price.Amount, 10)
+	case SortServiceName:
+		return sub.ServiceName
+	default:
+		return sub.StartDate.Format(time.DateOnly)
+	}
+}
+
+// ListCursor points right after the last subscription of a page in the list's sort order.
+type ListCursor struct {
+	Sort  SubscriptionSort `json:"s"`
+	Desc  bool             `json:"d,omitempty"`
+	Value string           `json:"v"`
+	ID    uuid.UUID        `json:"id"`
+}
+
+// NewListCursor returns the cursor of the page following sub.
+func NewListCursor(sort SubscriptionSort, desc bool, sub Subscription) ListCursor {
+	return ListCursor{Sort: sort, Desc: desc, Value: sort.Value(sub), ID: sub.ID}
+}
+
+// Encode returns the opaque string clients pass back as cursor.
+func (c ListCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeListCursor(s string) (ListCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ListCursor{}, errors.New("malformed cursor")
+	}
+
+	var c ListCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return ListCursor{}, errors.New("malformed cursor")
+	}
+
+	return c, nil
+}
+
+// PriceValue returns the price a cursor of a price-sorted list stopped at.
+func (c ListCursor) PriceValue() (int64, error) {
+	return strconv.ParseInt(c.Value, 10, 64)
+}
+
+func (c ListCursor) validate() error {
+	var err error
+
+	switch c.Sort {
+	case SortPrice:
+		_, err = c.PriceValue()
+	case SortStartDate:
+		_, err = time.Parse(time.DateOnly, c.Value)
+	}
+
+	if err != nil {
+		return errors.New("malformed cursor")
+	}
+
+	return nil
+}
+
+type SubscriptionsListParams struct {
+	UserID uuid.UUID
+	Sort   SubscriptionSort
+	Desc   bool
+	Limit  int
+	Cursor *ListCursor
+
+	ServiceName string
+	// ActiveOn keeps subscriptions that are running on the date.
+	ActiveOn *time.Time
+	// Currency keeps subscriptions priced in the currency. PriceMin and PriceMax bound the price
+	// in its minor units, so they need it: amounts of different currencies don't compare.
+	Currency   string
+	PriceMin   *int64
+	PriceMax   *int64
+	HasEndDate *bool
+	// TrialOn keeps subscriptions whose free trial covers the date.
+	TrialOn  *time.Time
+	Category Category
+	// Tags keeps subscriptions carrying every one of the tags.
+	Tags []string
+
+	// Period, when set, asks for every subscription's price normalized to the period.
+	Period *BillingPeriod
+}
+
+func (p SubscriptionsListParams) Validate() error {
+	if err := validatePage(p.Sort, p.Desc, p.Limit, p.Cursor); err != nil {
+		return err
+	}
+
+	if err := validatePriceRange(p.Currency, p.PriceMin, p.PriceMax); err != nil {
+		return err
+	}
+
+	if p.Category != "" {
+		if err := p.Category.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.Period != nil {
+		if err := p.Period.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SubscriptionFilter selects subscriptions of all users, every set field narrows the result.
+type SubscriptionFilter struct {
+	Sort   SubscriptionSort
+	Desc   bool
+	Limit  int
+	Cursor *ListCursor
+
+	// ServiceName matches any part of the name ignoring case.
+	ServiceName string
+	UserIDs     []uuid.UUID
+	// ActiveFrom and ActiveTo keep subscriptions running at some point between the dates, either may be open.
+	ActiveFrom *time.Time
+	ActiveTo   *time.Time
+	// Currency keeps subscriptions priced in the currency. PriceMin and PriceMax bound the price
+	// in its minor units, so they need it: amounts of different currencies don't compare.
+	Currency string
+	PriceMin *int64
+	PriceMax *int64
+}
+
+func (f SubscriptionFilter) Validate() error {
+	if err := validatePage(f.Sort, f.Desc, f.Limit, f.Cursor); err != nil {
+		return err
+	}
+
+	if f.ActiveFrom != nil && f.ActiveTo != nil && f.ActiveTo.Before(*f.ActiveFrom) {
+		return errors.New("active_between end must not be before its start")
+	}
+
+	if err := validatePriceRange(f.Currency, f.PriceMin, f.PriceMax); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validatePriceRange(currency string, priceMin, priceMax *int64) error {
+	if currency == "" {
+		if priceMin != nil || priceMax != nil {
+			return errors.New("price_min and price_max need currency")
+		}
+
+		return nil
+	}
+
+	if err := ValidateCurrency(currency); err != nil {
+		return err
+	}
+
+	if priceMin != nil && priceMax != nil && *priceMin > *priceMax {
+		return errors.New("price_min must not be greater than price_max")
+	}
+
+	return nil
+}
+
+func validatePage(sort SubscriptionSort, desc bool, limit int, cursor *ListCursor) error {
+	if limit <= 0 || limit > MaxPageLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxPageLimit)
+	}
+
+	if cursor == nil {
+		return nil
+	}
+
+	if cursor.Sort != sort || cursor.Desc != desc {
+		return errors.New("cursor belongs to a list with another sort")
+	}
+
+	return cursor.validate()
+}
+
+// SubscriptionsPage is one page of a subscriptions list, NextCursor is empty on the last page.
+type SubscriptionsPage struct {
+	Items      []Subscription `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,244 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrMoneyOverflow means the result doesn't fit into the int64 of minor units.
+	ErrMoneyOverflow = errors.New("money overflow")
+)
+
+// minorUnits lists ISO 4217 currencies whose minor unit is not a hundredth.
+var minorUnits = map[string]int{
+	"JPY": 0, "KRW": 0, "VND": 0, "CLP": 0, "ISK": 0, "UGX": 0,
+	"BHD": 3, "KWD": 3, "OMR": 3, "JOD": 3, "TND": 3, "LYD": 3, "IQD": 3,
+}
+
+// MinorUnits returns the number of decimal places of the currency.
+func MinorUnits(currency string) int {
+	if units, ok := minorUnits[currency]; ok {
+		return units
+	}
+
+	return 2
+}
+
+// Money is an exact amount of a currency counted in its minor units (kopecks, cents).
+// In JSON it is {"amount": "12.50", "currency": "RUB"}, the amount is a decimal string
+// so it never passes through a float.
+type Money struct {
+	Amount   int64  `json:"amount" swaggertype:"string" example:"12.50"`
+	Currency string `json:"currency" example:"RUB"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "12.50" or "-3" in major units of the currency.
+func ParseMoney(amount, currency string) (Money, error) {
+	minor, err := parseMinor(amount, MinorUnits(currency))
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q for %s: %w", amount, currency, err)
+	}
+
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// parseMinor parses a decimal amount into minor units with the given number of decimals.
+func parseMinor(amount string, units int) (int64, error) {
+	negative := strings.HasPrefix(amount, "-")
+	digits := strings.TrimPrefix(amount, "-")
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" || len(fraction) > units || strings.ContainsAny(whole+fraction, "+-") {
+		return 0, fmt.Errorf("expected at most %d decimals", units)
+	}
+
+	fraction += strings.Repeat("0", units-len(fraction))
+
+	minor, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if negative {
+		minor = -minor
+	}
+
+	return minor, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// String formats the amount in major units with the currency's number of decimals.
+func (m Money) String() string {
+	units := MinorUnits(m.Currency)
+
+	abs := m.Amount
+	sign := ""
+	if abs < 0 {
+		abs = -abs
+		sign = "-"
+	}
+
+	if units == 0 {
+		return sign + strconv.FormatInt(abs, 10)
+	}
+
+	scale := int64(math.Pow10(units))
+
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/scale, units, abs%scale)
+}
+
+func (m Money) Validate() error {
+	if err := ValidateCurrency(m.Currency); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Add sums two amounts of the same currency, a zero Money without currency takes the other's currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency == "" && m.Amount == 0 {
+		return o, nil
+	}
+
+	if o.Currency == "" && o.Amount == 0 {
+		return m, nil
+	}
+
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w adding %s to %s", ErrMoneyOverflow, o, m)
+	}
+
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// MulRat multiplies the amount by r, rounding half away from zero to the nearest minor unit.
+// It fails with ErrMoneyOverflow when the product doesn't fit into the amount.
+func (m Money) MulRat(r *big.Rat) (Money, error) {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), r)
+
+	amount, err := roundRat(product)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w multiplying %s by %s", err, m, r.RatString())
+	}
+
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// Convert converts the amount to currency at rate, the number of to units one major unit of m buys.
+func (m Money) Convert(rate float64, currency string) (Money, error) {
+	if m.Currency == currency {
+		return m, nil
+	}
+
+	r := new(big.Rat).SetFloat64(rate)
+	if r == nil {
+		return Money{Currency: currency}, nil
+	}
+
+	shift := MinorUnits(currency) - MinorUnits(m.Currency)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		r.Mul(r, scale)
+	} else {
+		r.Quo(r, scale)
+	}
+
+	converted, err := m.MulRat(r)
+	if err != nil {
+		return Money{}, err
+	}
+
+	converted.Currency = currency
+
+	return converted, nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{
+		Amount:   m.String(),
+		Currency: m.Currency,
+	})
+}
+
+// UnmarshalJSON accepts the amount both as a decimal string and as a JSON number.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Amount   json.RawMessage `json:"amount"`
+		Currency string          `json:"currency"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	amount := strings.Trim(string(raw.Amount), `"`)
+	if amount == "" {
+		return errors.New("money amount is empty")
+	}
+
+	// A price without currency gets the service's default one later, parse it with the usual two decimals.
+	units := MinorUnits(raw.Currency)
+
+	minor, err := parseMinor(amount, units)
+	if err != nil {
+		return fmt.Errorf("invalid amount %s: %w", amount, err)
+	}
+
+	*m = Money{Amount: minor, Currency: raw.Currency}
+
+	return nil
+}
+
+// roundRat rounds r half away from zero, it fails with ErrMoneyOverflow when the result doesn't fit into an int64.
+func roundRat(r *big.Rat) (int64, error) {
+	num := new(big.Int).Set(r.Num())
+	den := r.Denom()
+
+	negative := num.Sign() < 0
+	num.Abs(num)
+
+	// (2*num + den) / (2*den) rounds half up on the absolute value.
+	num.Mul(num, big.NewInt(2)).Add(num, den)
+	quo := num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+
+	if negative {
+		quo.Neg(quo)
+	}
+
+	if !quo.IsInt64() {
+		return 0, ErrMoneyOverflow
+	}
+
+	return quo.Int64(), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,203 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"12.50", "RUB", 1250, false},
+		{"12.5", "RUB", 1250, false},
+		{"12", "RUB", 1200, false},
+		{"0.01", "USD", 1, false},
+		{"-3", "EUR", -300, false},
+		{"-0.05", "EUR", -5, false},
+		{"500", "JPY", 500, false},
+		{"1.234", "BHD", 1234, false},
+		{"12.505", "RUB", 0, true},
+		{"1.5", "JPY", 0, true},
+		{".50", "RUB", 0, true},
+		{"", "RUB", 0, true},
+		{"1.-5", "RUB", 0, true},
+		{"+5", "RUB", 0, true},
+		{"--5", "RUB", 0, true},
+		{"1e3", "RUB", 0, true},
+		{"92233720368547758.08", "RUB", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			got, err := ParseMoney(tt.amount, tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMoney() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != NewMoney(tt.want, tt.currency) {
+				t.Errorf("ParseMoney() = %v, want %d %s", got, tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{NewMoney(1250, "RUB"), "12.50"},
+		{NewMoney(5, "USD"), "0.05"},
+		{NewMoney(-5, "USD"), "-0.05"},
+		{NewMoney(0, "EUR"), "0.00"},
+		{NewMoney(500, "JPY"), "500"},
+		{NewMoney(1234, "BHD"), "1.234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.money.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyMulRat(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		r       *big.Rat
+		want    int64
+		wantErr error
+	}{
+		{"exact", 1000, big.NewRat(1, 4), 250, nil},
+		{"rounds down", 1000, big.NewRat(1, 3), 333, nil},
+		{"rounds up", 2000, big.NewRat(1, 3), 667, nil},
+		{"half rounds up", 5, big.NewRat(1, 2), 3, nil},
+		{"negative half rounds away from zero", -5, big.NewRat(1, 2), -3, nil},
+		{"negative rounds down", -1000, big.NewRat(1, 3), -333, nil},
+		{"zero", 0, big.NewRat(7, 3), 0, nil},
+		{"largest amount", math.MaxInt64, big.NewRat(1, 1), math.MaxInt64, nil},
+		{"overflow", math.MaxInt64, big.NewRat(2, 1), 0, ErrMoneyOverflow},
+		{"negative overflow", math.MinInt64, big.NewRat(2, 1), 0, ErrMoneyOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMoney(tt.amount, "RUB").MulRat(tt.r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MulRat() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && got != NewMoney(tt.want, "RUB") {
+				t.Errorf("MulRat() = %d, want %d", got.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		rate     float64
+		currency string
+		want     Money
+		wantErr  bool
+	}{
+		{"same currency", NewMoney(1250, "RUB"), 2, "RUB", NewMoney(1250, "RUB"), false},
+		{"cents to kopecks", NewMoney(1000, "USD"), 90.5, "RUB", NewMoney(90500, "RUB"), false},
+		{"kopecks to cents rounds", NewMoney(10000, "RUB"), 0.011, "USD", NewMoney(110, "USD"), false},
+		{"to a currency without minor units", NewMoney(1000, "USD"), 150.25, "JPY", NewMoney(1503, "JPY"), false},
+		{"from a currency without minor units", NewMoney(1000, "JPY"), 0.0066, "USD", NewMoney(660, "USD"), false},
+		{"overflow", NewMoney(math.MaxInt64/2, "USD"), 1000, "RUB", Money{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.money.Convert(tt.rate, tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Convert() = %v %s, want %v %s", got, got.Currency, tt.want, tt.want.Currency)
+			}
+		})
+	}
+}
+
+func TestMoneyAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{"same currency", NewMoney(150, "RUB"), NewMoney(250, "RUB"), NewMoney(400, "RUB"), nil},
+		{"zero without currency", Money{}, NewMoney(250, "USD"), NewMoney(250, "USD"), nil},
+		{"to zero without currency", NewMoney(250, "USD"), Money{}, NewMoney(250, "USD"), nil},
+		{"currency mismatch", NewMoney(1, "RUB"), NewMoney(1, "USD"), Money{}, ErrCurrencyMismatch},
+		{"overflow", NewMoney(math.MaxInt64, "RUB"), NewMoney(1, "RUB"), Money{}, ErrMoneyOverflow},
+		{"negative overflow", NewMoney(math.MinInt64, "RUB"), NewMoney(-1, "RUB"), Money{}, ErrMoneyOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Add() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{`{"amount": "12.50", "currency": "RUB"}`, NewMoney(1250, "RUB"), false},
+		{`{"amount": 12.5, "currency": "USD"}`, NewMoney(1250, "USD"), false},
+		{`{"amount": "300", "currency": "JPY"}`, NewMoney(300, "JPY"), false},
+		{`{"amount": "9.99"}`, NewMoney(999, ""), false},
+		{`{"currency": "RUB"}`, Money{}, true},
+		{`{"amount": "1.001", "currency": "RUB"}`, Money{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	data, err := json.Marshal(NewMoney(-1205, "EUR"))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if want := `{"amount":"-12.05","currency":"EUR"}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
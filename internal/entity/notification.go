@@ -0,0 +1,143 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// MaxDeliveries bounds the delivery log page.
+const MaxDeliveries = 100
+
+// NotificationChannel is the way a notification reaches the user.
+type NotificationChannel string
+
+const (
+	ChannelEmail   NotificationChannel = "email"
+	ChannelWebhook NotificationChannel = "webhook"
+)
+
+// NotificationEvent is what a notification is about.
+type NotificationEvent string
+
+const (
+	EventRenewal        NotificationEvent = "renewal"
+	EventTrialEnding    NotificationEvent = "trial_ending"
+	EventBudgetExceeded NotificationEvent = "budget_exceeded"
+)
+
+// NotificationEvents lists every event users can be notified of.
+var NotificationEvents = []NotificationEvent{EventRenewal, EventTrialEnding, EventBudgetExceeded}
+
+// NotificationPreference is one place the user wants to be notified at.
+type NotificationPreference struct {
+	Channel NotificationChannel `json:"channel"`
+	// Address is the e-mail address or the webhook URL, depending on the channel.
+	Address string `json:"address"`
+	// Events the user is notified of at the address, every event when empty.
+	Events []NotificationEvent `json:"events,omitempty"`
+}
+
+func (p NotificationPreference) Validate() error {
+	switch p.Channel {
+	case ChannelEmail:
+		if _, err := mail.ParseAddress(p.Address); err != nil {
+			return fmt.Errorf("invalid email address %q", p.Address)
+		}
+	case ChannelWebhook:
+		u, err := url.Parse(p.Address)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook url %q", p.Address)
+		}
+
+		if err := publicHost(u.Hostname()); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown notification channel %q", p.Channel)
+	}
+
+	for _, event := range p.Events {
+		if !slices.Contains(NotificationEvents, event) {
+			return fmt.Errorf("unknown notification event %q", event)
+		}
+	}
+
+	return nil
+}
+
+// Wants reports whether the user is notified of the event at the address.
+func (p NotificationPreference) Wants(event NotificationEvent) bool {
+	return len(p.Events) == 0 || slices.Contains(p.Events, event)
+}
+
+// ValidatePreferences checks every preference and that no address is listed twice for a channel.
+// An e-mail address given with a display name, like "Ann <ann@example.com>", is replaced with
+// the bare address, which is what the SMTP envelope needs.
+func ValidatePreferences(prefs []NotificationPreference) error {
+	type place struct {
+		channel NotificationChannel
+		address string
+	}
+
+	seen := make(map[place]bool, len(prefs))
+
+	for i, p := range prefs {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+
+		if p.Channel == ChannelEmail {
+			addr, _ := mail.ParseAddress(p.Address)
+			p.Address = addr.Address
+			prefs[i].Address = addr.Address
+		}
+
+		key := place{channel: p.Channel, address: p.Address}
+		if seen[key] {
+			return errors.New("duplicate notification address " + p.Address)
+		}
+
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// DeliveryStatus is how far the delivery of a notification got.
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending"
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"
+)
+
+// Delivery is the log entry of one notification sent to one address.
+type Delivery struct {
+	ID      uuid.UUID           `json:"id"`
+	UserID  uuid.UUID           `json:"user_id"`
+	Channel NotificationChannel `json:"channel"`
+	Address string              `json:"address"`
+	Event   NotificationEvent   `json:"event"`
+	Subject string              `json:"subject"`
+	// Key identifies the occurrence of the event, a notification with a key already delivered
+	// to the address is never sent again.
+	Key string `json:"key,omitempty"`
+	// Text and Data are the rest of the message, kept until it is sent so a restart doesn't lose it.
+	Text      string          `json:"-"`
+	Data      json.RawMessage `json:"-"`
+	Status    DeliveryStatus  `json:"status"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+	// NextAttemptAt is when a pending delivery is tried next.
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,75 @@
+package entity
+
+import "testing"
+
+func TestValidatePreferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefs   []NotificationPreference
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "bare address",
+			prefs: []NotificationPreference{{Channel: ChannelEmail, Address: "ann@example.com"}},
+			want:  []string{"ann@example.com"},
+		},
+		{
+			name:  "display name is dropped",
+			prefs: []NotificationPreference{{Channel: ChannelEmail, Address: "Ann <ann@example.com>"}},
+			want:  []string{"ann@example.com"},
+		},
+		{
+			name:  "webhook url is kept",
+			prefs: []NotificationPreference{{Channel: ChannelWebhook, Address: "https://example.com/hook"}},
+			want:  []string{"https://example.com/hook"},
+		},
+		{
+			name: "same address with and without a name",
+			prefs: []NotificationPreference{
+				{Channel: ChannelEmail, Address: "ann@example.com"},
+				{Channel: ChannelEmail, Address: "Ann <ann@example.com>"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "webhook on loopback",
+			prefs:   []NotificationPreference{{Channel: ChannelWebhook, Address: "http://127.0.0.1:8080/hook"}},
+			wantErr: true,
+		},
+		{
+			name:    "webhook on localhost",
+			prefs:   []NotificationPreference{{Channel: ChannelWebhook, Address: "http://localhost/hook"}},
+			wantErr: true,
+		},
+		{
+			name:    "webhook on link-local",
+			prefs:   []NotificationPreference{{Channel: ChannelWebhook, Address: "http://169.254.169.254/latest"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid address",
+			prefs:   []NotificationPreference{{Channel: ChannelEmail, Address: "ann"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePreferences(tt.prefs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatePreferences() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			for i, p := range tt.prefs {
+				if p.Address != tt.want[i] {
+					t.Errorf("address %d = %q, want %q", i, p.Address, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// PricePeriod is one entry of a subscription's price history: the price applies from
+// EffectiveFrom until the next entry takes over.
+type PricePeriod struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	Price          Money     `json:"price"`
+	EffectiveFrom  time.Time `json:"effective_from"`
+}
+
+// PriceOn returns the price in effect on the day from a history ordered by EffectiveFrom.
+// Days before the first entry get the first price, ok is false for an empty history.
+func PriceOn(history []PricePeriod, day time.Time) (price Money, ok bool) {
+	if len(history) == 0 {
+		return Money{}, false
+	}
+
+	price = history[0].Price
+	for _, period := range history[1:] {
+		if period.EffectiveFrom.After(day) {
+			break
+		}
+
+		price = period.Price
+	}
+
+	return price, true
+}
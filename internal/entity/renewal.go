@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// MaxRenewalsDays bounds the range of a renewals query.
+const MaxRenewalsDays = 5 * 366
+
+type RenewalsParams struct {
+	UserID uuid.UUID
+	// From and To bound the dates of the renewals, both days included.
+	From time.Time
+	To   time.Time
+}
+
+func (p RenewalsParams) Validate() error {
+	if p.From.IsZero() || p.To.IsZero() {
+		return errors.New("from and to must be set")
+	}
+
+	if p.To.Before(p.From) {
+		return errors.New("to must not be before from")
+	}
+
+	if p.To.Sub(p.From) > MaxRenewalsDays*24*time.Hour {
+		return fmt.Errorf("renewals can't span more than %d days", MaxRenewalsDays)
+	}
+
+	return nil
+}
+
+// Renewal is a day a subscription is charged on and the price it costs that day.
+type Renewal struct {
+	Date           time.Time `json:"date"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	ServiceName    string    `json:"service_name"`
+	Price          Money     `json:"price"`
+}
+
+// CalendarFeed is the secret address of the user's renewals calendar. Anyone who knows it can read
+// the calendar, so it is shown only once, when it is created; creating a new one revokes the old one.
+type CalendarFeed struct {
+	URL string `json:"url"`
+}
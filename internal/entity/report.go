@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// MaxReportMonths bounds the range of a monthly report.
+const MaxReportMonths = 120
+
+// MonthlyReportParams asks for the months from From to To, both included. Only their year and month matter.
+type MonthlyReportParams struct {
+	UserID uuid.UUID
+	From   time.Time
+	// To, when nil, is the current month.
+	To *time.Time
+	// TargetCurrency is the currency every amount is converted to, empty means the default currency.
+	TargetCurrency string
+}
+
+func (p MonthlyReportParams) Validate() error {
+	if p.From.IsZero() {
+		return errors.New("from is empty")
+	}
+
+	to := time.Now().UTC()
+	if p.To != nil {
+		to = *p.To
+	}
+
+	months := (to.Year()-p.From.Year())*12 + int(to.Month()) - int(p.From.Month()) + 1
+	if months <= 0 {
+		if p.To == nil {
+			return errors.New("from must not be after the current month")
+		}
+
+		return errors.New("to must not be before from")
+	}
+
+	if months > MaxReportMonths {
+		return fmt.Errorf("report can't span more than %d months", MaxReportMonths)
+	}
+
+	if p.TargetCurrency != "" {
+		if err := ValidateCurrency(p.TargetCurrency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MonthlyReportRow is what the user was charged for one service within one month, month is formatted as YYYY-MM.
+type MonthlyReportRow struct {
+	Month       string    `json:"month"`
+	ServiceID   uuid.UUID `json:"service_id"`
+	ServiceName string    `json:"service_name"`
+	Amount      Money     `json:"amount"`
+}
+
+// ServiceSum is what the user was charged for one service over the whole report.
+type ServiceSum struct {
+	ServiceID   uuid.UUID `json:"service_id"`
+	ServiceName string    `json:"service_name"`
+	Amount      Money     `json:"amount"`
+}
+
+// MonthlyReport is the user's spending as a time series: a row for every month and service with
+// charges, ordered by month and service name, and the totals per month, per service and overall.
+type MonthlyReport struct {
+	UserID uuid.UUID          `json:"user_id"`
+	From   string             `json:"from"`
+	To     string             `json:"to"`
+	Rows   []MonthlyReportRow `json:"rows"`
+	// Months has an entry for every month of the range, months without charges included.
+	Months   []MonthlySum `json:"months"`
+	Services []ServiceSum `json:"services"`
+	Total    Money        `json:"total"`
+}
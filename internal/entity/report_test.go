@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonthlyReportParamsValidate(t *testing.T) {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	// months returns the first month of a range of n months that ends with the current one.
+	months := func(n int) time.Time { return month.AddDate(0, 1-n, 0) }
+	at := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name    string
+		from    time.Time
+		to      *time.Time
+		wantErr bool
+	}{
+		{"no from", time.Time{}, nil, true},
+		{"a month", month, at(month), false},
+		{"longest range", months(MaxReportMonths), at(month), false},
+		{"too long", months(MaxReportMonths + 1), at(month), true},
+		{"to before from", month, at(months(2)), true},
+		{"to the current month", months(12), nil, false},
+		{"longest range to the current month", months(MaxReportMonths), nil, false},
+		{"too long to the current month", months(MaxReportMonths + 1), nil, true},
+		{"from after the current month", month.AddDate(0, 1, 0), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MonthlyReportParams{From: tt.from, To: tt.to}.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// SubscriptionStatus is the lifecycle state of a subscription.
+//
+//	active    -> paused, cancelled, expired
+//	paused    -> active, cancelled, expired
+//	cancelled -> expired
+//
+// A paused subscription isn't charged, a cancelled one stays paid until the end of its current
+// billing period and an expired one has reached its end date.
+type SubscriptionStatus string
+
+const (
+	StatusActive    SubscriptionStatus = "active"
+	StatusPaused    SubscriptionStatus = "paused"
+	StatusCancelled SubscriptionStatus = "cancelled"
+	StatusExpired   SubscriptionStatus = "expired"
+)
+
+var statusTransitions = map[SubscriptionStatus][]SubscriptionStatus{
+	StatusActive:    {StatusPaused, StatusCancelled, StatusExpired},
+	StatusPaused:    {StatusActive, StatusCancelled, StatusExpired},
+	StatusCancelled: {StatusExpired},
+}
+
+// CheckTransition returns ErrInvalidTransition when a subscription can't move from s to status.
+func (s SubscriptionStatus) CheckTransition(to SubscriptionStatus) error {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, s, to)
+}
+
+// StatusChange is one transition in a subscription's history, EffectiveDate is the day it takes effect on.
+type StatusChange struct {
+	SubscriptionID uuid.UUID          `json:"subscription_id"`
+	From           SubscriptionStatus `json:"from"`
+	To             SubscriptionStatus `json:"to"`
+	EffectiveDate  time.Time          `json:"effective_date"`
+	ChangedAt      time.Time          `json:"changed_at"`
+}
+
+// StatusChangeRequest is the optional body of the pause, resume and cancel endpoints.
+type StatusChangeRequest struct {
+	// Date the change takes effect on, today when empty.
+	Date *time.Time `json:"date,omitempty"`
+}
+
+// Interval is a span of days, To is exclusive and nil while the span is still open.
+type Interval struct {
+	From time.Time
+	To   *time.Time
+}
+
+// Contains reports whether the day falls into the interval.
+func (i Interval) Contains(day time.Time) bool {
+	return !day.Before(i.From) && (i.To == nil || day.Before(*i.To))
+}
+
+// PausedIntervals derives the spans a subscription spent paused from its ordered status history.
+func PausedIntervals(history []StatusChange) []Interval {
+	var intervals []Interval
+
+	for _, change := range history {
+		if change.From == StatusPaused && len(intervals) > 0 && intervals[len(intervals)-1].To == nil {
+			to := change.EffectiveDate
+			intervals[len(intervals)-1].To = &to
+		}
+
+		if change.To == StatusPaused {
+			intervals = append(intervals, Interval{From: change.EffectiveDate})
+		}
+	}
+
+	return intervals
+}
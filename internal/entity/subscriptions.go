@@ -7,22 +7,74 @@ import (
 	"github.com/gofrs/uuid/v5"
 )
 
+// ErrVersionMismatch means the subscription was changed since the client read the version it names.
+var ErrVersionMismatch = errors.New("subscription version mismatch")
+
 type Subscription struct {
-	ID          uuid.UUID  `json:"id"`
-	ServiceName string     `json:"service_name"`
-	Price       int        `json:"price"`
-	UserID      uuid.UUID  `json:"user_id"`
-	StartDate   time.Time  `json:"start_date"`
-	EndDate     *time.Time `json:"end_date,omitempty"`
+	ID uuid.UUID `json:"id"`
+	// ServiceID references the catalog entry of the service. A subscription created without it
+	// is matched to the entry by ServiceName, and ServiceName always holds the entry's canonical name.
+	ServiceID     uuid.UUID     `json:"service_id"`
+	ServiceName   string        `json:"service_name"`
+	Price         Money         `json:"price"`
+	BillingPeriod BillingPeriod `json:"billing_period"`
+	UserID        uuid.UUID     `json:"user_id"`
+	StartDate     time.Time     `json:"start_date"`
+	EndDate       *time.Time    `json:"end_date,omitempty"`
+	// Category defaults to the catalog entry's category, and to CategoryOther when it has none.
+	Category Category `json:"category,omitempty"`
+	// Tags are the user's own labels, stored trimmed and lower-cased.
+	Tags []string `json:"tags,omitempty"`
+	// Status is changed only through the pause, resume and cancel endpoints.
+	Status SubscriptionStatus `json:"status"`
+	// TrialStart and TrialEnd bound the free trial, both days included. Billing starts the day
+	// after the trial ends, so trial time is never charged. TrialStart defaults to StartDate.
+	TrialStart *time.Time `json:"trial_start,omitempty"`
+	TrialEnd   *time.Time `json:"trial_end,omitempty"`
+	// TrialEndingFlaggedAt is set by the trials job once the trial is about to end.
+	TrialEndingFlaggedAt *time.Time `json:"trial_ending_flagged_at,omitempty"`
+	// Version grows with every change of the subscription. It is returned as the ETag, and updates and
+	// deletes have to name it in If-Match.
+	Version int64 `json:"version"`
+	// DeletedAt is set while the subscription lies in the trash, it is restored or purged from there.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// PriceEffectiveFrom is the day a price changed by an update takes effect on, today when empty.
+	// Earlier charges keep the previous price. It is never stored, see the price history instead.
+	PriceEffectiveFrom *time.Time `json:"price_effective_from,omitempty"`
+	// PeriodPrice is Price normalized to the period requested by the caller, it is never stored.
+	PeriodPrice *Money `json:"period_price,omitempty"`
 }
 
 func (s Subscription) Validate() error {
-	if s.ServiceName == "" {
+	if s.ServiceName == "" && s.ServiceID.IsNil() {
 		return errors.New("service name is empty")
 	}
 
-	if s.Price <= 0 {
-		return errors.New("price must be greater than 0")
+	// A zero price is taken from the catalog entry's default price.
+	if s.Price.Amount < 0 {
+		return errors.New("price must not be negative")
+	}
+
+	if s.Price.Currency != "" {
+		if err := s.Price.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.Category != "" {
+		if err := s.Category.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := validateTags(s.Tags); err != nil {
+		return err
+	}
+
+	if !s.BillingPeriod.IsZero() {
+		if err := s.BillingPeriod.Validate(); err != nil {
+			return err
+		}
 	}
 
 	if s.StartDate.IsZero() {
@@ -39,18 +91,61 @@ func (s Subscription) Validate() error {
 		}
 	}
 
+	if s.PriceEffectiveFrom != nil && s.PriceEffectiveFrom.Before(s.StartDate) {
+		return errors.New("price effective date must not be before start date")
+	}
+
+	if s.TrialStart != nil && s.TrialEnd == nil {
+		return errors.New("trial start without trial end")
+	}
+
+	if s.TrialEnd != nil {
+		trialStart := s.StartDate
+		if s.TrialStart != nil {
+			trialStart = *s.TrialStart
+		}
+
+		if trialStart.Before(s.StartDate) {
+			return errors.New("trial start must not be before start date")
+		}
+
+		if s.TrialEnd.Before(trialStart) {
+			return errors.New("trial end must not be before trial start")
+		}
+	}
+
 	return nil
 }
 
+// InTrial reports whether the day falls into the subscription's free trial.
+func (s Subscription) InTrial(day time.Time) bool {
+	if s.TrialEnd == nil {
+		return false
+	}
+
+	trialStart := s.StartDate
+	if s.TrialStart != nil {
+		trialStart = *s.TrialStart
+	}
+
+	return !day.Before(trialStart) && !day.After(*s.TrialEnd)
+}
+
 type SubscriptionsSumParams struct {
-	UserID      uuid.UUID
+	UserID uuid.UUID
+	// ServiceID selects the service, when it is empty the service resolves ServiceName through the catalog.
+	ServiceID   uuid.UUID
 	ServiceName string
 	StartDate   time.Time
 	EndDate     *time.Time
+	// Period, when set, asks for the subscriptions' price per period next to the charged total.
+	Period *BillingPeriod
+	// TargetCurrency is the currency every amount is converted to, empty means the default currency.
+	TargetCurrency string
 }
 
 func (s SubscriptionsSumParams) Validate() error {
-	if s.ServiceName == "" {
+	if s.ServiceName == "" && s.ServiceID.IsNil() {
 		return errors.New("service name is empty")
 	}
 
@@ -68,10 +163,34 @@ func (s SubscriptionsSumParams) Validate() error {
 		}
 	}
 
+	if s.Period != nil {
+		if err := s.Period.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.TargetCurrency != "" {
+		if err := ValidateCurrency(s.TargetCurrency); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// MonthlySum is the amount charged within one calendar month, month is formatted as YYYY-MM.
+type MonthlySum struct {
+	Month  string `json:"month"`
+	Amount Money  `json:"amount"`
+}
+
+// UserSubscriptionsSum holds amounts converted to the requested currency, Original keeps
+// the totals in the currencies the subscriptions are priced in.
 type UserSubscriptionsSum struct {
-	UserID     uuid.UUID `json:"user_id"`
-	TotalPrice int       `json:"total_price"`
+	UserID     uuid.UUID    `json:"user_id"`
+	TotalPrice Money        `json:"total_price"`
+	Months     []MonthlySum `json:"months"`
+	Original   []Money      `json:"original"`
+	// PeriodPrice is what the matched subscriptions cost per requested period.
+	PeriodPrice *Money `json:"period_price,omitempty"`
 }
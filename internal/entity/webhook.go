@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"online-subscribe-rest-service/pkg/publicnet"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// MinWebhookSecretLength is the length of the shortest secret a webhook is signed with.
+const MinWebhookSecretLength = 16
+
+// Webhook is an endpoint integrators receive subscription events at.
+type Webhook struct {
+	ID  uuid.UUID `json:"id"`
+	URL string    `json:"url"`
+	// Events the endpoint receives, every event when empty.
+	Events []SubscriptionEventType `json:"events,omitempty"`
+	// UserID is the user whose subscription events the endpoint receives. It is required, webhooks
+	// registered before that may have none and receive the events of every user.
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	// Secret is the HMAC key of the X-Signature header. It is set when the webhook is registered
+	// and never returned afterwards.
+	Secret string `json:"secret,omitempty"`
+	// Enabled webhooks receive events, one is disabled after failing too many attempts in a row.
+	Enabled bool `json:"enabled"`
+	// Failures counts the attempts failed in a row.
+	Failures   int        `json:"failures"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (w Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q", w.URL)
+	}
+
+	if err := publicHost(u.Hostname()); err != nil {
+		return err
+	}
+
+	if w.UserID == nil || *w.UserID == uuid.Nil {
+		return errors.New("user_id is required")
+	}
+
+	if len(w.Secret) < MinWebhookSecretLength {
+		return fmt.Errorf("secret must be at least %d characters long", MinWebhookSecretLength)
+	}
+
+	for _, event := range w.Events {
+		if !slices.Contains(SubscriptionEventTypes, event) {
+			return fmt.Errorf("unknown event %q", event)
+		}
+	}
+
+	return nil
+}
+
+// publicHost rejects the hosts that are plainly not on the internet: localhost and the loopback,
+// private, link-local and unspecified addresses. A name resolving to such an address is refused
+// when it is dialed, see publicnet.Transport.
+func publicHost(host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("host %q is not public", host)
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil && !publicnet.Addr(addr) {
+		return fmt.Errorf("host %q is not public", host)
+	}
+
+	return nil
+}
+
+// Wants reports whether the webhook receives the event.
+func (w Webhook) Wants(e SubscriptionEvent) bool {
+	if w.UserID != nil && *w.UserID != e.UserID {
+		return false
+	}
+
+	return len(w.Events) == 0 || slices.Contains(w.Events, e.Type)
+}
+
+// WebhookDelivery is one subscription event sent to one webhook.
+type WebhookDelivery struct {
+	ID        uuid.UUID             `json:"id"`
+	WebhookID uuid.UUID             `json:"webhook_id"`
+	EventID   uuid.UUID             `json:"event_id"`
+	EventType SubscriptionEventType `json:"event_type"`
+	// Payload is the body posted to the webhook, the event itself.
+	Payload  json.RawMessage `json:"payload" swaggertype:"object"`
+	Status   DeliveryStatus  `json:"status"`
+	Attempts int             `json:"attempts"`
+	// ResponseStatus is the HTTP status the last attempt got, 0 when it got no response.
+	ResponseStatus int    `json:"response_status,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	// NextAttemptAt is when a pending delivery is tried next.
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestWebhookValidate(t *testing.T) {
+	user := uuid.Must(uuid.NewV4())
+	nilUser := uuid.Nil
+
+	tests := []struct {
+		name    string
+		url     string
+		userID  *uuid.UUID
+		wantErr bool
+	}{
+		{"public host", "https://example.com/hook", &user, false},
+		{"public address", "http://93.184.216.34/hook", &user, false},
+		{"no user", "https://example.com/hook", nil, true},
+		{"nil user", "https://example.com/hook", &nilUser, true},
+		{"not http", "ftp://example.com/hook", &user, true},
+		{"localhost", "http://localhost:8080/hook", &user, true},
+		{"localhost subdomain", "http://api.localhost/hook", &user, true},
+		{"loopback", "http://127.0.0.1/hook", &user, true},
+		{"ipv6 loopback", "http://[::1]/hook", &user, true},
+		{"mapped loopback", "http://[::ffff:127.0.0.1]/hook", &user, true},
+		{"private", "http://10.0.0.5/hook", &user, true},
+		{"private 192.168", "http://192.168.1.1/hook", &user, true},
+		{"ipv6 private", "http://[fd00::1]/hook", &user, true},
+		{"link-local", "http://169.254.169.254/latest/meta-data", &user, true},
+		{"unspecified", "http://0.0.0.0/hook", &user, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := Webhook{URL: tt.url, UserID: tt.userID, Secret: "0123456789abcdef"}
+
+			if err := w.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
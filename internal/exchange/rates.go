@@ -0,0 +1,212 @@
+package exchange
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"online-subscribe-rest-service/internal/entity"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StaticRates serves exchange rates known in advance, quoted against a single base currency by date.
+// A rate for a date without quotes is taken from the latest earlier date.
+type StaticRates struct {
+	base  string
+	dates []time.Time
+	rates map[time.Time]map[string]float64
+}
+
+// NewStaticRates creates rates where rates[date][currency] is the price of one base unit in currency.
+func NewStaticRates(base string, rates map[time.Time]map[string]float64) *StaticRates {
+	r := &StaticRates{
+		base:  base,
+		rates: make(map[time.Time]map[string]float64, len(rates)),
+	}
+
+	for date, quotes := range rates {
+		r.add(date, quotes)
+	}
+
+	return r
+}
+
+// LoadFile reads rates from a .json or .csv file.
+//
+// The JSON file looks like {"base": "RUB", "rates": {"2025-07-01": {"USD": 0.0127}}}.
+// The CSV file has a date,base,currency,rate header and the same base on every row.
+func LoadFile(path string) (*StaticRates, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("exchange: LoadFile: %w", err)
+	}
+
+	defer f.Close()
+
+	var rates *StaticRates
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		rates, err = readJSON(f)
+	case ".csv":
+		rates, err = readCSV(f)
+	default:
+		err = fmt.Errorf("unsupported rates file %s", path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("exchange: LoadFile: %w", err)
+	}
+
+	return rates, nil
+}
+
+// Rate returns how many units of to one unit of from buys on the given date.
+func (r *StaticRates) Rate(_ context.Context, from, to string, on time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	quotes := r.quotesOn(on)
+	if quotes == nil {
+		return 0, fmt.Errorf("%w: %s/%s on %s", entity.ErrRateNotFound, from, to, on.Format(time.DateOnly))
+	}
+
+	fromRate, ok := r.quote(quotes, from)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s/%s on %s", entity.ErrRateNotFound, from, to, on.Format(time.DateOnly))
+	}
+
+	toRate, ok := r.quote(quotes, to)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s/%s on %s", entity.ErrRateNotFound, from, to, on.Format(time.DateOnly))
+	}
+
+	return toRate / fromRate, nil
+}
+
+func (r *StaticRates) add(date time.Time, quotes map[string]float64) {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	if _, ok := r.rates[date]; !ok {
+		r.rates[date] = make(map[string]float64, len(quotes))
+		r.dates = append(r.dates, date)
+		sort.Slice(r.dates, func(i, j int) bool { return r.dates[i].Before(r.dates[j]) })
+	}
+
+	for currency, rate := range quotes {
+		r.rates[date][currency] = rate
+	}
+}
+
+// quotesOn returns the quotes of the latest date not after on.
+func (r *StaticRates) quotesOn(on time.Time) map[string]float64 {
+	i := sort.Search(len(r.dates), func(i int) bool { return r.dates[i].After(on) })
+	if i == 0 {
+		return nil
+	}
+
+	return r.rates[r.dates[i-1]]
+}
+
+func (r *StaticRates) quote(quotes map[string]float64, currency string) (float64, bool) {
+	if currency == r.base {
+		return 1, true
+	}
+
+	rate, ok := quotes[currency]
+	if !ok || rate <= 0 {
+		return 0, false
+	}
+
+	return rate, true
+}
+
+func readJSON(r io.Reader) (*StaticRates, error) {
+	var file struct {
+		Base  string                        `json:"base"`
+		Rates map[string]map[string]float64 `json:"rates"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+
+	if err := entity.ValidateCurrency(file.Base); err != nil {
+		return nil, err
+	}
+
+	rates := NewStaticRates(file.Base, nil)
+	for qDate, quotes := range file.Rates {
+		date, err := time.Parse(time.DateOnly, qDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %s: %w", qDate, err)
+		}
+
+		rates.add(date, quotes)
+	}
+
+	return rates, nil
+}
+
+func readCSV(r io.Reader) (*StaticRates, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Join(header, ",") != "date,base,currency,rate" {
+		return nil, errors.New("csv header must be date,base,currency,rate")
+	}
+
+	var rates *StaticRates
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		date, err := time.Parse(time.DateOnly, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %s: %w", record[0], err)
+		}
+
+		if rates == nil {
+			if err := entity.ValidateCurrency(record[1]); err != nil {
+				return nil, err
+			}
+
+			rates = NewStaticRates(record[1], nil)
+		}
+
+		if record[1] != rates.base {
+			return nil, fmt.Errorf("csv rates must share one base, got %s and %s", rates.base, record[1])
+		}
+
+		rate, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate %s: %w", record[3], err)
+		}
+
+		rates.add(date, map[string]float64{record[2]: rate})
+	}
+
+	if rates == nil {
+		return nil, errors.New("csv has no rates")
+	}
+
+	return rates, nil
+}
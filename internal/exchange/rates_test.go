@@ -0,0 +1,124 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"math"
+	"online-subscribe-rest-service/internal/entity"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "json",
+			file:    "rates.json",
+			content: `{"base": "RUB", "rates": {"2025-01-01": {"USD": 0.01}, "2025-07-01": {"USD": 0.0125}}}`,
+		},
+		{
+			name:    "csv",
+			file:    "rates.csv",
+			content: "date,base,currency,rate\n2025-01-01,RUB,USD,0.01\n2025-07-01,RUB,USD,0.0125\n",
+		},
+		{name: "json that doesn't parse", file: "rates.json", content: `{"base": "RUB", "rates": `, wantErr: true},
+		{name: "json without a base", file: "rates.json", content: `{"rates": {"2025-01-01": {"USD": 0.01}}}`, wantErr: true},
+		{name: "json with a bad date", file: "rates.json", content: `{"base": "RUB", "rates": {"01.01.2025": {"USD": 0.01}}}`, wantErr: true},
+		{name: "csv with a wrong header", file: "rates.csv", content: "day,base,currency,rate\n2025-01-01,RUB,USD,0.01\n", wantErr: true},
+		{name: "csv with a short row", file: "rates.csv", content: "date,base,currency,rate\n2025-01-01,RUB,USD\n", wantErr: true},
+		{name: "csv with a bad rate", file: "rates.csv", content: "date,base,currency,rate\n2025-01-01,RUB,USD,cheap\n", wantErr: true},
+		{name: "csv with two bases", file: "rates.csv", content: "date,base,currency,rate\n2025-01-01,RUB,USD,0.01\n2025-01-01,EUR,USD,1.1\n", wantErr: true},
+		{name: "csv without rates", file: "rates.csv", content: "date,base,currency,rate\n", wantErr: true},
+		{name: "unsupported extension", file: "rates.xml", content: "<rates/>", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			rates, err := LoadFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			// Both files quote the same rates, the second one from July on.
+			for on, want := range map[string]float64{"2025-03-01": 0.01, "2025-07-01": 0.0125} {
+				got, err := rates.Rate(context.Background(), "RUB", "USD", date(on))
+				if err != nil {
+					t.Fatalf("Rate() on %s error = %v", on, err)
+				}
+
+				if math.Abs(got-want) > 1e-9 {
+					t.Errorf("Rate() on %s = %v, want %v", on, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "rates.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("LoadFile() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestRate(t *testing.T) {
+	rates := NewStaticRates("RUB", map[time.Time]map[string]float64{
+		date("2025-01-01"): {"USD": 0.01, "EUR": 0.008},
+		date("2025-04-01"): {"USD": 0.0125},
+	})
+
+	tests := []struct {
+		name     string
+		from, to string
+		on       string
+		want     float64
+		wantErr  error
+	}{
+		{name: "same currency", from: "GBP", to: "GBP", on: "2024-01-01", want: 1},
+		{name: "quoted date", from: "RUB", to: "USD", on: "2025-01-01", want: 0.01},
+		{name: "latest earlier date", from: "RUB", to: "USD", on: "2025-03-31", want: 0.01},
+		{name: "latest earlier date after a later quote", from: "RUB", to: "USD", on: "2026-01-01", want: 0.0125},
+		{name: "into the base", from: "USD", to: "RUB", on: "2025-02-01", want: 100},
+		{name: "cross rate", from: "USD", to: "EUR", on: "2025-02-01", want: 0.8},
+		{name: "before the first date", from: "RUB", to: "USD", on: "2024-12-31", wantErr: entity.ErrRateNotFound},
+		{name: "unknown currency", from: "RUB", to: "GBP", on: "2025-02-01", wantErr: entity.ErrRateNotFound},
+		{name: "currency missing on the latest date", from: "RUB", to: "EUR", on: "2025-05-01", wantErr: entity.ErrRateNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Rate(context.Background(), tt.from, tt.to, date(tt.on))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Rate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Rate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
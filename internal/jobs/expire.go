@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/logger"
+	"time"
+)
+
+type ExpireService interface {
+	ExpireSubscriptions(ctx context.Context) ([]entity.Subscription, error)
+}
+
+// ExpireJob marks the subscriptions past their end date as expired.
+type ExpireJob struct {
+	log     logger.Logger
+	service ExpireService
+}
+
+func NewExpireJob(log logger.Logger, service ExpireService) *ExpireJob {
+	return &ExpireJob{
+		log:     log,
+		service: service,
+	}
+}
+
+// Run expires the ended subscriptions once.
+func (j *ExpireJob) Run(ctx context.Context) (string, error) {
+	subs, err := j.service.ExpireSubscriptions(ctx)
+
+	for _, sub := range subs {
+		j.log.InfoW("subscription expired", map[string]any{
+			"subscription_id": sub.ID.String(),
+			"user_id":         sub.UserID.String(),
+			"service_name":    sub.ServiceName,
+			"end_date":        sub.EndDate.Format(time.DateOnly),
+		})
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("jobs: failed to expire subscriptions after expiring %d: %w", len(subs), err)
+	}
+
+	return fmt.Sprintf("%d subscriptions expired", len(subs)), nil
+}
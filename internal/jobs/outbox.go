@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type OutboxPurger interface {
+	Purge(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// OutboxPurgeJob removes the subscription events published long enough ago from the outbox.
+type OutboxPurgeJob struct {
+	relay     OutboxPurger
+	retention time.Duration
+}
+
+// NewOutboxPurgeJob creates the job, it removes the events published longer than retention ago.
+func NewOutboxPurgeJob(relay OutboxPurger, retention time.Duration) *OutboxPurgeJob {
+	return &OutboxPurgeJob{
+		relay:     relay,
+		retention: retention,
+	}
+}
+
+// Run purges the outbox once.
+func (j *OutboxPurgeJob) Run(ctx context.Context) (string, error) {
+	purged, err := j.relay.Purge(ctx, j.retention)
+	if err != nil {
+		return "", fmt.Errorf("jobs: failed to purge outbox: %w", err)
+	}
+
+	return fmt.Sprintf("%d published events purged", purged), nil
+}
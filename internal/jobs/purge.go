@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type PurgeService interface {
+	PurgeSubscriptions(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// PurgeJob empties the trash of the subscriptions deleted long enough ago.
+type PurgeJob struct {
+	service   PurgeService
+	retention time.Duration
+}
+
+// NewPurgeJob creates the job, it removes the subscriptions that lay in the trash longer than retention.
+func NewPurgeJob(service PurgeService, retention time.Duration) *PurgeJob {
+	return &PurgeJob{
+		service:   service,
+		retention: retention,
+	}
+}
+
+// Run purges the trash once.
+func (j *PurgeJob) Run(ctx context.Context) (string, error) {
+	purged, err := j.service.PurgeSubscriptions(ctx, j.retention)
+	if err != nil {
+		return "", fmt.Errorf("jobs: failed to purge subscriptions: %w", err)
+	}
+
+	return fmt.Sprintf("%d subscriptions purged", purged), nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+)
+
+type RenewalsService interface {
+	RemindRenewals(ctx context.Context, days int) (int, error)
+}
+
+// RenewalsJob reminds users of the charges coming up.
+type RenewalsJob struct {
+	service RenewalsService
+	days    int
+}
+
+// NewRenewalsJob creates the job, it reminds of the charges due in days days.
+func NewRenewalsJob(service RenewalsService, days int) *RenewalsJob {
+	return &RenewalsJob{
+		service: service,
+		days:    days,
+	}
+}
+
+// Run sends the reminders once.
+func (j *RenewalsJob) Run(ctx context.Context) (string, error) {
+	reminded, err := j.service.RemindRenewals(ctx, j.days)
+	if err != nil {
+		return "", fmt.Errorf("jobs: failed to remind renewals: %w", err)
+	}
+
+	return fmt.Sprintf("%d renewals reminded", reminded), nil
+}
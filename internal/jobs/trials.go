@@ -0,0 +1,49 @@
+// Package jobs holds the background jobs the scheduler runs next to the HTTP server.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/logger"
+	"time"
+)
+
+type TrialsService interface {
+	FlagEndingTrials(ctx context.Context, days int) ([]entity.Subscription, error)
+}
+
+// TrialsJob flags subscriptions whose free trial is about to end.
+type TrialsJob struct {
+	log     logger.Logger
+	service TrialsService
+	days    int
+}
+
+// NewTrialsJob creates the job, it flags trials ending within days.
+func NewTrialsJob(log logger.Logger, service TrialsService, days int) *TrialsJob {
+	return &TrialsJob{
+		log:     log,
+		service: service,
+		days:    days,
+	}
+}
+
+// Run flags the ending trials once.
+func (j *TrialsJob) Run(ctx context.Context) (string, error) {
+	subs, err := j.service.FlagEndingTrials(ctx, j.days)
+	if err != nil {
+		return "", fmt.Errorf("jobs: failed to flag ending trials: %w", err)
+	}
+
+	for _, sub := range subs {
+		j.log.InfoW("trial is ending", map[string]any{
+			"subscription_id": sub.ID.String(),
+			"user_id":         sub.UserID.String(),
+			"service_name":    sub.ServiceName,
+			"trial_end":       sub.TrialEnd.Format(time.DateOnly),
+		})
+	}
+
+	return fmt.Sprintf("%d trials flagged", len(subs)), nil
+}
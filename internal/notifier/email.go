@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"online-subscribe-rest-service/internal/entity"
+	"strconv"
+	"time"
+)
+
+// Email sends messages as plain text e-mails through an SMTP server. Without a username it
+// doesn't authenticate, which is what a local MailHog expects.
+type Email struct {
+	addr     string
+	host     string
+	from     string
+	username string
+	password string
+	// timeout bounds a whole send, a server that stops answering fails it instead of hanging.
+	timeout time.Duration
+}
+
+func NewEmail(host string, port int, from, username, password string, timeout time.Duration) *Email {
+	return &Email{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		from:     from,
+		username: username,
+		password: password,
+		timeout:  timeout,
+	}
+}
+
+func (e *Email) Name() entity.NotificationChannel {
+	return entity.ChannelEmail
+}
+
+// Send hands the e-mail to the server. The connection gets a deadline of e.timeout, or of ctx when
+// it is sooner, and is cut when ctx is cancelled, the standard SMTP client taking no context.
+func (e *Email) Send(ctx context.Context, address string, msg Message) error {
+	dialer := net.Dialer{Timeout: e.timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", e.addr)
+	if err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+
+	deadline := time.Now().Add(e.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("send email: %w", err)
+	}
+
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, e.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("send email: %w", err)
+	}
+	defer c.Close()
+
+	if err := e.send(c, address, msg); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+
+	return nil
+}
+
+// send goes through the SMTP conversation smtp.SendMail has.
+func (e *Email) send(c *smtp.Client, address string, msg Message) error {
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: e.host}); err != nil {
+			return err
+		}
+	}
+
+	if e.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.username, e.password, e.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(e.from); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(address); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(e.compose(address, msg)); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func (e *Email) compose(to string, msg Message) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Text)
+	b.WriteString("\r\n")
+
+	return b.Bytes()
+}
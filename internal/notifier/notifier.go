@@ -0,0 +1,270 @@
+// Package notifier delivers notifications to users through pluggable channels, such as e-mail
+// and webhooks, retrying failed deliveries and logging every one of them.
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/logger"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Message is a notification about one event.
+type Message struct {
+	Event entity.NotificationEvent
+	// Key identifies the occurrence of the event, a message with a key reaches every address once.
+	Key     string
+	Subject string
+	Text    string
+	// Data is the event itself, webhooks receive it as JSON.
+	Data any
+}
+
+// Channel sends messages to addresses of one kind.
+type Channel interface {
+	Name() entity.NotificationChannel
+	Send(ctx context.Context, address string, msg Message) error
+}
+
+// Store keeps the users' preferences and the delivery log, the service reads them through service.NotificationRepo.
+type Store interface {
+	NotificationPreferences(ctx context.Context, userID uuid.UUID) ([]entity.NotificationPreference, error)
+	// CreateDelivery fails with entity.ErrAlreadyExists when the delivery's key was already used for the address.
+	CreateDelivery(ctx context.Context, d entity.Delivery) (uuid.UUID, error)
+	// UpdateDelivery records the status, attempts, last error, next attempt and update time of the delivery.
+	UpdateDelivery(ctx context.Context, d entity.Delivery) error
+	// DueDeliveries returns up to limit pending deliveries due by now, due first go first.
+	DueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.Delivery, error)
+}
+
+// Locker keeps replicas from sending at the same time, see scheduler.Locker.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// batchSize is how many due deliveries the dispatcher reads at once.
+const batchSize = 100
+
+// Dispatcher sends a message to every address the user wants it at. Dispatch only logs the deliveries,
+// Run sends them from the log: a failed one is retried with exponentially growing pauses until it
+// succeeds or runs out of attempts, and the ones still pending when the process stops are picked up
+// after a restart.
+type Dispatcher struct {
+	log      logger.Logger
+	store    Store
+	locker   Locker
+	channels map[entity.NotificationChannel]Channel
+	attempts int
+	backoff  time.Duration
+	interval time.Duration
+	// wake cuts the wait for the next poll short when a delivery was logged.
+	wake chan struct{}
+	now  func() time.Time
+}
+
+// NewDispatcher creates the dispatcher, a delivery is attempted up to attempts times and the pause
+// after the first failure is backoff, doubling with every further one. The log is polled every interval,
+// locker may be nil, see scheduler.Locker.
+func NewDispatcher(log logger.Logger, store Store, locker Locker, attempts int, backoff, interval time.Duration, channels ...Channel) *Dispatcher {
+	d := &Dispatcher{
+		log:      log,
+		store:    store,
+		locker:   locker,
+		channels: make(map[entity.NotificationChannel]Channel, len(channels)),
+		attempts: max(attempts, 1),
+		backoff:  backoff,
+		interval: interval,
+		wake:     make(chan struct{}, 1),
+		now:      time.Now,
+	}
+
+	for _, ch := range channels {
+		d.channels[ch.Name()] = ch
+	}
+
+	return d
+}
+
+// Dispatch logs a delivery for every address of the user that wants the message, Run sends them.
+// Addresses of channels the dispatcher has no implementation for are skipped.
+func (d *Dispatcher) Dispatch(ctx context.Context, userID uuid.UUID, msg Message) error {
+	prefs, err := d.store.NotificationPreferences(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("notifier: failed to get preferences of user %s: %w", userID, err)
+	}
+
+	var data json.RawMessage
+	if msg.Data != nil {
+		if data, err = json.Marshal(msg.Data); err != nil {
+			return fmt.Errorf("notifier: failed to encode %s notification: %w", msg.Event, err)
+		}
+	}
+
+	logged := false
+
+	for _, pref := range prefs {
+		if !pref.Wants(msg.Event) {
+			continue
+		}
+
+		if _, ok := d.channels[pref.Channel]; !ok {
+			d.log.WarnF("notifier: channel %s is not configured, %s notification to %s skipped", pref.Channel, msg.Event, pref.Address)
+			continue
+		}
+
+		now := d.now().UTC()
+		delivery := entity.Delivery{
+			UserID:        userID,
+			Channel:       pref.Channel,
+			Address:       pref.Address,
+			Event:         msg.Event,
+			Subject:       msg.Subject,
+			Key:           msg.Key,
+			Text:          msg.Text,
+			Data:          data,
+			Status:        entity.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		_, err = d.store.CreateDelivery(ctx, delivery)
+		if errors.Is(err, entity.ErrAlreadyExists) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("notifier: failed to log delivery: %w", err)
+		}
+
+		logged = true
+	}
+
+	if logged {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Run sends the due deliveries until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.sendDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// sendDue sends the due deliveries, batch after batch while whole batches are due.
+func (d *Dispatcher) sendDue(ctx context.Context) {
+	if d.locker != nil {
+		unlock, ok, err := d.locker.TryLock(ctx, "notifier")
+		if err != nil {
+			d.log.ErrorF("notifier: failed to take lock: %v", err)
+			return
+		}
+
+		// Another replica is sending.
+		if !ok {
+			return
+		}
+
+		defer unlock()
+	}
+
+	for ctx.Err() == nil {
+		deliveries, err := d.store.DueDeliveries(ctx, d.now().UTC(), batchSize)
+		if err != nil {
+			d.log.ErrorF("notifier: failed to read due deliveries: %v", err)
+			return
+		}
+
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				return
+			}
+
+			d.deliver(ctx, delivery)
+		}
+
+		if len(deliveries) < batchSize {
+			return
+		}
+	}
+}
+
+// deliver makes an attempt at the delivery and logs its outcome. A delivery whose channel is no longer
+// configured fails right away.
+func (d *Dispatcher) deliver(ctx context.Context, delivery entity.Delivery) {
+	msg := Message{
+		Event:   delivery.Event,
+		Key:     delivery.Key,
+		Subject: delivery.Subject,
+		Text:    delivery.Text,
+	}
+
+	if delivery.Data != nil {
+		msg.Data = delivery.Data
+	}
+
+	err := errors.New("channel is not configured")
+	if ch, ok := d.channels[delivery.Channel]; ok {
+		err = ch.Send(ctx, delivery.Address, msg)
+	}
+
+	// A send cut short by shutdown isn't an attempt, the delivery stays due for the next run.
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	now := d.now().UTC()
+
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+
+	switch {
+	case err == nil:
+		delivery.Status = entity.DeliverySent
+		delivery.LastError = ""
+	case delivery.Attempts < d.attempts:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(d.retryDelay(delivery.Attempts))
+	default:
+		delivery.Status = entity.DeliveryFailed
+		delivery.LastError = err.Error()
+
+		d.log.ErrorF("notifier: %s notification to %s failed after %d attempts: %s",
+			delivery.Event, delivery.Address, delivery.Attempts, delivery.LastError)
+	}
+
+	if err := d.store.UpdateDelivery(ctx, delivery); err != nil {
+		d.log.ErrorF("notifier: failed to log delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// retryDelay is the pause after the given number of failed attempts.
+func (d *Dispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.backoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+	}
+
+	return delay
+}
@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/publicnet"
+	"time"
+)
+
+// Webhook posts messages as JSON to the URL the user gave, public addresses only.
+type Webhook struct {
+	client *http.Client
+}
+
+func NewWebhook(timeout time.Duration) *Webhook {
+	return &Webhook{client: &http.Client{Timeout: timeout, Transport: publicnet.Transport()}}
+}
+
+func (w *Webhook) Name() entity.NotificationChannel {
+	return entity.ChannelWebhook
+}
+
+// webhookPayload is the body every webhook receives.
+type webhookPayload struct {
+	Event   entity.NotificationEvent `json:"event"`
+	Subject string                   `json:"subject"`
+	Text    string                   `json:"text"`
+	Data    any                      `json:"data,omitempty"`
+}
+
+// Send posts the message, any status but 2xx is a failure.
+func (w *Webhook) Send(ctx context.Context, address string, msg Message) error {
+	body, err := json.Marshal(webhookPayload{Event: msg.Event, Subject: msg.Subject, Text: msg.Text, Data: msg.Data})
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("post webhook: unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
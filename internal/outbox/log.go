@@ -0,0 +1,28 @@
+package outbox
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/logger"
+)
+
+// LogPublisher writes events to the log, for development and for services nobody listens to yet.
+type LogPublisher struct {
+	log logger.Logger
+}
+
+func NewLogPublisher(log logger.Logger) *LogPublisher {
+	return &LogPublisher{log: log}
+}
+
+func (p *LogPublisher) Publish(_ context.Context, event entity.SubscriptionEvent) error {
+	p.log.InfoW("subscription event", map[string]any{
+		"event_id":        event.ID.String(),
+		"type":            string(event.Type),
+		"subscription_id": event.SubscriptionID.String(),
+		"user_id":         event.UserID.String(),
+		"occurred_at":     event.OccurredAt,
+	})
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"online-subscribe-rest-service/internal/entity"
+)
+
+// Publishers publishes every event with each of the publishers. The event is published
+// again when any of them fails, so all of them have to put up with repeats.
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, event entity.SubscriptionEvent) error {
+	var errs []error
+
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
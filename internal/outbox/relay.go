@@ -0,0 +1,180 @@
+// Package outbox publishes the subscription events the service writes into the outbox table.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/logger"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Publisher hands an event over to the services interested in it. Publishing the same event
+// twice must be harmless, consumers tell the repeats apart by the event's ID.
+type Publisher interface {
+	Publish(ctx context.Context, event entity.SubscriptionEvent) error
+}
+
+// Store is the relay's side of the outbox, the service writes into it through service.OutboxRepo.
+type Store interface {
+	// PendingOutboxEvents returns up to limit unpublished events in the order they were added. The events
+	// of a subscription with an unpublished event held back past now are left out, so they don't crowd
+	// the batch.
+	PendingOutboxEvents(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEvent, error)
+	// MarkOutboxEventPublished takes the event out of the pending ones.
+	MarkOutboxEventPublished(ctx context.Context, seq int64, at time.Time) error
+	// MarkOutboxEventFailed counts a failed attempt to publish the event and holds it back until retryAt.
+	MarkOutboxEventFailed(ctx context.Context, seq int64, lastError string, retryAt time.Time) error
+	// PurgeOutboxEvents removes the events published before the time and returns how many it removed.
+	PurgeOutboxEvents(ctx context.Context, before time.Time) (int, error)
+}
+
+// Locker keeps replicas from relaying at the same time, see scheduler.Locker.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// maxBackoff caps the pause before publishing a failed event again.
+const maxBackoff = 30 * time.Minute
+
+// Relay moves events from the outbox to the publisher. An event leaves the outbox only once
+// it was published, so every event is published at least once. The events of a subscription
+// are published in the order they were written: while one of them fails, the later ones wait.
+type Relay struct {
+	log       logger.Logger
+	store     Store
+	publisher Publisher
+	locker    Locker
+	interval  time.Duration
+	batchSize int
+	backoff   time.Duration
+	now       func() time.Time
+}
+
+// NewRelay creates the relay, it polls the outbox every interval for up to batchSize events and
+// retries a failed event after backoff, doubled with every failure. locker may be nil, see scheduler.Locker.
+func NewRelay(log logger.Logger, store Store, publisher Publisher, locker Locker, interval time.Duration, batchSize int, backoff time.Duration) *Relay {
+	return &Relay{
+		log:       log,
+		store:     store,
+		publisher: publisher,
+		locker:    locker,
+		interval:  interval,
+		batchSize: batchSize,
+		backoff:   backoff,
+		now:       time.Now,
+	}
+}
+
+// Run relays the events until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.relay(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay publishes the due events, batch after batch while whole batches go out.
+func (r *Relay) relay(ctx context.Context) {
+	if r.locker != nil {
+		unlock, ok, err := r.locker.TryLock(ctx, "outbox")
+		if err != nil {
+			r.log.ErrorF("outbox: failed to take lock: %v", err)
+			return
+		}
+
+		// Another replica is relaying.
+		if !ok {
+			return
+		}
+
+		defer unlock()
+	}
+
+	for {
+		events, err := r.store.PendingOutboxEvents(ctx, r.now().UTC(), r.batchSize)
+		if err != nil {
+			r.log.ErrorF("outbox: failed to read pending events: %v", err)
+			return
+		}
+
+		published := r.publish(ctx, events)
+
+		if len(events) < r.batchSize || published == 0 {
+			return
+		}
+	}
+}
+
+// publish publishes the batch in order and returns how many events went out. A subscription
+// whose event fails has the rest of its events skipped until the retry.
+func (r *Relay) publish(ctx context.Context, events []entity.OutboxEvent) int {
+	blocked := make(map[uuid.UUID]bool)
+	published := 0
+
+	for _, e := range events {
+		subscriptionID := e.Event.SubscriptionID
+
+		if blocked[subscriptionID] {
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, e.Event); err != nil {
+			blocked[subscriptionID] = true
+
+			retryAt := r.now().Add(r.retryDelay(e.Attempts))
+
+			r.log.WarnF("outbox: failed to publish event %s, retrying at %s: %v", e.Event.ID, retryAt.Format(time.RFC3339), err)
+
+			if err := r.store.MarkOutboxEventFailed(ctx, e.Seq, err.Error(), retryAt); err != nil {
+				r.log.ErrorF("outbox: failed to record failure of event %s: %v", e.Event.ID, err)
+			}
+
+			continue
+		}
+
+		if err := r.store.MarkOutboxEventPublished(ctx, e.Seq, r.now().UTC()); err != nil {
+			// The event stays pending and goes out again, which at-least-once delivery allows.
+			r.log.ErrorF("outbox: failed to mark event %s published: %v", e.Event.ID, err)
+			blocked[subscriptionID] = true
+
+			continue
+		}
+
+		published++
+	}
+
+	return published
+}
+
+// retryDelay is the pause after the attempt following the given number of failed ones.
+func (r *Relay) retryDelay(attempts int) time.Duration {
+	delay := r.backoff
+
+	for i := 0; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
+
+// Purge removes the events published longer than retention ago, the relay never reads them again.
+// It suits the scheduler as a job.
+func (r *Relay) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	purged, err := r.store.PurgeOutboxEvents(ctx, r.now().UTC().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("outbox: failed to purge published events: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const budgetColumns = "id, user_id, period, category, limit_minor, limit_currency"
+
+func (r *SubscriptionRepo) CreateBudget(ctx context.Context, b entity.Budget) (uuid.UUID, error) {
+	id := uuid.Must(uuid.NewV4())
+
+	query := `
+	INSERT INTO budgets (` + budgetColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, id, b.UserID, b.Period, b.Category, b.Limit.Amount, b.Limit.Currency)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository: CreateBudget: %w", uniqueError(err))
+	}
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) UpdateBudget(ctx context.Context, b entity.Budget) error {
+	query := `
+	UPDATE budgets
+	SET period = $1, category = $2, limit_minor = $3, limit_currency = $4
+	WHERE id = $5
+	`
+
+	tag, err := r.conn(ctx).Exec(ctx, query, b.Period, b.Category, b.Limit.Amount, b.Limit.Currency, b.ID)
+	if err != nil {
+		return fmt.Errorf("repository: UpdateBudget: %w", uniqueError(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.conn(ctx).Exec(ctx, `DELETE FROM budgets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repository: DeleteBudget: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) BudgetByID(ctx context.Context, id uuid.UUID) (entity.Budget, error) {
+	budgets, err := r.budgets(ctx, sq.Eq{"id": id})
+	if err != nil {
+		return entity.Budget{}, fmt.Errorf("repository: BudgetByID: %w", err)
+	}
+
+	if len(budgets) == 0 {
+		return entity.Budget{}, entity.ErrNotFound
+	}
+
+	return budgets[0], nil
+}
+
+func (r *SubscriptionRepo) Budgets(ctx context.Context, userID uuid.UUID) ([]entity.Budget, error) {
+	budgets, err := r.budgets(ctx, sq.Eq{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("repository: Budgets: %w", err)
+	}
+
+	return budgets, nil
+}
+
+func (r *SubscriptionRepo) budgets(ctx context.Context, where sq.Sqlizer) ([]entity.Budget, error) {
+	sqlQuery, args, err := sq.Select(budgetColumns).PlaceholderFormat(sq.Dollar).
+		From("budgets").
+		Where(where).
+		OrderBy("period", `category COLLATE "C"`).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := []entity.Budget{}
+	for rows.Next() {
+		var b entity.Budget
+
+		if err := rows.Scan(&b.ID, &b.UserID, &b.Period, &b.Category, &b.Limit.Amount, &b.Limit.Currency); err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
+		}
+
+		budgets = append(budgets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err() %w", err)
+	}
+
+	return budgets, nil
+}
+
+// uniqueError reports a unique constraint violation as entity.ErrAlreadyExists.
+func uniqueError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return entity.ErrAlreadyExists
+	}
+
+	return err
+}
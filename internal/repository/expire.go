@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+)
+
+func (r *SubscriptionRepo) EndedSubscriptions(ctx context.Context, before time.Time) ([]entity.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE status <> $1
+	AND end_date < $2
+	AND deleted_at IS NULL
+	ORDER BY end_date, id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, entity.StatusExpired, before)
+	if err != nil {
+		return nil, fmt.Errorf("repository: EndedSubscriptions: %w", err)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("repository: EndedSubscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
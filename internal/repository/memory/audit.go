@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+)
+
+// auditEntry keeps the changes encoded like the Postgres changes column does.
+type auditEntry struct {
+	entity.AuditEntry
+	changes []byte
+}
+
+func (r *SubscriptionRepo) AddAuditEntry(ctx context.Context, e entity.AuditEntry) error {
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return fmt.Errorf("repository: AddAuditEntry: %w", err)
+	}
+
+	e.Changes = nil
+
+	r.write(ctx, func() {
+		r.auditSeq++
+		e.ID = r.auditSeq
+		r.audit = append(r.audit, auditEntry{AuditEntry: e, changes: changes})
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) AuditEntries(_ context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []entity.AuditEntry{}
+	for _, entry := range slices.Backward(r.audit) {
+		if len(entries) == filter.Limit {
+			break
+		}
+
+		if !auditMatches(entry.AuditEntry, filter) {
+			continue
+		}
+
+		e := entry.AuditEntry
+		if err := json.Unmarshal(entry.changes, &e.Changes); err != nil {
+			return nil, fmt.Errorf("repository: AuditEntries: entry %d: %w", e.ID, err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func auditMatches(e entity.AuditEntry, filter entity.AuditFilter) bool {
+	switch {
+	case filter.EntityType != "" && e.EntityType != filter.EntityType,
+		filter.EntityID != nil && e.EntityID != *filter.EntityID,
+		filter.Actor != "" && e.Actor != filter.Actor,
+		filter.RequestID != "" && e.RequestID != filter.RequestID,
+		filter.Action != "" && e.Action != filter.Action,
+		filter.From != nil && e.OccurredAt.Before(*filter.From),
+		filter.To != nil && !e.OccurredAt.Before(*filter.To),
+		filter.Before != nil && e.ID >= *filter.Before:
+		return false
+	}
+
+	return true
+}
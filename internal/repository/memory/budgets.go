@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) CreateBudget(ctx context.Context, b entity.Budget) (uuid.UUID, error) {
+	var err error
+
+	b.ID = uuid.Must(uuid.NewV4())
+
+	r.write(ctx, func() {
+		if r.budgetTaken(b) {
+			err = entity.ErrAlreadyExists
+			return
+		}
+
+		r.budgets[b.ID] = b
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return b.ID, nil
+}
+
+func (r *SubscriptionRepo) UpdateBudget(ctx context.Context, b entity.Budget) error {
+	var err error
+
+	r.write(ctx, func() {
+		current, ok := r.budgets[b.ID]
+		if !ok {
+			err = entity.ErrNotFound
+			return
+		}
+
+		b.UserID = current.UserID
+		if r.budgetTaken(b) {
+			err = entity.ErrAlreadyExists
+			return
+		}
+
+		r.budgets[b.ID] = b
+	})
+
+	return err
+}
+
+func (r *SubscriptionRepo) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	var err error
+
+	r.write(ctx, func() {
+		if _, ok := r.budgets[id]; !ok {
+			err = entity.ErrNotFound
+			return
+		}
+
+		delete(r.budgets, id)
+	})
+
+	return err
+}
+
+func (r *SubscriptionRepo) BudgetByID(_ context.Context, id uuid.UUID) (entity.Budget, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	b, ok := r.budgets[id]
+	if !ok {
+		return entity.Budget{}, entity.ErrNotFound
+	}
+
+	return b, nil
+}
+
+func (r *SubscriptionRepo) Budgets(_ context.Context, userID uuid.UUID) ([]entity.Budget, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	budgets := []entity.Budget{}
+	for _, b := range r.budgets {
+		if b.UserID == userID {
+			budgets = append(budgets, b)
+		}
+	}
+
+	slices.SortFunc(budgets, func(a, b entity.Budget) int {
+		return cmp.Or(cmp.Compare(a.Period, b.Period), cmp.Compare(a.Category, b.Category))
+	})
+
+	return budgets, nil
+}
+
+// budgetTaken reports whether another budget of the user has b's period and category.
+func (r *SubscriptionRepo) budgetTaken(b entity.Budget) bool {
+	for id, other := range r.budgets {
+		if id != b.ID && other.UserID == b.UserID && other.Period == b.Period && other.Category == b.Category {
+			return true
+		}
+	}
+
+	return false
+}
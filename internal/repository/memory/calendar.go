@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) SetCalendarToken(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	r.write(ctx, func() {
+		r.calendarTokens[userID] = tokenHash
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DeleteCalendarToken(ctx context.Context, userID uuid.UUID) error {
+	r.write(ctx, func() {
+		delete(r.calendarTokens, userID)
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) CalendarUser(_ context.Context, tokenHash string) (uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for userID, hash := range r.calendarTokens {
+		if hash == tokenHash {
+			return userID, nil
+		}
+	}
+
+	return uuid.Nil, entity.ErrNotFound
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) CreateCatalogEntry(ctx context.Context, e entity.CatalogEntry) (uuid.UUID, error) {
+	id := uuid.Must(uuid.NewV4())
+
+	var err error
+	r.write(ctx, func() {
+		e.ID = id
+		if err = r.claimNames(e); err != nil {
+			return
+		}
+
+		r.services[id] = cloneEntry(e)
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository: CreateCatalogEntry: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) UpdateCatalogEntry(ctx context.Context, e entity.CatalogEntry) error {
+	var err error
+	r.write(ctx, func() {
+		if _, ok := r.services[e.ID]; !ok {
+			err = entity.ErrNotFound
+			return
+		}
+
+		released := r.releaseNames(e.ID)
+		if err = r.claimNames(e); err != nil {
+			for key := range released {
+				r.serviceNames[key] = e.ID
+			}
+			return
+		}
+
+		r.services[e.ID] = cloneEntry(e)
+
+		for id, s := range r.subscriptions {
+			if s.ServiceID == e.ID && s.ServiceName != e.Name {
+				s.Version++
+				s.ServiceName = e.Name
+				r.subscriptions[id] = s
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("repository: UpdateCatalogEntry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DeleteCatalogEntry(ctx context.Context, id uuid.UUID) error {
+	var err error
+	r.write(ctx, func() {
+		for _, s := range r.subscriptions {
+			if s.ServiceID == id {
+				err = entity.ErrServiceInUse
+				return
+			}
+		}
+
+		r.releaseNames(id)
+		delete(r.services, id)
+	})
+	if err != nil {
+		return fmt.Errorf("repository: DeleteCatalogEntry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) CatalogEntryByID(_ context.Context, id uuid.UUID) (entity.CatalogEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.services[id]
+	if !ok {
+		return entity.CatalogEntry{}, entity.ErrNotFound
+	}
+
+	return cloneEntry(e), nil
+}
+
+func (r *SubscriptionRepo) CatalogEntryByName(ctx context.Context, name string) (entity.CatalogEntry, error) {
+	r.mu.RLock()
+	id, ok := r.serviceNames[entity.NameKey(name)]
+	r.mu.RUnlock()
+
+	if !ok {
+		return entity.CatalogEntry{}, entity.ErrNotFound
+	}
+
+	return r.CatalogEntryByID(ctx, id)
+}
+
+func (r *SubscriptionRepo) CatalogEntries(_ context.Context) ([]entity.CatalogEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]entity.CatalogEntry, 0, len(r.services))
+	for _, e := range r.services {
+		entries = append(entries, cloneEntry(e))
+	}
+
+	slices.SortFunc(entries, func(a, b entity.CatalogEntry) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+
+		return bytes.Compare(a.ID.Bytes(), b.ID.Bytes())
+	})
+
+	return entries, nil
+}
+
+// claimNames registers the names of e, leaving the index untouched when one of them is taken.
+func (r *SubscriptionRepo) claimNames(e entity.CatalogEntry) error {
+	for _, name := range e.Names() {
+		if _, taken := r.serviceNames[entity.NameKey(name)]; taken {
+			return fmt.Errorf("%w: service name %q", entity.ErrAlreadyExists, name)
+		}
+	}
+
+	for _, name := range e.Names() {
+		r.serviceNames[entity.NameKey(name)] = e.ID
+	}
+
+	return nil
+}
+
+// releaseNames drops the names of the service from the index and returns their keys.
+func (r *SubscriptionRepo) releaseNames(id uuid.UUID) map[string]bool {
+	released := make(map[string]bool)
+	for key, serviceID := range r.serviceNames {
+		if serviceID == id {
+			released[key] = true
+			delete(r.serviceNames, key)
+		}
+	}
+
+	return released
+}
+
+func cloneEntry(e entity.CatalogEntry) entity.CatalogEntry {
+	e.Aliases = slices.Clone(e.Aliases)
+	if e.Aliases == nil {
+		e.Aliases = []string{}
+	}
+
+	if e.DefaultPrice != nil {
+		price := *e.DefaultPrice
+		e.DefaultPrice = &price
+	}
+
+	return e
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+	"time"
+)
+
+func (r *SubscriptionRepo) EndedSubscriptions(ctx context.Context, before time.Time) ([]entity.Subscription, error) {
+	before = dateOf(before)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ended []entity.Subscription
+	for _, id := range r.order {
+		s := r.subscriptions[id]
+		if s.DeletedAt == nil && s.Status != entity.StatusExpired && s.EndDate != nil && s.EndDate.Before(before) {
+			ended = append(ended, clone(s))
+		}
+	}
+
+	slices.SortFunc(ended, func(a, b entity.Subscription) int {
+		if c := a.EndDate.Compare(*b.EndDate); c != 0 {
+			return c
+		}
+
+		return bytes.Compare(a.ID.Bytes(), b.ID.Bytes())
+	})
+
+	return ended, nil
+}
@@ -0,0 +1,439 @@
+// Package memory keeps subscriptions in process memory, for tests and running the service without a database.
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"maps"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type txKey struct{}
+
+// SubscriptionRepo mirrors repository.SubscriptionRepo on top of a map.
+//
+// Transactions are serialized: WithTx holds txMu for the whole call and mutations made
+// outside a transaction wait for it, so a rollback never loses somebody else's write.
+type SubscriptionRepo struct {
+	txMu sync.Mutex
+
+	mu sync.RWMutex
+	*state
+}
+
+// state holds every table of the repository, WithTx restores a copy of it on rollback.
+type state struct {
+	subscriptions map[uuid.UUID]entity.Subscription
+	// order keeps insertion order, the order a fresh Postgres table returns rows in.
+	order         []uuid.UUID
+	statusHistory []entity.StatusChange
+	prices        []entity.PricePeriod
+	// tags holds the sorted tags of every tagged subscription.
+	tags     map[uuid.UUID][]string
+	services map[uuid.UUID]entity.CatalogEntry
+	// serviceNames indexes the services by the keys of their names and aliases.
+	serviceNames map[string]uuid.UUID
+	// calendarTokens maps users to the hashes of their calendar feed tokens.
+	calendarTokens map[uuid.UUID]string
+	budgets        map[uuid.UUID]entity.Budget
+	// preferences holds the notification preferences of every user who has any.
+	preferences map[uuid.UUID][]entity.NotificationPreference
+	deliveries  []entity.Delivery
+	outbox      []outboxEntry
+	// outboxSeq is the Seq of the last event added to the outbox.
+	outboxSeq         int64
+	webhooks          []entity.Webhook
+	webhookDeliveries []entity.WebhookDelivery
+	audit             []auditEntry
+	// auditSeq is the ID of the last audit entry.
+	auditSeq int64
+}
+
+func NewSubscriptionRepo() *SubscriptionRepo {
+	return &SubscriptionRepo{
+		state: &state{
+			subscriptions:  make(map[uuid.UUID]entity.Subscription),
+			tags:           make(map[uuid.UUID][]string),
+			services:       make(map[uuid.UUID]entity.CatalogEntry),
+			serviceNames:   make(map[string]uuid.UUID),
+			calendarTokens: make(map[uuid.UUID]string),
+			budgets:        make(map[uuid.UUID]entity.Budget),
+			preferences:    make(map[uuid.UUID][]entity.NotificationPreference),
+		},
+	}
+}
+
+// WithTx runs fn with the repository locked against other transactions and mutations.
+// Changes made by fn are undone when it returns an error. Nested calls reuse the outer transaction.
+func (r *SubscriptionRepo) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTx(ctx) {
+		return fn(ctx)
+	}
+
+	r.txMu.Lock()
+	defer r.txMu.Unlock()
+
+	snapshot := r.snapshot()
+
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		r.mu.Lock()
+		r.state = snapshot
+		r.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) CreateSubscription(ctx context.Context, s entity.Subscription) (uuid.UUID, error) {
+	id := uuid.Must(uuid.NewV4())
+
+	r.write(ctx, func() {
+		s.ID, s.Version, s.DeletedAt = id, 1, nil
+		r.subscriptions[id] = stored(s)
+		r.order = append(r.order, id)
+	})
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) UpdateSubscription(ctx context.Context, s entity.Subscription) error {
+	var err error
+
+	r.write(ctx, func() {
+		current, ok := r.subscriptions[s.ID]
+		if !ok || current.DeletedAt != nil || current.Version != s.Version {
+			err = entity.ErrVersionMismatch
+			return
+		}
+
+		s.Version, s.DeletedAt = current.Version+1, nil
+		r.subscriptions[s.ID] = stored(s)
+	})
+
+	return err
+}
+
+func (r *SubscriptionRepo) DeleteSubscription(ctx context.Context, id uuid.UUID, version int64, at time.Time) error {
+	var err error
+
+	r.write(ctx, func() {
+		s, ok := r.subscriptions[id]
+		if !ok || s.DeletedAt != nil || s.Version != version {
+			err = entity.ErrVersionMismatch
+			return
+		}
+
+		s.Version++
+		s.DeletedAt = &at
+		r.subscriptions[id] = clone(s)
+	})
+
+	return err
+}
+
+func (r *SubscriptionRepo) SubscriptionByID(_ context.Context, id uuid.UUID) (entity.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.subscriptions[id]
+	if !ok || s.DeletedAt != nil {
+		return entity.Subscription{}, entity.ErrNotFound
+	}
+
+	return clone(s), nil
+}
+
+// SubscriptionForUpdate needs no row lock, the surrounding WithTx already excludes other writers.
+func (r *SubscriptionRepo) SubscriptionForUpdate(ctx context.Context, id uuid.UUID) (entity.Subscription, error) {
+	return r.SubscriptionByID(ctx, id)
+}
+
+func (r *SubscriptionRepo) SubscriptionsList(_ context.Context, params entity.SubscriptionsListParams) ([]entity.Subscription, error) {
+	subscriptions := r.filter(func(s entity.Subscription) bool {
+		if s.UserID != params.UserID {
+			return false
+		}
+
+		if params.ServiceName != "" && s.ServiceName != params.ServiceName {
+			return false
+		}
+
+		if params.ActiveOn != nil {
+			on := dateOf(*params.ActiveOn)
+			if s.StartDate.After(on) || (s.EndDate != nil && s.EndDate.Before(on)) {
+				return false
+			}
+		}
+
+		if !inPriceRange(s.Price, params.Currency, params.PriceMin, params.PriceMax) {
+			return false
+		}
+
+		if params.HasEndDate != nil && *params.HasEndDate != (s.EndDate != nil) {
+			return false
+		}
+
+		if params.TrialOn != nil && !s.InTrial(dateOf(*params.TrialOn)) {
+			return false
+		}
+
+		if params.Category != "" && s.Category != params.Category {
+			return false
+		}
+
+		for _, tag := range params.Tags {
+			if !slices.Contains(r.tags[s.ID], tag) {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	return page(subscriptions, params.Sort, params.Desc, params.Cursor, params.Limit), nil
+}
+
+func (r *SubscriptionRepo) SearchSubscriptions(_ context.Context, filter entity.SubscriptionFilter) ([]entity.Subscription, int, error) {
+	serviceName := strings.ToLower(filter.ServiceName)
+
+	subscriptions := r.filter(func(s entity.Subscription) bool {
+		if !strings.Contains(strings.ToLower(s.ServiceName), serviceName) {
+			return false
+		}
+
+		if len(filter.UserIDs) > 0 && !slices.Contains(filter.UserIDs, s.UserID) {
+			return false
+		}
+
+		if filter.ActiveTo != nil && s.StartDate.After(dateOf(*filter.ActiveTo)) {
+			return false
+		}
+
+		if filter.ActiveFrom != nil && s.EndDate != nil && s.EndDate.Before(dateOf(*filter.ActiveFrom)) {
+			return false
+		}
+
+		return inPriceRange(s.Price, filter.Currency, filter.PriceMin, filter.PriceMax)
+	})
+
+	return page(subscriptions, filter.Sort, filter.Desc, filter.Cursor, filter.Limit), len(subscriptions), nil
+}
+
+func inPriceRange(price entity.Money, currency string, priceMin, priceMax *int64) bool {
+	if currency != "" && price.Currency != currency {
+		return false
+	}
+
+	if priceMin != nil && price.Amount < *priceMin {
+		return false
+	}
+
+	return priceMax == nil || price.Amount <= *priceMax
+}
+
+// page sorts subscriptions and returns up to limit of them, starting right after cursor.
+func page(subscriptions []entity.Subscription, sortBy entity.SubscriptionSort, desc bool, cursor *entity.ListCursor, limit int) []entity.Subscription {
+	if cursor != nil {
+		subscriptions = slices.DeleteFunc(subscriptions, func(s entity.Subscription) bool {
+			return compare(sortBy, s, *cursor, desc) <= 0
+		})
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return compare(sortBy, subscriptions[i], entity.NewListCursor(sortBy, desc, subscriptions[j]), desc) < 0
+	})
+
+	if len(subscriptions) > limit {
+		subscriptions = subscriptions[:limit]
+	}
+
+	return subscriptions
+}
+
+// compare orders s against the position of cursor in a list sorted by sortBy, it is positive when s comes after it.
+func compare(sortBy entity.SubscriptionSort, s entity.Subscription, cursor entity.ListCursor, desc bool) int {
+	var c int
+
+	switch sortBy {
+	case entity.SortPrice:
+		price, _ := cursor.PriceValue()
+		c = cmp.Compare(s.Price.Amount, price)
+	default:
+		c = strings.Compare(sortBy.Value(s), cursor.Value)
+	}
+
+	if c == 0 {
+		c = bytes.Compare(s.ID.Bytes(), cursor.ID.Bytes())
+	}
+
+	if desc {
+		return -c
+	}
+
+	return c
+}
+
+func (r *SubscriptionRepo) SubscriptionsInPeriod(_ context.Context, params entity.SubscriptionsSumParams) ([]entity.Subscription, error) {
+	subscriptions := r.filter(func(s entity.Subscription) bool {
+		if s.UserID != params.UserID || (!params.ServiceID.IsNil() && s.ServiceID != params.ServiceID) {
+			return false
+		}
+
+		if s.EndDate != nil && s.EndDate.Before(params.StartDate) {
+			return false
+		}
+
+		return params.EndDate == nil || !s.StartDate.After(*params.EndDate)
+	})
+
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		if !subscriptions[i].StartDate.Equal(subscriptions[j].StartDate) {
+			return subscriptions[i].StartDate.Before(subscriptions[j].StartDate)
+		}
+
+		return subscriptions[i].ID.String() < subscriptions[j].ID.String()
+	})
+
+	return subscriptions, nil
+}
+
+// filter returns copies of the subscriptions out of the trash matching keep in insertion order,
+// nil when nothing matches.
+func (r *SubscriptionRepo) filter(keep func(entity.Subscription) bool) []entity.Subscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var subscriptions []entity.Subscription
+	for _, id := range r.order {
+		if s := r.subscriptions[id]; s.DeletedAt == nil && keep(s) {
+			subscriptions = append(subscriptions, clone(s))
+		}
+	}
+
+	return subscriptions
+}
+
+// write applies a mutation, outside a transaction it first waits for the running one to finish.
+func (r *SubscriptionRepo) write(ctx context.Context, mutate func()) {
+	if !inTx(ctx) {
+		r.txMu.Lock()
+		defer r.txMu.Unlock()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mutate()
+}
+
+func (r *SubscriptionRepo) snapshot() *state {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.state.clone()
+}
+
+// clone deep-copies the state.
+func (st *state) clone() *state {
+	subscriptions := make(map[uuid.UUID]entity.Subscription, len(st.subscriptions))
+	for id, s := range st.subscriptions {
+		subscriptions[id] = clone(s)
+	}
+
+	tags := make(map[uuid.UUID][]string, len(st.tags))
+	for id, subscriptionTags := range st.tags {
+		tags[id] = slices.Clone(subscriptionTags)
+	}
+
+	services := make(map[uuid.UUID]entity.CatalogEntry, len(st.services))
+	for id, e := range st.services {
+		services[id] = cloneEntry(e)
+	}
+
+	preferences := make(map[uuid.UUID][]entity.NotificationPreference, len(st.preferences))
+	for userID, prefs := range st.preferences {
+		preferences[userID] = slices.Clone(prefs)
+	}
+
+	webhooks := make([]entity.Webhook, 0, len(st.webhooks))
+	for _, w := range st.webhooks {
+		webhooks = append(webhooks, cloneWebhook(w))
+	}
+
+	return &state{
+		subscriptions:     subscriptions,
+		order:             slices.Clone(st.order),
+		statusHistory:     slices.Clone(st.statusHistory),
+		prices:            slices.Clone(st.prices),
+		tags:              tags,
+		services:          services,
+		serviceNames:      maps.Clone(st.serviceNames),
+		calendarTokens:    maps.Clone(st.calendarTokens),
+		budgets:           maps.Clone(st.budgets),
+		preferences:       preferences,
+		deliveries:        slices.Clone(st.deliveries),
+		outbox:            slices.Clone(st.outbox),
+		outboxSeq:         st.outboxSeq,
+		webhooks:          webhooks,
+		webhookDeliveries: slices.Clone(st.webhookDeliveries),
+		audit:             slices.Clone(st.audit),
+		auditSeq:          st.auditSeq,
+	}
+}
+
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(bool)
+	return ok
+}
+
+// stored returns s the way a date column keeps it: start and end dates without the clock part.
+func stored(s entity.Subscription) entity.Subscription {
+	s = clone(s)
+
+	s.StartDate = dateOf(s.StartDate)
+	for _, date := range []*time.Time{s.EndDate, s.TrialStart, s.TrialEnd} {
+		if date != nil {
+			*date = dateOf(*date)
+		}
+	}
+
+	return s
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// clone copies the pointer fields so callers can't change stored subscriptions.
+func clone(s entity.Subscription) entity.Subscription {
+	s.EndDate = copyTime(s.EndDate)
+	s.TrialStart = copyTime(s.TrialStart)
+	s.TrialEnd = copyTime(s.TrialEnd)
+	s.TrialEndingFlaggedAt = copyTime(s.TrialEndingFlaggedAt)
+	s.DeletedAt = copyTime(s.DeletedAt)
+
+	s.Tags = nil
+	s.PeriodPrice = nil
+	s.PriceEffectiveFrom = nil
+
+	return s
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	copied := *t
+	return &copied
+}
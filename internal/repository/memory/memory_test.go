@@ -0,0 +1,13 @@
+package memory_test
+
+import (
+	"online-subscribe-rest-service/internal/repository/memory"
+	"online-subscribe-rest-service/internal/repository/repotest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repo {
+		return memory.NewSubscriptionRepo()
+	})
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) NotificationPreferences(_ context.Context, userID uuid.UUID) ([]entity.NotificationPreference, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prefs := []entity.NotificationPreference{}
+	for _, p := range r.preferences[userID] {
+		p.Events = slices.Clone(p.Events)
+		prefs = append(prefs, p)
+	}
+
+	return prefs, nil
+}
+
+func (r *SubscriptionRepo) SetNotificationPreferences(ctx context.Context, userID uuid.UUID, prefs []entity.NotificationPreference) error {
+	stored := make([]entity.NotificationPreference, 0, len(prefs))
+	for _, p := range prefs {
+		p.Events = slices.Clone(p.Events)
+		stored = append(stored, p)
+	}
+
+	slices.SortFunc(stored, func(a, b entity.NotificationPreference) int {
+		return cmp.Or(cmp.Compare(a.Channel, b.Channel), cmp.Compare(a.Address, b.Address))
+	})
+
+	r.write(ctx, func() {
+		if len(stored) == 0 {
+			delete(r.preferences, userID)
+			return
+		}
+
+		r.preferences[userID] = stored
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) CreateDelivery(ctx context.Context, d entity.Delivery) (uuid.UUID, error) {
+	var err error
+
+	d.ID = uuid.Must(uuid.NewV4())
+	d.Data = bytes.Clone(d.Data)
+
+	r.write(ctx, func() {
+		if d.Key != "" && slices.ContainsFunc(r.deliveries, func(other entity.Delivery) bool {
+			return other.UserID == d.UserID && other.Channel == d.Channel && other.Address == d.Address && other.Key == d.Key
+		}) {
+			err = entity.ErrAlreadyExists
+			return
+		}
+
+		r.deliveries = append(r.deliveries, d)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return d.ID, nil
+}
+
+func (r *SubscriptionRepo) UpdateDelivery(ctx context.Context, d entity.Delivery) error {
+	r.write(ctx, func() {
+		for i := range r.deliveries {
+			if r.deliveries[i].ID == d.ID {
+				r.deliveries[i].Status = d.Status
+				r.deliveries[i].Attempts = d.Attempts
+				r.deliveries[i].LastError = d.LastError
+				r.deliveries[i].NextAttemptAt = d.NextAttemptAt
+				r.deliveries[i].UpdatedAt = d.UpdatedAt
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) Deliveries(_ context.Context, userID uuid.UUID, limit int) ([]entity.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []entity.Delivery{}
+	for _, d := range r.deliveries {
+		if d.UserID == userID {
+			d.Data = bytes.Clone(d.Data)
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	slices.SortStableFunc(deliveries, func(a, b entity.Delivery) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (r *SubscriptionRepo) DueDeliveries(_ context.Context, now time.Time, limit int) ([]entity.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []entity.Delivery{}
+	for _, d := range r.deliveries {
+		if d.Status != entity.DeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+
+		d.Data = bytes.Clone(d.Data)
+		deliveries = append(deliveries, d)
+	}
+
+	slices.SortStableFunc(deliveries, func(a, b entity.Delivery) int {
+		return cmp.Or(a.NextAttemptAt.Compare(b.NextAttemptAt), a.CreatedAt.Compare(b.CreatedAt))
+	})
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
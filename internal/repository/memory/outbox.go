@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// outboxEntry keeps the event encoded like the Postgres payload column does, so the
+// stored event never shares memory with the caller's subscriptions.
+type outboxEntry struct {
+	seq           int64
+	payload       []byte
+	attempts      int
+	lastError     string
+	nextAttemptAt time.Time
+	published     bool
+	publishedAt   time.Time
+}
+
+func (r *SubscriptionRepo) AddOutboxEvent(ctx context.Context, e entity.SubscriptionEvent) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("repository: AddOutboxEvent: %w", err)
+	}
+
+	r.write(ctx, func() {
+		r.outboxSeq++
+		r.outbox = append(r.outbox, outboxEntry{seq: r.outboxSeq, payload: payload, nextAttemptAt: e.OccurredAt})
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) PendingOutboxEvents(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type pending struct {
+		entry outboxEntry
+		event entity.SubscriptionEvent
+	}
+
+	var (
+		entries []pending
+		held    = make(map[uuid.UUID]bool)
+	)
+
+	for _, entry := range r.outbox {
+		if entry.published {
+			continue
+		}
+
+		var event entity.SubscriptionEvent
+		if err := json.Unmarshal(entry.payload, &event); err != nil {
+			return nil, fmt.Errorf("repository: PendingOutboxEvents: event %d: %w", entry.seq, err)
+		}
+
+		if entry.nextAttemptAt.After(now) {
+			held[event.SubscriptionID] = true
+		}
+
+		entries = append(entries, pending{entry: entry, event: event})
+	}
+
+	var events []entity.OutboxEvent
+	for _, p := range entries {
+		if len(events) == limit {
+			break
+		}
+
+		if held[p.event.SubscriptionID] {
+			continue
+		}
+
+		events = append(events, entity.OutboxEvent{
+			Seq:           p.entry.seq,
+			Event:         p.event,
+			Attempts:      p.entry.attempts,
+			LastError:     p.entry.lastError,
+			NextAttemptAt: p.entry.nextAttemptAt,
+		})
+	}
+
+	return events, nil
+}
+
+func (r *SubscriptionRepo) MarkOutboxEventPublished(ctx context.Context, seq int64, at time.Time) error {
+	r.updateOutbox(ctx, seq, func(entry *outboxEntry) {
+		entry.published = true
+		entry.publishedAt = at
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) MarkOutboxEventFailed(ctx context.Context, seq int64, lastError string, retryAt time.Time) error {
+	r.updateOutbox(ctx, seq, func(entry *outboxEntry) {
+		entry.attempts++
+		entry.lastError = lastError
+		entry.nextAttemptAt = retryAt
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) PurgeOutboxEvents(ctx context.Context, before time.Time) (int, error) {
+	var purged int
+
+	r.write(ctx, func() {
+		r.outbox = slices.DeleteFunc(r.outbox, func(entry outboxEntry) bool {
+			if entry.published && entry.publishedAt.Before(before) {
+				purged++
+				return true
+			}
+
+			return false
+		})
+	})
+
+	return purged, nil
+}
+
+func (r *SubscriptionRepo) updateOutbox(ctx context.Context, seq int64, update func(entry *outboxEntry)) {
+	r.write(ctx, func() {
+		for i := range r.outbox {
+			if r.outbox[i].seq == seq {
+				update(&r.outbox[i])
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) SetPrice(ctx context.Context, period entity.PricePeriod) error {
+	period.EffectiveFrom = dateOf(period.EffectiveFrom)
+
+	r.write(ctx, func() {
+		for i, existing := range r.prices {
+			if existing.SubscriptionID == period.SubscriptionID && existing.EffectiveFrom.Equal(period.EffectiveFrom) {
+				r.prices[i] = period
+				return
+			}
+		}
+
+		r.prices = append(r.prices, period)
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) PriceHistory(_ context.Context, ids []uuid.UUID) ([]entity.PricePeriod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var history []entity.PricePeriod
+	for _, period := range r.prices {
+		if slices.Contains(ids, period.SubscriptionID) {
+			history = append(history, period)
+		}
+	}
+
+	slices.SortFunc(history, func(a, b entity.PricePeriod) int {
+		if c := bytes.Compare(a.SubscriptionID.Bytes(), b.SubscriptionID.Bytes()); c != 0 {
+			return c
+		}
+
+		return a.EffectiveFrom.Compare(b.EffectiveFrom)
+	})
+
+	return history, nil
+}
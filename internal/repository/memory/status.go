@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) AddStatusChange(ctx context.Context, change entity.StatusChange) error {
+	change.EffectiveDate = dateOf(change.EffectiveDate)
+
+	r.write(ctx, func() {
+		r.statusHistory = append(r.statusHistory, change)
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) StatusHistory(_ context.Context, ids []uuid.UUID) ([]entity.StatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var history []entity.StatusChange
+	for _, change := range r.statusHistory {
+		if slices.Contains(ids, change.SubscriptionID) {
+			history = append(history, change)
+		}
+	}
+
+	// Stable, so every subscription's changes keep their order.
+	slices.SortStableFunc(history, func(a, b entity.StatusChange) int {
+		return bytes.Compare(a.SubscriptionID.Bytes(), b.SubscriptionID.Bytes())
+	})
+
+	return history, nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// SetTags keeps tags only as long as a subscription carries them,
+// so the user they belong to needs no bookkeeping.
+func (r *SubscriptionRepo) SetTags(ctx context.Context, _, subscriptionID uuid.UUID, tags []string) error {
+	tags = slices.Clone(tags)
+	slices.Sort(tags)
+
+	r.write(ctx, func() {
+		if len(tags) == 0 {
+			delete(r.tags, subscriptionID)
+			return
+		}
+
+		r.tags[subscriptionID] = slices.Compact(tags)
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) SubscriptionTags(_ context.Context, ids []uuid.UUID) (map[uuid.UUID][]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tags := make(map[uuid.UUID][]string)
+	for _, id := range ids {
+		if subscriptionTags, ok := r.tags[id]; ok {
+			tags[id] = slices.Clone(subscriptionTags)
+		}
+	}
+
+	return tags, nil
+}
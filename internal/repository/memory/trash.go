@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) RestoreSubscription(ctx context.Context, id uuid.UUID) error {
+	var err error
+
+	r.write(ctx, func() {
+		s, ok := r.subscriptions[id]
+		if !ok || s.DeletedAt == nil {
+			err = entity.ErrNotFound
+			return
+		}
+
+		s.Version++
+		s.DeletedAt = nil
+		r.subscriptions[id] = s
+	})
+
+	return err
+}
+
+func (r *SubscriptionRepo) DeletedSubscriptions(_ context.Context, userID uuid.UUID, limit int) ([]entity.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var deleted []entity.Subscription
+	for _, id := range r.order {
+		if s := r.subscriptions[id]; s.UserID == userID && s.DeletedAt != nil {
+			deleted = append(deleted, clone(s))
+		}
+	}
+
+	slices.SortFunc(deleted, func(a, b entity.Subscription) int {
+		if c := b.DeletedAt.Compare(*a.DeletedAt); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+
+	if len(deleted) > limit {
+		deleted = deleted[:limit]
+	}
+
+	return deleted, nil
+}
+
+func (r *SubscriptionRepo) PurgeSubscriptions(ctx context.Context, before time.Time) ([]entity.Subscription, error) {
+	var purged []entity.Subscription
+
+	r.write(ctx, func() {
+		r.order = slices.DeleteFunc(r.order, func(id uuid.UUID) bool {
+			s := r.subscriptions[id]
+			if s.DeletedAt == nil || !s.DeletedAt.Before(before) {
+				return false
+			}
+
+			s = clone(s)
+			s.Tags = slices.Clone(r.tags[id])
+			purged = append(purged, s)
+
+			delete(r.subscriptions, id)
+			delete(r.tags, id)
+
+			return true
+		})
+
+		// The histories go with the subscription, like the cascading foreign keys in Postgres.
+		r.statusHistory = slices.DeleteFunc(r.statusHistory, func(change entity.StatusChange) bool {
+			_, ok := r.subscriptions[change.SubscriptionID]
+			return !ok
+		})
+		r.prices = slices.DeleteFunc(r.prices, func(period entity.PricePeriod) bool {
+			_, ok := r.subscriptions[period.SubscriptionID]
+			return !ok
+		})
+	})
+
+	slices.SortFunc(purged, func(a, b entity.Subscription) int {
+		if c := a.DeletedAt.Compare(*b.DeletedAt); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+
+	return purged, nil
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+	"time"
+)
+
+func (r *SubscriptionRepo) EndingTrials(_ context.Context, from, to time.Time) ([]entity.Subscription, error) {
+	from, to = dateOf(from), dateOf(to)
+
+	ending := r.filter(func(s entity.Subscription) bool {
+		if s.TrialEndingFlaggedAt != nil || s.Status != entity.StatusActive || s.TrialEnd == nil {
+			return false
+		}
+
+		return !s.TrialEnd.Before(from) && !s.TrialEnd.After(to)
+	})
+
+	slices.SortFunc(ending, func(a, b entity.Subscription) int {
+		if c := a.TrialEnd.Compare(*b.TrialEnd); c != 0 {
+			return c
+		}
+
+		return bytes.Compare(a.ID.Bytes(), b.ID.Bytes())
+	})
+
+	return ending, nil
+}
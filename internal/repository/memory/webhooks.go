@@ -0,0 +1,231 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) CreateWebhook(ctx context.Context, w entity.Webhook) (uuid.UUID, error) {
+	w.ID = uuid.Must(uuid.NewV4())
+
+	r.write(ctx, func() {
+		r.webhooks = append(r.webhooks, cloneWebhook(w))
+	})
+
+	return w.ID, nil
+}
+
+func (r *SubscriptionRepo) WebhookByID(_ context.Context, id uuid.UUID) (entity.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, w := range r.webhooks {
+		if w.ID == id {
+			return cloneWebhook(w), nil
+		}
+	}
+
+	return entity.Webhook{}, entity.ErrNotFound
+}
+
+func (r *SubscriptionRepo) Webhooks(_ context.Context) ([]entity.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]entity.Webhook, 0, len(r.webhooks))
+	for _, w := range r.webhooks {
+		webhooks = append(webhooks, cloneWebhook(w))
+	}
+
+	slices.SortStableFunc(webhooks, func(a, b entity.Webhook) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return webhooks, nil
+}
+
+func (r *SubscriptionRepo) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	err := entity.ErrNotFound
+
+	r.write(ctx, func() {
+		n := len(r.webhooks)
+
+		r.webhooks = slices.DeleteFunc(r.webhooks, func(w entity.Webhook) bool { return w.ID == id })
+		if len(r.webhooks) == n {
+			return
+		}
+
+		r.webhookDeliveries = slices.DeleteFunc(r.webhookDeliveries, func(d entity.WebhookDelivery) bool {
+			return d.WebhookID == id
+		})
+
+		err = nil
+	})
+
+	return err
+}
+
+func (r *SubscriptionRepo) EnableWebhook(ctx context.Context, id uuid.UUID) error {
+	return r.updateWebhook(ctx, id, func(w *entity.Webhook) {
+		w.Enabled = true
+		w.Failures = 0
+		w.DisabledAt = nil
+	})
+}
+
+func (r *SubscriptionRepo) WebhookSucceeded(ctx context.Context, id uuid.UUID) error {
+	_ = r.updateWebhook(ctx, id, func(w *entity.Webhook) {
+		w.Failures = 0
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) WebhookFailed(ctx context.Context, id uuid.UUID, disableAfter int, at time.Time) (bool, error) {
+	var disabled bool
+
+	err := r.updateWebhook(ctx, id, func(w *entity.Webhook) {
+		w.Failures++
+
+		if w.Enabled && w.Failures >= disableAfter {
+			w.Enabled = false
+			w.DisabledAt = &at
+			disabled = true
+		}
+	})
+
+	return disabled, err
+}
+
+func (r *SubscriptionRepo) CreateWebhookDelivery(ctx context.Context, d entity.WebhookDelivery) (uuid.UUID, error) {
+	var err error
+
+	d.ID = uuid.Must(uuid.NewV4())
+
+	r.write(ctx, func() {
+		if slices.ContainsFunc(r.webhookDeliveries, func(other entity.WebhookDelivery) bool {
+			return other.WebhookID == d.WebhookID && other.EventID == d.EventID
+		}) {
+			err = entity.ErrAlreadyExists
+			return
+		}
+
+		d.Payload = bytes.Clone(d.Payload)
+		r.webhookDeliveries = append(r.webhookDeliveries, d)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return d.ID, nil
+}
+
+func (r *SubscriptionRepo) UpdateWebhookDelivery(ctx context.Context, d entity.WebhookDelivery) error {
+	r.write(ctx, func() {
+		for i := range r.webhookDeliveries {
+			if r.webhookDeliveries[i].ID == d.ID {
+				r.webhookDeliveries[i].Status = d.Status
+				r.webhookDeliveries[i].Attempts = d.Attempts
+				r.webhookDeliveries[i].ResponseStatus = d.ResponseStatus
+				r.webhookDeliveries[i].LastError = d.LastError
+				r.webhookDeliveries[i].NextAttemptAt = d.NextAttemptAt
+				r.webhookDeliveries[i].UpdatedAt = d.UpdatedAt
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DueWebhookDeliveries(_ context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []entity.WebhookDelivery{}
+	for _, d := range r.webhookDeliveries {
+		if d.Status != entity.DeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+
+		if !slices.ContainsFunc(r.webhooks, func(w entity.Webhook) bool { return w.ID == d.WebhookID && w.Enabled }) {
+			continue
+		}
+
+		d.Payload = bytes.Clone(d.Payload)
+		deliveries = append(deliveries, d)
+	}
+
+	slices.SortStableFunc(deliveries, func(a, b entity.WebhookDelivery) int {
+		return cmp.Or(a.NextAttemptAt.Compare(b.NextAttemptAt), a.CreatedAt.Compare(b.CreatedAt))
+	})
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (r *SubscriptionRepo) WebhookDeliveries(_ context.Context, webhookID uuid.UUID, limit int) ([]entity.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []entity.WebhookDelivery{}
+	for _, d := range r.webhookDeliveries {
+		if d.WebhookID == webhookID {
+			d.Payload = bytes.Clone(d.Payload)
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	slices.SortStableFunc(deliveries, func(a, b entity.WebhookDelivery) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+// updateWebhook applies update to the stored webhook, it fails with entity.ErrNotFound when there is none.
+func (r *SubscriptionRepo) updateWebhook(ctx context.Context, id uuid.UUID, update func(w *entity.Webhook)) error {
+	err := entity.ErrNotFound
+
+	r.write(ctx, func() {
+		for i := range r.webhooks {
+			if r.webhooks[i].ID == id {
+				update(&r.webhooks[i])
+				err = nil
+				return
+			}
+		}
+	})
+
+	return err
+}
+
+// cloneWebhook copies the webhook, so the stored one never shares memory with the caller's.
+func cloneWebhook(w entity.Webhook) entity.Webhook {
+	w.Events = slices.Clone(w.Events)
+
+	if w.UserID != nil {
+		userID := *w.UserID
+		w.UserID = &userID
+	}
+
+	if w.DisabledAt != nil {
+		at := *w.DisabledAt
+		w.DisabledAt = &at
+	}
+
+	return w
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const deliveryColumns = "id, user_id, channel, address, event, subject, dedup_key, body, data, status, attempts, last_error, " +
+	"next_attempt_at, created_at, updated_at"
+
+func (r *SubscriptionRepo) NotificationPreferences(ctx context.Context, userID uuid.UUID) ([]entity.NotificationPreference, error) {
+	query := `
+	SELECT channel, address, events
+	FROM notification_preferences
+	WHERE user_id = $1
+	ORDER BY channel, address COLLATE "C"
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: NotificationPreferences: %w", err)
+	}
+	defer rows.Close()
+
+	prefs := []entity.NotificationPreference{}
+	for rows.Next() {
+		var (
+			p      entity.NotificationPreference
+			events []string
+		)
+
+		if err := rows.Scan(&p.Channel, &p.Address, &events); err != nil {
+			return nil, fmt.Errorf("repository: NotificationPreferences: %w", err)
+		}
+
+		for _, event := range events {
+			p.Events = append(p.Events, entity.NotificationEvent(event))
+		}
+
+		prefs = append(prefs, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: NotificationPreferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+func (r *SubscriptionRepo) SetNotificationPreferences(ctx context.Context, userID uuid.UUID, prefs []entity.NotificationPreference) error {
+	err := r.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := r.conn(ctx).Exec(ctx, `DELETE FROM notification_preferences WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+
+		for _, p := range prefs {
+			events := make([]string, 0, len(p.Events))
+			for _, event := range p.Events {
+				events = append(events, string(event))
+			}
+
+			query := `INSERT INTO notification_preferences (user_id, channel, address, events) VALUES ($1, $2, $3, $4)`
+			if _, err := r.conn(ctx).Exec(ctx, query, userID, p.Channel, p.Address, events); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("repository: SetNotificationPreferences: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) CreateDelivery(ctx context.Context, d entity.Delivery) (uuid.UUID, error) {
+	id := uuid.Must(uuid.NewV4())
+
+	query := `
+	INSERT INTO notification_deliveries (` + deliveryColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, id, d.UserID, d.Channel, d.Address, d.Event, d.Subject, d.Key, d.Text, string(d.Data),
+		d.Status, d.Attempts, d.LastError, d.NextAttemptAt, d.CreatedAt, d.UpdatedAt)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository: CreateDelivery: %w", uniqueError(err))
+	}
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) UpdateDelivery(ctx context.Context, d entity.Delivery) error {
+	query := `
+	UPDATE notification_deliveries
+	SET status = $1, attempts = $2, last_error = $3, next_attempt_at = $4, updated_at = $5
+	WHERE id = $6
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, d.Status, d.Attempts, d.LastError, d.NextAttemptAt, d.UpdatedAt, d.ID); err != nil {
+		return fmt.Errorf("repository: UpdateDelivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) Deliveries(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Delivery, error) {
+	query := `
+	SELECT ` + deliveryColumns + `
+	FROM notification_deliveries
+	WHERE user_id = $1
+	ORDER BY created_at DESC, id
+	LIMIT $2
+	`
+
+	deliveries, err := r.deliveries(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: Deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *SubscriptionRepo) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.Delivery, error) {
+	query := `
+	SELECT ` + deliveryColumns + `
+	FROM notification_deliveries
+	WHERE status = $1 AND next_attempt_at <= $2
+	ORDER BY next_attempt_at, created_at, id
+	LIMIT $3
+	`
+
+	deliveries, err := r.deliveries(ctx, query, entity.DeliveryPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: DueDeliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *SubscriptionRepo) deliveries(ctx context.Context, query string, args ...any) ([]entity.Delivery, error) {
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []entity.Delivery{}
+	for rows.Next() {
+		var (
+			d    entity.Delivery
+			data string
+		)
+
+		err := rows.Scan(&d.ID, &d.UserID, &d.Channel, &d.Address, &d.Event, &d.Subject, &d.Key, &d.Text, &data,
+			&d.Status, &d.Attempts, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
+		}
+
+		if data != "" {
+			d.Data = json.RawMessage(data)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+)
+
+func (r *SubscriptionRepo) AddOutboxEvent(ctx context.Context, e entity.SubscriptionEvent) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("repository: AddOutboxEvent: %w", err)
+	}
+
+	query := `
+	INSERT INTO outbox_events (id, event_type, subscription_id, payload, created_at, next_attempt_at)
+	VALUES ($1, $2, $3, $4, $5, $5)
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, e.ID, e.Type, e.SubscriptionID, payload, e.OccurredAt); err != nil {
+		return fmt.Errorf("repository: AddOutboxEvent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) PendingOutboxEvents(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEvent, error) {
+	query := `
+	SELECT seq, payload, attempts, last_error, next_attempt_at
+	FROM outbox_events
+	WHERE published_at IS NULL
+		AND subscription_id NOT IN (
+			SELECT subscription_id
+			FROM outbox_events
+			WHERE published_at IS NULL AND next_attempt_at > $1
+		)
+	ORDER BY seq
+	LIMIT $2
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: PendingOutboxEvents: %w", err)
+	}
+	defer rows.Close()
+
+	var events []entity.OutboxEvent
+	for rows.Next() {
+		var (
+			e       entity.OutboxEvent
+			payload []byte
+		)
+
+		if err := rows.Scan(&e.Seq, &payload, &e.Attempts, &e.LastError, &e.NextAttemptAt); err != nil {
+			return nil, fmt.Errorf("repository: PendingOutboxEvents: %w", err)
+		}
+
+		if err := json.Unmarshal(payload, &e.Event); err != nil {
+			return nil, fmt.Errorf("repository: PendingOutboxEvents: event %d: %w", e.Seq, err)
+		}
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: PendingOutboxEvents: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *SubscriptionRepo) MarkOutboxEventPublished(ctx context.Context, seq int64, at time.Time) error {
+	if _, err := r.conn(ctx).Exec(ctx, `UPDATE outbox_events SET published_at = $1 WHERE seq = $2`, at, seq); err != nil {
+		return fmt.Errorf("repository: MarkOutboxEventPublished: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) MarkOutboxEventFailed(ctx context.Context, seq int64, lastError string, retryAt time.Time) error {
+	query := `
+	UPDATE outbox_events
+	SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+	WHERE seq = $3
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, lastError, retryAt, seq); err != nil {
+		return fmt.Errorf("repository: MarkOutboxEventFailed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) PurgeOutboxEvents(ctx context.Context, before time.Time) (int, error) {
+	tag, err := r.conn(ctx).Exec(ctx, `DELETE FROM outbox_events WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("repository: PurgeOutboxEvents: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) SetPrice(ctx context.Context, period entity.PricePeriod) error {
+	query := `
+	INSERT INTO subscription_prices (subscription_id, price_minor, currency, effective_from)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (subscription_id, effective_from)
+	DO UPDATE SET price_minor = EXCLUDED.price_minor, currency = EXCLUDED.currency
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, period.SubscriptionID, period.Price.Amount, period.Price.Currency, period.EffectiveFrom)
+	if err != nil {
+		return fmt.Errorf("repository: SetPrice: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) PriceHistory(ctx context.Context, ids []uuid.UUID) ([]entity.PricePeriod, error) {
+	sqlQuery, args, err := sq.Select("subscription_id, price_minor, currency, effective_from").
+		PlaceholderFormat(sq.Dollar).
+		From("subscription_prices").
+		Where(sq.Eq{"subscription_id": ids}).
+		OrderBy("subscription_id", "effective_from").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("repository: PriceHistory: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: PriceHistory: %w", err)
+	}
+	defer rows.Close()
+
+	var history []entity.PricePeriod
+	for rows.Next() {
+		var period entity.PricePeriod
+		if err := rows.Scan(&period.SubscriptionID, &period.Price.Amount, &period.Price.Currency, &period.EffectiveFrom); err != nil {
+			return nil, fmt.Errorf("repository: PriceHistory: %w", err)
+		}
+
+		history = append(history, period)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: PriceHistory: %w", err)
+	}
+
+	return history, nil
+}
@@ -5,17 +5,26 @@ import (
 	"errors"
 	"fmt"
 	"online-subscribe-rest-service/internal/entity"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/gofrs/uuid/v5"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const subscriptionColumns = "id, service_id, service_name, price_minor, currency, user_id, start_date, end_date, billing_unit, billing_count, status, " +
+	"trial_start, trial_end, trial_ending_flagged_at, category, deleted_at, version"
+
+// notDeleted keeps the subscriptions in the trash out of a query.
+var notDeleted = sq.Eq{"deleted_at": nil}
+
 type SubscriptionRepo struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewSubscriptionRepo(db *pgx.Conn) *SubscriptionRepo {
+func NewSubscriptionRepo(db *pgxpool.Pool) *SubscriptionRepo {
 	return &SubscriptionRepo{db: db}
 }
 
@@ -24,11 +33,13 @@ func (r *SubscriptionRepo) CreateSubscription(ctx context.Context, s entity.Subs
 	id := uuid.Must(uuid.NewV4())
 
 	query := `
-	INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	INSERT INTO subscriptions (id, service_id, service_name, price_minor, currency, user_id, start_date, end_date, billing_unit, billing_count, status,
+		trial_start, trial_end, trial_ending_flagged_at, category)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
-	_, err := r.db.Exec(ctx, query, id, s.ServiceName, s.Price, s.UserID, s.StartDate, s.EndDate)
+	_, err := r.conn(ctx).Exec(ctx, query, id, s.ServiceID, s.ServiceName, s.Price.Amount, s.Price.Currency, s.UserID, s.StartDate, s.EndDate,
+		s.BillingPeriod.Unit, s.BillingPeriod.Count, s.Status, s.TrialStart, s.TrialEnd, s.TrialEndingFlaggedAt, s.Category)
 
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("repository: create subscription: %w", err)
@@ -42,54 +53,68 @@ func (r *SubscriptionRepo) UpdateSubscription(ctx context.Context, s entity.Subs
 	query := `
 	UPDATE subscriptions
 	SET 
-	service_name = $1,
-	price = $2,
-	user_id = $3,
-	start_date = $4,
-	end_date = $5
-	WHERE id = $6
+	service_id = $1,
+	service_name = $2,
+	price_minor = $3,
+	currency = $4,
+	user_id = $5,
+	start_date = $6,
+	end_date = $7,
+	billing_unit = $8,
+	billing_count = $9,
+	status = $10,
+	trial_start = $11,
+	trial_end = $12,
+	trial_ending_flagged_at = $13,
+	category = $14,
+	version = version + 1
+	WHERE id = $15 AND version = $16 AND deleted_at IS NULL
 	`
 
-	_, err := r.db.Exec(ctx, query, s.ServiceName, s.Price, s.UserID, s.StartDate, s.EndDate, s.ID)
+	tag, err := r.conn(ctx).Exec(ctx, query, s.ServiceID, s.ServiceName, s.Price.Amount, s.Price.Currency, s.UserID, s.StartDate, s.EndDate,
+		s.BillingPeriod.Unit, s.BillingPeriod.Count, s.Status, s.TrialStart, s.TrialEnd, s.TrialEndingFlaggedAt, s.Category, s.ID, s.Version)
 
 	if err != nil {
 		return fmt.Errorf("repository: update subscription: %w", err)
 	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrVersionMismatch
+	}
+
 	return nil
 }
 
-func (r *SubscriptionRepo) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+func (r *SubscriptionRepo) DeleteSubscription(ctx context.Context, id uuid.UUID, version int64, at time.Time) error {
 
 	query := `
-DELETE FROM subscriptions 
-WHERE id = $1
-`
+	UPDATE subscriptions
+	SET deleted_at = $1, version = version + 1
+	WHERE id = $2 AND version = $3 AND deleted_at IS NULL
+	`
 
-	_, err := r.db.Exec(ctx, query, id)
+	tag, err := r.conn(ctx).Exec(ctx, query, at, id, version)
 
 	if err != nil {
 		return fmt.Errorf("repository: delete subscription: %w", err)
 	}
 
+	if tag.RowsAffected() == 0 {
+		return entity.ErrVersionMismatch
+	}
+
 	return nil
 }
 
 func (r *SubscriptionRepo) SubscriptionByID(ctx context.Context, id uuid.UUID) (entity.Subscription, error) {
 
 	query := `
-	SELECT id, service_name, price, user_id, start_date, end_date
+	SELECT ` + subscriptionColumns + `
 	FROM subscriptions 
-	WHERE id = $1
+	WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	var subscription entity.Subscription
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&subscription.ID,
-		&subscription.ServiceName,
-		&subscription.Price,
-		&subscription.UserID,
-		&subscription.StartDate,
-		&subscription.EndDate)
+	subscription, err := scanSubscription(r.conn(ctx).QueryRow(ctx, query, id))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -102,75 +127,264 @@ func (r *SubscriptionRepo) SubscriptionByID(ctx context.Context, id uuid.UUID) (
 	return subscription, nil
 }
 
-func (r *SubscriptionRepo) SubscriptionsList(ctx context.Context, userID uuid.UUID) ([]entity.Subscription, error) {
+// SubscriptionForUpdate reads the subscription and locks its row until the surrounding transaction ends.
+func (r *SubscriptionRepo) SubscriptionForUpdate(ctx context.Context, id uuid.UUID) (entity.Subscription, error) {
 
 	query := `
-	SELECT id, service_name, price, user_id, start_date, end_date
+	SELECT ` + subscriptionColumns + `
 	FROM subscriptions 
-	WHERE user_id = $1
+	WHERE id = $1 AND deleted_at IS NULL
+	FOR UPDATE
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	subscription, err := scanSubscription(r.conn(ctx).QueryRow(ctx, query, id))
 
 	if err != nil {
-		return nil, fmt.Errorf("repository: SubscriptionList: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Subscription{}, entity.ErrNotFound
+		}
+
+		return entity.Subscription{}, fmt.Errorf("repository: SubscriptionForUpdate: %w", err)
 	}
 
-	defer rows.Close()
+	return subscription, nil
+}
 
-	var subscriptions []entity.Subscription
-	for rows.Next() {
-		var s entity.Subscription
-		if err := rows.Scan(
-			&s.ID,
-			&s.ServiceName,
-			&s.Price,
-			&s.UserID,
-			&s.StartDate,
-			&s.EndDate); err != nil {
-
-			return nil, fmt.Errorf("repository: SubscriptionList: rows.Scan() %w", err)
+// sortColumns maps list sorts to the columns they order by. Names compare byte-wise
+// so the order doesn't depend on the database locale and matches the other backends.
+var sortColumns = map[entity.SubscriptionSort]string{
+	entity.SortStartDate:   "start_date",
+	entity.SortPrice:       "price_minor",
+	entity.SortServiceName: `service_name COLLATE "C"`,
+}
+
+func (r *SubscriptionRepo) SubscriptionsList(ctx context.Context, params entity.SubscriptionsListParams) ([]entity.Subscription, error) {
+	where := sq.And{notDeleted, sq.Eq{"user_id": params.UserID}}
+
+	if params.ServiceName != "" {
+		where = append(where, sq.Eq{"service_name": params.ServiceName})
+	}
+
+	if params.ActiveOn != nil {
+		where = append(where,
+			sq.LtOrEq{"start_date": params.ActiveOn},
+			sq.Or{sq.Eq{"end_date": nil}, sq.GtOrEq{"end_date": params.ActiveOn}})
+	}
+
+	where = append(where, priceRange(params.Currency, params.PriceMin, params.PriceMax)...)
+
+	if params.HasEndDate != nil {
+		if *params.HasEndDate {
+			where = append(where, sq.NotEq{"end_date": nil})
+		} else {
+			where = append(where, sq.Eq{"end_date": nil})
 		}
+	}
 
-		subscriptions = append(subscriptions, s)
+	if params.TrialOn != nil {
+		where = append(where,
+			sq.LtOrEq{"coalesce(trial_start, start_date)": params.TrialOn},
+			sq.GtOrEq{"trial_end": params.TrialOn})
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("repository: SubscriptionList: rows.Err() %w", err)
+	if params.Category != "" {
+		where = append(where, sq.Eq{"category": params.Category})
+	}
+
+	for _, tag := range params.Tags {
+		where = append(where, sq.Expr(
+			"id IN (SELECT st.subscription_id FROM subscription_tags st JOIN tags t ON t.id = st.tag_id WHERE t.name = ?)", tag))
+	}
+
+	subscriptions, err := r.page(ctx, where, params.Sort, params.Desc, params.Cursor, params.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: SubscriptionList: %w", err)
 	}
 
 	return subscriptions, nil
+}
+
+func (r *SubscriptionRepo) SearchSubscriptions(ctx context.Context, filter entity.SubscriptionFilter) ([]entity.Subscription, int, error) {
+	where := sq.And{notDeleted}
+
+	if filter.ServiceName != "" {
+		where = append(where, sq.ILike{"service_name": "%" + escapeLike(filter.ServiceName) + "%"})
+	}
+
+	if len(filter.UserIDs) > 0 {
+		where = append(where, sq.Eq{"user_id": filter.UserIDs})
+	}
+
+	if filter.ActiveTo != nil {
+		where = append(where, sq.LtOrEq{"start_date": filter.ActiveTo})
+	}
+
+	if filter.ActiveFrom != nil {
+		where = append(where, sq.Or{sq.Eq{"end_date": nil}, sq.GtOrEq{"end_date": filter.ActiveFrom}})
+	}
+
+	where = append(where, priceRange(filter.Currency, filter.PriceMin, filter.PriceMax)...)
+
+	countQuery, args, err := sq.Select("count(*)").PlaceholderFormat(sq.Dollar).
+		From("subscriptions").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository: SearchSubscriptions: %w", err)
+	}
+
+	var total int
+	if err := r.conn(ctx).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("repository: SearchSubscriptions: %w", err)
+	}
+
+	subscriptions, err := r.page(ctx, where, filter.Sort, filter.Desc, filter.Cursor, filter.Limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository: SearchSubscriptions: %w", err)
+	}
+
+	return subscriptions, total, nil
+}
+
+// page selects up to limit subscriptions matching where in the sort order, starting right after cursor.
+func (r *SubscriptionRepo) page(ctx context.Context, where sq.And, sortBy entity.SubscriptionSort, desc bool, cursor *entity.ListCursor, limit int) ([]entity.Subscription, error) {
+	column := sortColumns[sortBy]
+
+	direction, after := "ASC", ">"
+	if desc {
+		direction, after = "DESC", "<"
+	}
+
+	if cursor != nil {
+		var value any = cursor.Value
+		if sortBy == entity.SortPrice {
+			value, _ = cursor.PriceValue()
+		}
+
+		where = append(where, sq.Or{
+			sq.Expr(column+" "+after+" ?", value),
+			sq.And{sq.Eq{column: value}, sq.Expr("id "+after+" ?", cursor.ID)},
+		})
+	}
+
+	sqlQuery, args, err := sq.Select(subscriptionColumns).PlaceholderFormat(sq.Dollar).
+		From("subscriptions").
+		Where(where).
+		OrderBy(column+" "+direction, "id "+direction).
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSubscriptions(rows)
+}
+
+func priceRange(currency string, priceMin, priceMax *int64) []sq.Sqlizer {
+	var where []sq.Sqlizer
+
+	if currency != "" {
+		where = append(where, sq.Eq{"currency": currency})
+	}
+
+	if priceMin != nil {
+		where = append(where, sq.GtOrEq{"price_minor": *priceMin})
+	}
+
+	if priceMax != nil {
+		where = append(where, sq.LtOrEq{"price_minor": *priceMax})
+	}
 
+	return where
 }
 
-func (r *SubscriptionRepo) SubscriptionsSum(ctx context.Context, params entity.SubscriptionsSumParams) (entity.UserSubscriptionsSum, error) {
-	query := sq.Select("user_id, sum(price)").PlaceholderFormat(sq.Dollar).
+// escapeLike escapes the LIKE wildcards in s, backslash is the default escape character.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (r *SubscriptionRepo) SubscriptionsInPeriod(ctx context.Context, params entity.SubscriptionsSumParams) ([]entity.Subscription, error) {
+	query := sq.Select(subscriptionColumns).PlaceholderFormat(sq.Dollar).
 		From("subscriptions").
+		Where(notDeleted).
 		Where(sq.Eq{"user_id": params.UserID}).
-		Where(sq.Eq{"service_name": params.ServiceName}).
-		Where(sq.GtOrEq{"start_date": params.StartDate}).
-		GroupBy("user_id")
+		Where(sq.Or{sq.Eq{"end_date": nil}, sq.GtOrEq{"end_date": params.StartDate}}).
+		OrderBy("start_date", "id")
+
+	if !params.ServiceID.IsNil() {
+		query = query.Where(sq.Eq{"service_id": params.ServiceID})
+	}
 
 	if params.EndDate != nil {
-		query = query.Where(sq.LtOrEq{"end_date": params.EndDate})
-	} 
+		query = query.Where(sq.LtOrEq{"start_date": params.EndDate})
+	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
-		return entity.UserSubscriptionsSum{}, fmt.Errorf("repository: SubscriptionsSum: %w", err)
+		return nil, fmt.Errorf("repository: SubscriptionsInPeriod: %w", err)
 	}
 
-	var sum entity.UserSubscriptionsSum
+	rows, err := r.conn(ctx).Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: SubscriptionsInPeriod: %w", err)
+	}
 
-	err = r.db.QueryRow(ctx, sqlQuery, args...).Scan(&sum.UserID, &sum.TotalPrice)
+	subscriptions, err := scanSubscriptions(rows)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return entity.UserSubscriptionsSum{}, entity.ErrNotFound
+		return nil, fmt.Errorf("repository: SubscriptionsInPeriod: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// scanSubscription reads one row selected with subscriptionColumns.
+func scanSubscription(row pgx.Row) (entity.Subscription, error) {
+	var s entity.Subscription
+
+	err := row.Scan(
+		&s.ID,
+		&s.ServiceID,
+		&s.ServiceName,
+		&s.Price.Amount,
+		&s.Price.Currency,
+		&s.UserID,
+		&s.StartDate,
+		&s.EndDate,
+		&s.BillingPeriod.Unit,
+		&s.BillingPeriod.Count,
+		&s.Status,
+		&s.TrialStart,
+		&s.TrialEnd,
+		&s.TrialEndingFlaggedAt,
+		&s.Category,
+		&s.DeletedAt,
+		&s.Version)
+
+	return s, err
+}
+
+// scanSubscriptions drains and closes rows selected with subscriptionColumns.
+func scanSubscriptions(rows pgx.Rows) ([]entity.Subscription, error) {
+	defer rows.Close()
+
+	var subscriptions []entity.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
 		}
 
-		return entity.UserSubscriptionsSum{}, fmt.Errorf("repository: SubscriptionsSum: %w", err)
+		subscriptions = append(subscriptions, s)
 	}
 
-	return sum, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err() %w", err)
+	}
 
+	return subscriptions, nil
 }
@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"net/url"
+	"online-subscribe-rest-service/internal/repository"
+	"online-subscribe-rest-service/internal/repository/repotest"
+	"online-subscribe-rest-service/pkg/config"
+	"online-subscribe-rest-service/pkg/postgres"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// TestConformance runs the suite against the database at TEST_POSTGRES_DSN and is skipped without it.
+// Every test migrates a schema of its own, which is dropped afterwards.
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	ctx := context.Background()
+
+	repotest.Run(t, func(t *testing.T) repotest.Repo {
+		schema := "repotest_" + strings.ReplaceAll(uuid.Must(uuid.NewV4()).String(), "-", "")
+
+		conn, err := pgx.Connect(ctx, dsn)
+		if err != nil {
+			t.Fatalf("failed to connect to postgres: %v", err)
+		}
+
+		defer conn.Close(ctx)
+
+		if _, err := conn.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+			t.Fatalf("failed to create schema: %v", err)
+		}
+
+		t.Cleanup(func() {
+			conn, err := pgx.Connect(ctx, dsn)
+			if err != nil {
+				t.Errorf("failed to connect to postgres: %v", err)
+				return
+			}
+
+			defer conn.Close(ctx)
+
+			if _, err := conn.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+				t.Errorf("failed to drop schema: %v", err)
+			}
+		})
+
+		schemaDSN, err := withSearchPath(dsn, schema)
+		if err != nil {
+			t.Fatalf("failed to parse TEST_POSTGRES_DSN: %v", err)
+		}
+
+		if err := postgres.UpMigrations(schemaDSN); err != nil {
+			t.Fatalf("failed to up migrations: %v", err)
+		}
+
+		pool, err := postgres.ConnectToPostgres(ctx, config.Postgres{DSN: schemaDSN})
+		if err != nil {
+			t.Fatalf("failed to connect to postgres: %v", err)
+		}
+
+		t.Cleanup(pool.Close)
+
+		return repository.NewSubscriptionRepo(pool)
+	})
+}
+
+// withSearchPath points the connections of the DSN, a URL or key=value pairs, at the schema.
+func withSearchPath(dsn, schema string) (string, error) {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema, nil
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
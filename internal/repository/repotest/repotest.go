@@ -0,0 +1,1952 @@
+// Package repotest is the conformance suite every storage backend has to pass,
+// so the storage backends stay interchangeable. Call Run from the backend's tests.
+package repotest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/internal/notifier"
+	"online-subscribe-rest-service/internal/outbox"
+	"online-subscribe-rest-service/internal/service"
+	"online-subscribe-rest-service/internal/webhooks"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Repo is everything a storage backend implements: what the service uses and what the notifier,
+// the outbox relay and the webhook sender use.
+type Repo interface {
+	service.Repo
+	notifier.Store
+	outbox.Store
+	webhooks.Store
+}
+
+// Run checks the repository returned by newRepo, newRepo must return an empty repository on every call.
+func Run(t *testing.T, newRepo func(t *testing.T) Repo) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo Repo)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"GetMissing", testGetMissing},
+		{"Update", testUpdate},
+		{"Delete", testDelete},
+		{"Trash", testTrash},
+		{"List", testList},
+		{"ListFilters", testListFilters},
+		{"ListPagination", testListPagination},
+		{"Search", testSearch},
+		{"InPeriod", testInPeriod},
+		{"TxCommit", testTxCommit},
+		{"TxRollback", testTxRollback},
+		{"StatusHistory", testStatusHistory},
+		{"EndingTrials", testEndingTrials},
+		{"EndedSubscriptions", testEndedSubscriptions},
+		{"PriceHistory", testPriceHistory},
+		{"Catalog", testCatalog},
+		{"CatalogUpdate", testCatalogUpdate},
+		{"Tags", testTags},
+		{"CalendarTokens", testCalendarTokens},
+		{"Budgets", testBudgets},
+		{"NotificationPreferences", testNotificationPreferences},
+		{"Deliveries", testDeliveries},
+		{"Outbox", testOutbox},
+		{"Webhooks", testWebhooks},
+		{"WebhookDeliveries", testWebhookDeliveries},
+		{"AuditLog", testAuditLog},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepo(t))
+		})
+	}
+}
+
+func testCreateAndGet(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	want := subscription(uuid.Must(uuid.NewV4()), "Yandex Plus", "2025-07-01", "2025-12-31")
+	want.BillingPeriod = entity.Quarterly
+	want.TrialStart = day("2025-07-01")
+	want.TrialEnd = day("2025-07-14")
+	want.ServiceID = serviceID(t, repo, "Yandex Plus")
+
+	id := create(t, repo, want)
+	want.ID = id
+
+	got, err := repo.SubscriptionByID(ctx, id)
+	if err != nil {
+		t.Fatalf("SubscriptionByID() error = %v", err)
+	}
+
+	assertEqual(t, got, want)
+
+	got, err = repo.SubscriptionForUpdate(ctx, id)
+	if err != nil {
+		t.Fatalf("SubscriptionForUpdate() error = %v", err)
+	}
+
+	assertEqual(t, got, want)
+}
+
+func testGetMissing(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	_, err := repo.SubscriptionByID(ctx, uuid.Must(uuid.NewV4()))
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("SubscriptionByID() error = %v, want %v", err, entity.ErrNotFound)
+	}
+}
+
+func testUpdate(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	sub := subscription(uuid.Must(uuid.NewV4()), "Netflix", "2025-01-15", "")
+	sub.ServiceID = serviceID(t, repo, "Netflix")
+	sub.ID = create(t, repo, sub)
+
+	sub.ServiceID = serviceID(t, repo, "Netflix Premium")
+	sub.ServiceName = "Netflix Premium"
+	sub.Price = entity.NewMoney(99900, "USD")
+	sub.BillingPeriod = entity.Yearly
+	sub.EndDate = day("2026-01-14")
+
+	if err := repo.UpdateSubscription(ctx, sub); err != nil {
+		t.Fatalf("UpdateSubscription() error = %v", err)
+	}
+
+	stale := sub
+	stale.ServiceName = "Stale"
+	if err := repo.UpdateSubscription(ctx, stale); !errors.Is(err, entity.ErrVersionMismatch) {
+		t.Fatalf("UpdateSubscription() at a stale version error = %v, want %v", err, entity.ErrVersionMismatch)
+	}
+
+	sub.Version++
+
+	got, err := repo.SubscriptionByID(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("SubscriptionByID() error = %v", err)
+	}
+
+	assertEqual(t, got, sub)
+
+	missing := sub
+	missing.ID = uuid.Must(uuid.NewV4())
+	if err := repo.UpdateSubscription(ctx, missing); !errors.Is(err, entity.ErrVersionMismatch) {
+		t.Fatalf("UpdateSubscription() of a missing subscription error = %v, want %v", err, entity.ErrVersionMismatch)
+	}
+}
+
+func testDelete(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	id := create(t, repo, subscription(uuid.Must(uuid.NewV4()), "Spotify", "2025-03-01", ""))
+
+	if err := repo.DeleteSubscription(ctx, id, 2, time.Now()); !errors.Is(err, entity.ErrVersionMismatch) {
+		t.Fatalf("DeleteSubscription() at another version error = %v, want %v", err, entity.ErrVersionMismatch)
+	}
+
+	if err := repo.DeleteSubscription(ctx, id, 1, time.Now()); err != nil {
+		t.Fatalf("DeleteSubscription() error = %v", err)
+	}
+
+	if _, err := repo.SubscriptionByID(ctx, id); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("SubscriptionByID() after delete error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	// The delete bumped the version, yet a deleted subscription is at none.
+	for _, version := range []int64{1, 2} {
+		if err := repo.DeleteSubscription(ctx, id, version, time.Now()); !errors.Is(err, entity.ErrVersionMismatch) {
+			t.Fatalf("DeleteSubscription() of a deleted subscription error = %v, want %v", err, entity.ErrVersionMismatch)
+		}
+	}
+}
+
+func testTrash(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+	firstAt := time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)
+	lastAt := firstAt.Add(24 * time.Hour)
+
+	first := create(t, repo, subscription(user, "Okko", "2025-01-01", "2025-03-31"))
+	last := create(t, repo, subscription(user, "Okko", "2025-02-01", ""))
+	kept := create(t, repo, subscription(user, "Okko", "2025-03-01", ""))
+	other := create(t, repo, subscription(uuid.Must(uuid.NewV4()), "Okko", "2025-01-01", ""))
+
+	if err := repo.SetTags(ctx, user, last, []string{"work"}); err != nil {
+		t.Fatalf("SetTags() error = %v", err)
+	}
+
+	for id, at := range map[uuid.UUID]time.Time{first: firstAt, last: lastAt, other: firstAt} {
+		if err := repo.DeleteSubscription(ctx, id, 1, at); err != nil {
+			t.Fatalf("DeleteSubscription() error = %v", err)
+		}
+	}
+
+	subs, err := repo.SubscriptionsList(ctx, listParams(user, entity.SortStartDate, false))
+	if err != nil {
+		t.Fatalf("SubscriptionsList() error = %v", err)
+	}
+
+	assertIDs(t, subs, kept)
+
+	subs, total, err := repo.SearchSubscriptions(ctx, entity.SubscriptionFilter{ServiceName: "okko", Sort: entity.SortStartDate, Limit: entity.MaxPageLimit})
+	if err != nil {
+		t.Fatalf("SearchSubscriptions() error = %v", err)
+	}
+
+	if assertIDs(t, subs, kept); total != 1 {
+		t.Fatalf("SearchSubscriptions() total = %d, want 1", total)
+	}
+
+	subs, err = repo.SubscriptionsInPeriod(ctx, entity.SubscriptionsSumParams{UserID: user, StartDate: *day("2025-01-01")})
+	if err != nil {
+		t.Fatalf("SubscriptionsInPeriod() error = %v", err)
+	}
+
+	assertIDs(t, subs, kept)
+
+	ended, err := repo.EndedSubscriptions(ctx, *day("2025-07-01"))
+	if err != nil {
+		t.Fatalf("EndedSubscriptions() error = %v", err)
+	}
+
+	if len(ended) != 0 {
+		t.Fatalf("EndedSubscriptions() = %d subscriptions, want none in the trash", len(ended))
+	}
+
+	changed := subscription(user, "Okko", "2025-02-01", "2025-12-31")
+	changed.ID, changed.ServiceID = last, serviceID(t, repo, "Okko")
+	if err := repo.UpdateSubscription(ctx, changed); !errors.Is(err, entity.ErrVersionMismatch) {
+		t.Fatalf("UpdateSubscription() of a deleted subscription error = %v, want %v", err, entity.ErrVersionMismatch)
+	}
+
+	deleted, err := repo.DeletedSubscriptions(ctx, user, 10)
+	if err != nil {
+		t.Fatalf("DeletedSubscriptions() error = %v", err)
+	}
+
+	assertIDs(t, deleted, last, first)
+
+	if at := deleted[0].DeletedAt; !equalTimes(at, &lastAt) {
+		t.Fatalf("DeletedAt = %v, want %v", at, lastAt)
+	}
+
+	if deleted[0].EndDate != nil {
+		t.Fatalf("UpdateSubscription() changed a deleted subscription: end date = %v", deleted[0].EndDate)
+	}
+
+	if deleted, err = repo.DeletedSubscriptions(ctx, user, 1); err != nil {
+		t.Fatalf("DeletedSubscriptions() error = %v", err)
+	}
+
+	assertIDs(t, deleted, last)
+
+	if err := repo.RestoreSubscription(ctx, first); err != nil {
+		t.Fatalf("RestoreSubscription() error = %v", err)
+	}
+
+	restored, err := repo.SubscriptionByID(ctx, first)
+	if err != nil {
+		t.Fatalf("SubscriptionByID() of a restored subscription error = %v", err)
+	}
+
+	if restored.DeletedAt != nil || restored.Version != 3 {
+		t.Fatalf("restored subscription: DeletedAt = %v, Version = %d, want nil and 3", restored.DeletedAt, restored.Version)
+	}
+
+	for _, id := range []uuid.UUID{first, kept, uuid.Must(uuid.NewV4())} {
+		if err := repo.RestoreSubscription(ctx, id); !errors.Is(err, entity.ErrNotFound) {
+			t.Fatalf("RestoreSubscription() of a subscription out of the trash error = %v, want %v", err, entity.ErrNotFound)
+		}
+	}
+
+	purged, err := repo.PurgeSubscriptions(ctx, lastAt)
+	if err != nil {
+		t.Fatalf("PurgeSubscriptions() error = %v", err)
+	}
+
+	assertIDs(t, purged, other)
+
+	if purged, err = repo.PurgeSubscriptions(ctx, lastAt.Add(time.Second)); err != nil {
+		t.Fatalf("PurgeSubscriptions() error = %v", err)
+	}
+
+	// The purged subscription comes back as it lay in the trash, its tags included.
+	if assertIDs(t, purged, last); !equalTimes(purged[0].DeletedAt, &lastAt) || !slices.Equal(purged[0].Tags, []string{"work"}) {
+		t.Fatalf("purged subscription: DeletedAt = %v, Tags = %v, want %v and [work]", purged[0].DeletedAt, purged[0].Tags, lastAt)
+	}
+
+	if deleted, err = repo.DeletedSubscriptions(ctx, user, 10); err != nil {
+		t.Fatalf("DeletedSubscriptions() error = %v", err)
+	}
+
+	if len(deleted) != 0 {
+		t.Fatalf("DeletedSubscriptions() after purge = %d subscriptions, want 0", len(deleted))
+	}
+
+	if err := repo.RestoreSubscription(ctx, last); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("RestoreSubscription() of a purged subscription error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	if _, err := repo.SubscriptionByID(ctx, kept); err != nil {
+		t.Fatalf("SubscriptionByID() after purge error = %v", err)
+	}
+}
+
+func testList(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+
+	late := create(t, repo, subscription(user, "B service", "2025-05-01", ""))
+	create(t, repo, subscription(uuid.Must(uuid.NewV4()), "Other user", "2025-01-01", ""))
+	early := create(t, repo, subscription(user, "A service", "2025-01-01", "2025-03-31"))
+
+	subs, err := repo.SubscriptionsList(ctx, listParams(user, entity.SortStartDate, false))
+	if err != nil {
+		t.Fatalf("SubscriptionsList() error = %v", err)
+	}
+
+	assertIDs(t, subs, early, late)
+
+	subs, err = repo.SubscriptionsList(ctx, listParams(uuid.Must(uuid.NewV4()), entity.SortStartDate, false))
+	if err != nil {
+		t.Fatalf("SubscriptionsList() for unknown user error = %v", err)
+	}
+
+	if len(subs) != 0 {
+		t.Fatalf("SubscriptionsList() for unknown user = %v, want none", subs)
+	}
+}
+
+func testListFilters(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+
+	cheap := subscription(user, "Okko", "2025-01-01", "2025-06-30")
+	cheap.Price = entity.NewMoney(19900, "RUB")
+	cheapID := create(t, repo, cheap)
+
+	expensive := subscription(user, "Netflix", "2025-03-01", "")
+	expensive.Price = entity.NewMoney(99900, "RUB")
+	expensive.TrialStart = day("2025-03-01")
+	expensive.TrialEnd = day("2025-03-14")
+	expensive.Category = entity.CategoryStreaming
+	expensiveID := create(t, repo, expensive)
+
+	futureID := create(t, repo, subscription(user, "Okko", "2026-01-01", ""))
+
+	dollars := subscription(user, "Spotify", "2025-02-01", "")
+	dollars.Price = entity.NewMoney(99900, "USD")
+	dollarsID := create(t, repo, dollars)
+
+	tags := map[uuid.UUID][]string{
+		cheapID:     {"family", "video"},
+		expensiveID: {"video"},
+	}
+	for id, subscriptionTags := range tags {
+		if err := repo.SetTags(ctx, user, id, subscriptionTags); err != nil {
+			t.Fatalf("SetTags() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *entity.SubscriptionsListParams)
+		want   []uuid.UUID
+	}{
+		{"ServiceName", func(p *entity.SubscriptionsListParams) { p.ServiceName = "Okko" }, []uuid.UUID{cheapID, futureID}},
+		{"ActiveOn", func(p *entity.SubscriptionsListParams) { p.ActiveOn = day("2025-04-01") }, []uuid.UUID{cheapID, dollarsID, expensiveID}},
+		{"ActiveOnEndDate", func(p *entity.SubscriptionsListParams) { p.ActiveOn = day("2025-06-30") }, []uuid.UUID{cheapID, dollarsID, expensiveID}},
+		{"Currency", func(p *entity.SubscriptionsListParams) { p.Currency = "USD" }, []uuid.UUID{dollarsID}},
+		{"PriceMin", func(p *entity.SubscriptionsListParams) { p.Currency, p.PriceMin = "RUB", ptr(int64(39900)) }, []uuid.UUID{expensiveID, futureID}},
+		{"PriceMax", func(p *entity.SubscriptionsListParams) { p.Currency, p.PriceMax = "RUB", ptr(int64(39900)) }, []uuid.UUID{cheapID, futureID}},
+		{"PriceInOtherCurrency", func(p *entity.SubscriptionsListParams) { p.Currency, p.PriceMin = "USD", ptr(int64(39900)) }, []uuid.UUID{dollarsID}},
+		{"HasEndDate", func(p *entity.SubscriptionsListParams) { p.HasEndDate = ptr(true) }, []uuid.UUID{cheapID}},
+		{"NoEndDate", func(p *entity.SubscriptionsListParams) { p.HasEndDate = ptr(false) }, []uuid.UUID{dollarsID, expensiveID, futureID}},
+		{"TrialOn", func(p *entity.SubscriptionsListParams) { p.TrialOn = day("2025-03-14") }, []uuid.UUID{expensiveID}},
+		{"Category", func(p *entity.SubscriptionsListParams) { p.Category = entity.CategoryStreaming }, []uuid.UUID{expensiveID}},
+		{"Tag", func(p *entity.SubscriptionsListParams) { p.Tags = []string{"video"} }, []uuid.UUID{cheapID, expensiveID}},
+		{"AllTags", func(p *entity.SubscriptionsListParams) { p.Tags = []string{"video", "family"} }, []uuid.UUID{cheapID}},
+	}
+
+	for _, tt := range tests {
+		params := listParams(user, entity.SortStartDate, false)
+		tt.modify(&params)
+
+		subs, err := repo.SubscriptionsList(ctx, params)
+		if err != nil {
+			t.Fatalf("%s: SubscriptionsList() error = %v", tt.name, err)
+		}
+
+		assertIDs(t, subs, tt.want...)
+	}
+}
+
+func testListPagination(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+
+	var subs []entity.Subscription
+	for i, name := range []string{"Netflix", "Okko", "Apple", "Okko", "Kion"} {
+		sub := subscription(user, name, "2025-01-01", "")
+		sub.Price = entity.NewMoney(int64(10000*(i%3+1)), "RUB")
+		sub.ID = create(t, repo, sub)
+		subs = append(subs, sub)
+	}
+
+	for _, sortBy := range []entity.SubscriptionSort{entity.SortStartDate, entity.SortPrice, entity.SortServiceName} {
+		for _, desc := range []bool{false, true} {
+			want := append([]entity.Subscription(nil), subs...)
+			sort.Slice(want, func(i, j int) bool {
+				if vi, vj := sortBy.Value(want[i]), sortBy.Value(want[j]); vi != vj {
+					if sortBy == entity.SortPrice {
+						return (want[i].Price.Amount < want[j].Price.Amount) != desc
+					}
+
+					return (vi < vj) != desc
+				}
+
+				return (bytes.Compare(want[i].ID.Bytes(), want[j].ID.Bytes()) < 0) != desc
+			})
+
+			params := listParams(user, sortBy, desc)
+			params.Limit = 2
+
+			var got []entity.Subscription
+			for page := 0; page < len(subs); page++ {
+				items, err := repo.SubscriptionsList(ctx, params)
+				if err != nil {
+					t.Fatalf("SubscriptionsList(%s, desc=%v) error = %v", sortBy, desc, err)
+				}
+
+				got = append(got, items...)
+				if len(items) < params.Limit {
+					break
+				}
+
+				cursor := entity.NewListCursor(sortBy, desc, items[len(items)-1])
+				params.Cursor = &cursor
+			}
+
+			wantIDs := make([]uuid.UUID, 0, len(want))
+			for _, s := range want {
+				wantIDs = append(wantIDs, s.ID)
+			}
+
+			assertIDs(t, got, wantIDs...)
+		}
+	}
+}
+
+func testSearch(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	alice, bob, carol := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+
+	plus := create(t, repo, subscription(alice, "Yandex Plus", "2025-01-01", "2025-03-31"))
+	plusRu := create(t, repo, subscription(bob, "Яндекс Плюс", "2025-02-01", ""))
+	music := create(t, repo, subscription(bob, "YANDEX Music", "2025-05-01", ""))
+	netflix := create(t, repo, subscription(carol, "Netflix", "2025-01-01", ""))
+	percent := create(t, repo, subscription(carol, "100% Cloud", "2025-01-01", ""))
+
+	dollars := subscription(carol, "Spotify", "2025-02-01", "")
+	dollars.Price = entity.NewMoney(39900, "USD")
+	spotify := create(t, repo, dollars)
+
+	tests := []struct {
+		name      string
+		filter    entity.SubscriptionFilter
+		want      []uuid.UUID
+		wantTotal int
+	}{
+		{"ServiceName", entity.SubscriptionFilter{ServiceName: "yandex"}, []uuid.UUID{plus, music}, 2},
+		{"ServiceNameUnicode", entity.SubscriptionFilter{ServiceName: "яндекс"}, []uuid.UUID{plusRu}, 1},
+		{"ServiceNameWildcard", entity.SubscriptionFilter{ServiceName: "%"}, []uuid.UUID{percent}, 1},
+		{"UserIDs", entity.SubscriptionFilter{UserIDs: []uuid.UUID{alice, bob}}, []uuid.UUID{plus, plusRu, music}, 3},
+		{"ActiveBetween", entity.SubscriptionFilter{UserIDs: []uuid.UUID{alice, bob}, ActiveFrom: day("2025-04-01"), ActiveTo: day("2025-04-30")}, []uuid.UUID{plusRu}, 1},
+		{"Limit", entity.SubscriptionFilter{UserIDs: []uuid.UUID{alice, bob}, Limit: 1}, []uuid.UUID{plus}, 3},
+		{"Currency", entity.SubscriptionFilter{Currency: "USD"}, []uuid.UUID{spotify}, 1},
+		{"PriceRange", entity.SubscriptionFilter{ServiceName: "netflix", Currency: "RUB", PriceMin: ptr(int64(39900)), PriceMax: ptr(int64(39900))}, []uuid.UUID{netflix}, 1},
+	}
+
+	for _, tt := range tests {
+		filter := tt.filter
+		filter.Sort = entity.SortStartDate
+		if filter.Limit == 0 {
+			filter.Limit = entity.MaxPageLimit
+		}
+
+		subs, total, err := repo.SearchSubscriptions(ctx, filter)
+		if err != nil {
+			t.Fatalf("%s: SearchSubscriptions() error = %v", tt.name, err)
+		}
+
+		assertIDs(t, subs, tt.want...)
+
+		if total != tt.wantTotal {
+			t.Fatalf("%s: SearchSubscriptions() total = %d, want %d", tt.name, total, tt.wantTotal)
+		}
+	}
+}
+
+func testInPeriod(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+
+	late := create(t, repo, subscription(user, "Kinopoisk", "2025-06-01", ""))
+	early := create(t, repo, subscription(user, "Kinopoisk", "2025-01-01", "2025-03-31"))
+	create(t, repo, subscription(user, "Kinopoisk", "2024-01-01", "2024-12-31"))
+	create(t, repo, subscription(user, "Kinopoisk", "2026-01-01", ""))
+	create(t, repo, subscription(user, "Okko", "2025-01-01", ""))
+	create(t, repo, subscription(uuid.Must(uuid.NewV4()), "Kinopoisk", "2025-01-01", ""))
+
+	subs, err := repo.SubscriptionsInPeriod(ctx, entity.SubscriptionsSumParams{
+		UserID:    user,
+		ServiceID: serviceID(t, repo, "Kinopoisk"),
+		StartDate: *day("2025-01-01"),
+		EndDate:   day("2025-12-31"),
+	})
+	if err != nil {
+		t.Fatalf("SubscriptionsInPeriod() error = %v", err)
+	}
+
+	assertIDs(t, subs, early, late)
+
+	subs, err = repo.SubscriptionsInPeriod(ctx, entity.SubscriptionsSumParams{
+		UserID:    user,
+		ServiceID: serviceID(t, repo, "Kinopoisk"),
+		StartDate: *day("2025-03-31"),
+	})
+	if err != nil {
+		t.Fatalf("SubscriptionsInPeriod() without end date error = %v", err)
+	}
+
+	if len(subs) != 3 {
+		t.Fatalf("SubscriptionsInPeriod() without end date returned %d subscriptions, want 3", len(subs))
+	}
+
+	subs, err = repo.SubscriptionsInPeriod(ctx, entity.SubscriptionsSumParams{
+		UserID:    user,
+		StartDate: *day("2025-01-01"),
+		EndDate:   day("2025-12-31"),
+	})
+	if err != nil {
+		t.Fatalf("SubscriptionsInPeriod() of all services error = %v", err)
+	}
+
+	if len(subs) != 3 {
+		t.Fatalf("SubscriptionsInPeriod() of all services returned %d subscriptions, want 3", len(subs))
+	}
+}
+
+func testTxCommit(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	sub := subscription(uuid.Must(uuid.NewV4()), "Okko", "2025-01-01", "")
+	sub.ServiceID = serviceID(t, repo, "Okko")
+
+	var id uuid.UUID
+	err := repo.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = repo.CreateSubscription(ctx, sub)
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.SubscriptionForUpdate(ctx, id)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTx() error = %v", err)
+	}
+
+	if _, err := repo.SubscriptionByID(ctx, id); err != nil {
+		t.Fatalf("SubscriptionByID() after commit error = %v", err)
+	}
+}
+
+func testTxRollback(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	kept := subscription(uuid.Must(uuid.NewV4()), "Okko", "2025-01-01", "")
+	kept.ServiceID = serviceID(t, repo, "Okko")
+	kept.ID = create(t, repo, kept)
+
+	errRollback := errors.New("rollback")
+
+	var created uuid.UUID
+	err := repo.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = repo.CreateSubscription(ctx, kept); err != nil {
+			return err
+		}
+
+		changed := kept
+		changed.ServiceName = "Changed"
+		if err := repo.UpdateSubscription(ctx, changed); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithTx() error = %v, want %v", err, errRollback)
+	}
+
+	if _, err := repo.SubscriptionByID(ctx, created); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("SubscriptionByID() of a rolled back subscription error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	got, err := repo.SubscriptionByID(ctx, kept.ID)
+	if err != nil {
+		t.Fatalf("SubscriptionByID() error = %v", err)
+	}
+
+	assertEqual(t, got, kept)
+}
+
+func testStatusHistory(t *testing.T, repo Repo) {
+	ctx := context.Background()
+	userID := uuid.Must(uuid.NewV4())
+
+	first := create(t, repo, subscription(userID, "Netflix", "2025-01-15", ""))
+	second := create(t, repo, subscription(userID, "Spotify", "2025-02-01", ""))
+
+	changedAt := time.Date(2025, 5, 1, 10, 30, 0, 0, time.UTC)
+	changes := []entity.StatusChange{
+		{SubscriptionID: first, From: entity.StatusActive, To: entity.StatusPaused, EffectiveDate: *day("2025-03-01"), ChangedAt: changedAt},
+		{SubscriptionID: second, From: entity.StatusActive, To: entity.StatusCancelled, EffectiveDate: *day("2025-03-10"), ChangedAt: changedAt},
+		{SubscriptionID: first, From: entity.StatusPaused, To: entity.StatusActive, EffectiveDate: *day("2025-04-01"), ChangedAt: changedAt},
+	}
+
+	for _, change := range changes {
+		if err := repo.AddStatusChange(ctx, change); err != nil {
+			t.Fatalf("AddStatusChange() error = %v", err)
+		}
+	}
+
+	history, err := repo.StatusHistory(ctx, []uuid.UUID{first})
+	if err != nil {
+		t.Fatalf("StatusHistory() error = %v", err)
+	}
+
+	assertHistory(t, history, changes[0], changes[2])
+
+	history, err = repo.StatusHistory(ctx, []uuid.UUID{first, second})
+	if err != nil {
+		t.Fatalf("StatusHistory() error = %v", err)
+	}
+
+	// Grouped by subscription in id order, each group oldest first.
+	if bytes.Compare(first.Bytes(), second.Bytes()) < 0 {
+		assertHistory(t, history, changes[0], changes[2], changes[1])
+	} else {
+		assertHistory(t, history, changes[1], changes[0], changes[2])
+	}
+
+	purge(t, repo, first)
+
+	history, err = repo.StatusHistory(ctx, []uuid.UUID{first, second})
+	if err != nil {
+		t.Fatalf("StatusHistory() error = %v", err)
+	}
+
+	assertHistory(t, history, changes[1])
+}
+
+func testEndingTrials(t *testing.T, repo Repo) {
+	ctx := context.Background()
+	user := uuid.Must(uuid.NewV4())
+
+	withTrial := func(trialEnd string, status entity.SubscriptionStatus) uuid.UUID {
+		sub := subscription(user, "Kinopoisk", "2025-07-01", "")
+		sub.TrialStart = day("2025-07-01")
+		sub.TrialEnd = day(trialEnd)
+		sub.Status = status
+
+		return create(t, repo, sub)
+	}
+
+	endingID := withTrial("2025-07-10", entity.StatusActive)
+	withTrial("2025-07-20", entity.StatusActive)
+	withTrial("2025-07-05", entity.StatusCancelled)
+	create(t, repo, subscription(user, "Kinopoisk", "2025-07-01", ""))
+
+	ending, err := repo.EndingTrials(ctx, *day("2025-07-03"), *day("2025-07-10"))
+	if err != nil {
+		t.Fatalf("EndingTrials() error = %v", err)
+	}
+
+	assertIDs(t, ending, endingID)
+
+	at := time.Date(2025, 7, 3, 9, 0, 0, 0, time.UTC)
+
+	flagged := ending[0]
+	flagged.TrialEndingFlaggedAt = &at
+
+	if err := repo.UpdateSubscription(ctx, flagged); err != nil {
+		t.Fatalf("UpdateSubscription() error = %v", err)
+	}
+
+	ending, err = repo.EndingTrials(ctx, *day("2025-07-03"), *day("2025-07-10"))
+	if err != nil {
+		t.Fatalf("EndingTrials() error = %v", err)
+	}
+
+	if len(ending) != 0 {
+		t.Fatalf("EndingTrials() returned %d flagged subscriptions", len(ending))
+	}
+}
+
+func testEndedSubscriptions(t *testing.T, repo Repo) {
+	ctx := context.Background()
+	user := uuid.Must(uuid.NewV4())
+
+	withStatus := func(end string, status entity.SubscriptionStatus) uuid.UUID {
+		sub := subscription(user, "Okko", "2025-01-01", end)
+		sub.Status = status
+
+		return create(t, repo, sub)
+	}
+
+	lastID := withStatus("2025-06-30", entity.StatusActive)
+	firstID := withStatus("2025-05-31", entity.StatusCancelled)
+	withStatus("2025-05-15", entity.StatusExpired)
+	withStatus("2025-07-01", entity.StatusActive)
+	withStatus("", entity.StatusPaused)
+
+	ended, err := repo.EndedSubscriptions(ctx, *day("2025-07-01"))
+	if err != nil {
+		t.Fatalf("EndedSubscriptions() error = %v", err)
+	}
+
+	assertIDs(t, ended, firstID, lastID)
+}
+
+func testPriceHistory(t *testing.T, repo Repo) {
+	ctx := context.Background()
+	userID := uuid.Must(uuid.NewV4())
+
+	id := create(t, repo, subscription(userID, "Netflix", "2025-01-15", ""))
+	other := create(t, repo, subscription(userID, "Spotify", "2025-01-01", ""))
+
+	periods := []entity.PricePeriod{
+		{SubscriptionID: id, Price: entity.NewMoney(59900, "RUB"), EffectiveFrom: *day("2025-06-01")},
+		{SubscriptionID: id, Price: entity.NewMoney(39900, "RUB"), EffectiveFrom: *day("2025-01-15")},
+		{SubscriptionID: other, Price: entity.NewMoney(19900, "RUB"), EffectiveFrom: *day("2025-01-01")},
+		// Replaces the price of the same day.
+		{SubscriptionID: id, Price: entity.NewMoney(6, "USD"), EffectiveFrom: *day("2025-06-01")},
+	}
+
+	for _, period := range periods {
+		if err := repo.SetPrice(ctx, period); err != nil {
+			t.Fatalf("SetPrice() error = %v", err)
+		}
+	}
+
+	history, err := repo.PriceHistory(ctx, []uuid.UUID{id})
+	if err != nil {
+		t.Fatalf("PriceHistory() error = %v", err)
+	}
+
+	assertPrices(t, history, periods[1], periods[3])
+
+	purge(t, repo, id)
+
+	history, err = repo.PriceHistory(ctx, []uuid.UUID{id, other})
+	if err != nil {
+		t.Fatalf("PriceHistory() error = %v", err)
+	}
+
+	assertPrices(t, history, periods[2])
+}
+
+func testCatalog(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	yandex := entity.CatalogEntry{
+		Name:         "Yandex Plus",
+		Aliases:      []string{"Яндекс Плюс", "yandex+"},
+		Category:     entity.CategoryStreaming,
+		Homepage:     "https://plus.yandex.ru",
+		DefaultPrice: ptr(entity.NewMoney(39900, "RUB")),
+	}
+
+	var err error
+	if yandex.ID, err = repo.CreateCatalogEntry(ctx, yandex); err != nil {
+		t.Fatalf("CreateCatalogEntry() error = %v", err)
+	}
+
+	netflix := entity.CatalogEntry{Name: "Netflix", Aliases: []string{}}
+	if netflix.ID, err = repo.CreateCatalogEntry(ctx, netflix); err != nil {
+		t.Fatalf("CreateCatalogEntry() error = %v", err)
+	}
+
+	got, err := repo.CatalogEntryByID(ctx, yandex.ID)
+	if err != nil {
+		t.Fatalf("CatalogEntryByID() error = %v", err)
+	}
+
+	assertEntry(t, got, yandex)
+
+	for _, name := range []string{"Yandex Plus", " yandex plus ", "ЯНДЕКС ПЛЮС", "Yandex+"} {
+		got, err := repo.CatalogEntryByName(ctx, name)
+		if err != nil {
+			t.Fatalf("CatalogEntryByName(%q) error = %v", name, err)
+		}
+
+		assertEntry(t, got, yandex)
+	}
+
+	if _, err := repo.CatalogEntryByName(ctx, "Okko"); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("CatalogEntryByName() of a missing service error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	if _, err := repo.CatalogEntryByID(ctx, uuid.Must(uuid.NewV4())); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("CatalogEntryByID() of a missing service error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	_, err = repo.CreateCatalogEntry(ctx, entity.CatalogEntry{Name: "Okko", Aliases: []string{"NETFLIX"}})
+	if !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Fatalf("CreateCatalogEntry() with a taken name error = %v, want %v", err, entity.ErrAlreadyExists)
+	}
+
+	if _, err := repo.CatalogEntryByName(ctx, "Okko"); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("CatalogEntryByName() of a rejected service error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	entries, err := repo.CatalogEntries(ctx)
+	if err != nil {
+		t.Fatalf("CatalogEntries() error = %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("CatalogEntries() returned %d entries, want 2", len(entries))
+	}
+
+	assertEntry(t, entries[0], netflix)
+	assertEntry(t, entries[1], yandex)
+
+	sub := subscription(uuid.Must(uuid.NewV4()), yandex.Name, "2025-01-01", "")
+	sub.ServiceID = yandex.ID
+	subID := create(t, repo, sub)
+
+	if err := repo.DeleteCatalogEntry(ctx, yandex.ID); !errors.Is(err, entity.ErrServiceInUse) {
+		t.Fatalf("DeleteCatalogEntry() of a service in use error = %v, want %v", err, entity.ErrServiceInUse)
+	}
+
+	if err := repo.DeleteSubscription(ctx, subID, 1, time.Now()); err != nil {
+		t.Fatalf("DeleteSubscription() error = %v", err)
+	}
+
+	if err := repo.DeleteCatalogEntry(ctx, yandex.ID); !errors.Is(err, entity.ErrServiceInUse) {
+		t.Fatalf("DeleteCatalogEntry() of a service in the trash error = %v, want %v", err, entity.ErrServiceInUse)
+	}
+
+	if _, err := repo.PurgeSubscriptions(ctx, time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("PurgeSubscriptions() error = %v", err)
+	}
+
+	if err := repo.DeleteCatalogEntry(ctx, yandex.ID); err != nil {
+		t.Fatalf("DeleteCatalogEntry() error = %v", err)
+	}
+
+	if _, err := repo.CatalogEntryByName(ctx, "yandex+"); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("CatalogEntryByName() of a deleted service error = %v, want %v", err, entity.ErrNotFound)
+	}
+}
+
+func testCatalogUpdate(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	kino := entity.CatalogEntry{Name: "Kinopoisk", Aliases: []string{"КиноПоиск"}}
+
+	var err error
+	if kino.ID, err = repo.CreateCatalogEntry(ctx, kino); err != nil {
+		t.Fatalf("CreateCatalogEntry() error = %v", err)
+	}
+
+	okko := serviceID(t, repo, "Okko")
+
+	sub := subscription(uuid.Must(uuid.NewV4()), kino.Name, "2025-01-01", "")
+	sub.ServiceID = kino.ID
+	sub.ID = create(t, repo, sub)
+
+	kino.Name = "Kinopoisk HD"
+	kino.Aliases = []string{"Kinopoisk", "Кинопоиск"}
+	kino.DefaultPrice = ptr(entity.NewMoney(29900, "RUB"))
+
+	if err := repo.UpdateCatalogEntry(ctx, kino); err != nil {
+		t.Fatalf("UpdateCatalogEntry() error = %v", err)
+	}
+
+	got, err := repo.CatalogEntryByName(ctx, "kinopoisk")
+	if err != nil {
+		t.Fatalf("CatalogEntryByName() error = %v", err)
+	}
+
+	assertEntry(t, got, kino)
+
+	stored, err := repo.SubscriptionByID(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("SubscriptionByID() error = %v", err)
+	}
+
+	sub.ServiceName = kino.Name
+	sub.Version++
+	assertEqual(t, stored, sub)
+
+	clash := kino
+	clash.Aliases = []string{"okko"}
+	if err := repo.UpdateCatalogEntry(ctx, clash); !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Fatalf("UpdateCatalogEntry() with a taken alias error = %v, want %v", err, entity.ErrAlreadyExists)
+	}
+
+	if got, err := repo.CatalogEntryByName(ctx, "Кинопоиск"); err != nil || got.ID != kino.ID {
+		t.Fatalf("CatalogEntryByName() after a rejected update = %+v, %v", got, err)
+	}
+
+	if got, err := repo.CatalogEntryByName(ctx, "OKKO"); err != nil || got.ID != okko {
+		t.Fatalf("CatalogEntryByName() after a rejected update = %+v, %v", got, err)
+	}
+
+	missing := entity.CatalogEntry{ID: uuid.Must(uuid.NewV4()), Name: "Missing"}
+	if err := repo.UpdateCatalogEntry(ctx, missing); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("UpdateCatalogEntry() of a missing service error = %v, want %v", err, entity.ErrNotFound)
+	}
+}
+
+func testTags(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	alice := uuid.Must(uuid.NewV4())
+	bob := uuid.Must(uuid.NewV4())
+
+	first := create(t, repo, subscription(alice, "Netflix", "2025-01-01", ""))
+	second := create(t, repo, subscription(alice, "Spotify", "2025-01-01", ""))
+	untagged := create(t, repo, subscription(alice, "Okko", "2025-01-01", ""))
+	other := create(t, repo, subscription(bob, "Netflix", "2025-01-01", ""))
+
+	sets := []struct {
+		user uuid.UUID
+		id   uuid.UUID
+		tags []string
+	}{
+		{alice, first, []string{"work", "family"}},
+		{alice, second, []string{"family"}},
+		{bob, other, []string{"family"}},
+		// Replaces the earlier tags of the subscription.
+		{alice, first, []string{"video", "family"}},
+	}
+
+	for _, set := range sets {
+		if err := repo.SetTags(ctx, set.user, set.id, set.tags); err != nil {
+			t.Fatalf("SetTags() error = %v", err)
+		}
+	}
+
+	tags, err := repo.SubscriptionTags(ctx, []uuid.UUID{first, second, untagged})
+	if err != nil {
+		t.Fatalf("SubscriptionTags() error = %v", err)
+	}
+
+	want := map[uuid.UUID][]string{
+		first:  {"family", "video"},
+		second: {"family"},
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Fatalf("SubscriptionTags() = %v, want %v", tags, want)
+	}
+
+	if err := repo.SetTags(ctx, alice, second, nil); err != nil {
+		t.Fatalf("SetTags() error = %v", err)
+	}
+
+	purge(t, repo, first)
+
+	tags, err = repo.SubscriptionTags(ctx, []uuid.UUID{first, second, other})
+	if err != nil {
+		t.Fatalf("SubscriptionTags() error = %v", err)
+	}
+
+	want = map[uuid.UUID][]string{other: {"family"}}
+	if !reflect.DeepEqual(tags, want) {
+		t.Fatalf("SubscriptionTags() after removal = %v, want %v", tags, want)
+	}
+}
+
+func testCalendarTokens(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	alice := uuid.Must(uuid.NewV4())
+	bob := uuid.Must(uuid.NewV4())
+
+	for _, set := range []struct {
+		user uuid.UUID
+		hash string
+	}{
+		{alice, "alice-old"},
+		{bob, "bob"},
+		// Replaces the earlier token of the user.
+		{alice, "alice"},
+	} {
+		if err := repo.SetCalendarToken(ctx, set.user, set.hash); err != nil {
+			t.Fatalf("SetCalendarToken() error = %v", err)
+		}
+	}
+
+	for hash, want := range map[string]uuid.UUID{"alice": alice, "bob": bob} {
+		got, err := repo.CalendarUser(ctx, hash)
+		if err != nil {
+			t.Fatalf("CalendarUser(%q) error = %v", hash, err)
+		}
+
+		if got != want {
+			t.Fatalf("CalendarUser(%q) = %v, want %v", hash, got, want)
+		}
+	}
+
+	if _, err := repo.CalendarUser(ctx, "alice-old"); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("CalendarUser() of a replaced token error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	if err := repo.DeleteCalendarToken(ctx, alice); err != nil {
+		t.Fatalf("DeleteCalendarToken() error = %v", err)
+	}
+
+	if _, err := repo.CalendarUser(ctx, "alice"); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("CalendarUser() of a deleted token error = %v, want %v", err, entity.ErrNotFound)
+	}
+}
+
+func testBudgets(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	alice := uuid.Must(uuid.NewV4())
+	bob := uuid.Must(uuid.NewV4())
+
+	yearly := entity.Budget{UserID: alice, Period: entity.BudgetYearly, Limit: entity.NewMoney(1200000, "RUB")}
+	monthly := entity.Budget{UserID: alice, Period: entity.BudgetMonthly, Limit: entity.NewMoney(100000, "RUB")}
+	streaming := entity.Budget{UserID: alice, Period: entity.BudgetMonthly, Category: entity.CategoryStreaming, Limit: entity.NewMoney(50000, "RUB")}
+	other := entity.Budget{UserID: bob, Period: entity.BudgetMonthly, Limit: entity.NewMoney(2000, "USD")}
+
+	for _, b := range []*entity.Budget{&yearly, &streaming, &monthly, &other} {
+		id, err := repo.CreateBudget(ctx, *b)
+		if err != nil {
+			t.Fatalf("CreateBudget() error = %v", err)
+		}
+
+		b.ID = id
+	}
+
+	if _, err := repo.CreateBudget(ctx, entity.Budget{UserID: alice, Period: entity.BudgetMonthly, Limit: entity.NewMoney(1, "RUB")}); !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Fatalf("CreateBudget() of a taken period error = %v, want %v", err, entity.ErrAlreadyExists)
+	}
+
+	budgets, err := repo.Budgets(ctx, alice)
+	if err != nil {
+		t.Fatalf("Budgets() error = %v", err)
+	}
+
+	if want := []entity.Budget{monthly, streaming, yearly}; !reflect.DeepEqual(budgets, want) {
+		t.Fatalf("Budgets() = %+v, want %+v", budgets, want)
+	}
+
+	// The user of a budget never changes.
+	streaming.Period = entity.BudgetYearly
+	streaming.Limit = entity.NewMoney(600000, "RUB")
+	if err := repo.UpdateBudget(ctx, entity.Budget{ID: streaming.ID, UserID: bob, Period: streaming.Period, Category: streaming.Category, Limit: streaming.Limit}); err != nil {
+		t.Fatalf("UpdateBudget() error = %v", err)
+	}
+
+	got, err := repo.BudgetByID(ctx, streaming.ID)
+	if err != nil {
+		t.Fatalf("BudgetByID() error = %v", err)
+	}
+
+	if got != streaming {
+		t.Fatalf("BudgetByID() = %+v, want %+v", got, streaming)
+	}
+
+	monthly.Period = entity.BudgetYearly
+	if err := repo.UpdateBudget(ctx, monthly); !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Fatalf("UpdateBudget() to a taken period error = %v, want %v", err, entity.ErrAlreadyExists)
+	}
+
+	if err := repo.DeleteBudget(ctx, yearly.ID); err != nil {
+		t.Fatalf("DeleteBudget() error = %v", err)
+	}
+
+	if err := repo.DeleteBudget(ctx, yearly.ID); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("DeleteBudget() of a deleted budget error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	missing := uuid.Must(uuid.NewV4())
+
+	if err := repo.UpdateBudget(ctx, entity.Budget{ID: missing, Period: entity.BudgetMonthly, Limit: entity.NewMoney(1, "RUB")}); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("UpdateBudget() of a missing budget error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	if _, err := repo.BudgetByID(ctx, missing); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("BudgetByID() of a missing budget error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	budgets, err = repo.Budgets(ctx, bob)
+	if err != nil {
+		t.Fatalf("Budgets() error = %v", err)
+	}
+
+	if want := []entity.Budget{other}; !reflect.DeepEqual(budgets, want) {
+		t.Fatalf("Budgets() of another user = %+v, want %+v", budgets, want)
+	}
+}
+
+func testNotificationPreferences(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	alice := uuid.Must(uuid.NewV4())
+
+	prefs := []entity.NotificationPreference{
+		{Channel: entity.ChannelWebhook, Address: "https://example.com/hook"},
+		{Channel: entity.ChannelEmail, Address: "b@example.com", Events: []entity.NotificationEvent{entity.EventRenewal}},
+		{Channel: entity.ChannelEmail, Address: "a@example.com", Events: []entity.NotificationEvent{entity.EventTrialEnding, entity.EventBudgetExceeded}},
+	}
+
+	if err := repo.SetNotificationPreferences(ctx, alice, prefs); err != nil {
+		t.Fatalf("SetNotificationPreferences() error = %v", err)
+	}
+
+	got, err := repo.NotificationPreferences(ctx, alice)
+	if err != nil {
+		t.Fatalf("NotificationPreferences() error = %v", err)
+	}
+
+	if want := []entity.NotificationPreference{prefs[2], prefs[1], prefs[0]}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("NotificationPreferences() = %+v, want %+v", got, want)
+	}
+
+	// Replaces the earlier preferences.
+	if err := repo.SetNotificationPreferences(ctx, alice, prefs[:1]); err != nil {
+		t.Fatalf("SetNotificationPreferences() error = %v", err)
+	}
+
+	if got, err = repo.NotificationPreferences(ctx, alice); err != nil {
+		t.Fatalf("NotificationPreferences() error = %v", err)
+	}
+
+	if want := prefs[:1]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("NotificationPreferences() after replace = %+v, want %+v", got, want)
+	}
+
+	if err := repo.SetNotificationPreferences(ctx, alice, nil); err != nil {
+		t.Fatalf("SetNotificationPreferences() error = %v", err)
+	}
+
+	if got, err = repo.NotificationPreferences(ctx, alice); err != nil || len(got) != 0 {
+		t.Fatalf("NotificationPreferences() after removal = %+v, %v, want none", got, err)
+	}
+}
+
+func testDeliveries(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	alice := uuid.Must(uuid.NewV4())
+	bob := uuid.Must(uuid.NewV4())
+	start := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	deliveries := []entity.Delivery{
+		{UserID: alice, Channel: entity.ChannelEmail, Address: "a@example.com", Event: entity.EventRenewal, Subject: "first", Key: "renewal:1",
+			Text: "Netflix renews tomorrow", Data: json.RawMessage(`{"price":"9.99 USD"}`)},
+		{UserID: alice, Channel: entity.ChannelWebhook, Address: "https://example.com/hook", Event: entity.EventRenewal, Subject: "second", Key: "renewal:1"},
+		{UserID: alice, Channel: entity.ChannelEmail, Address: "a@example.com", Event: entity.EventBudgetExceeded, Subject: "third"},
+		{UserID: alice, Channel: entity.ChannelEmail, Address: "a@example.com", Event: entity.EventBudgetExceeded, Subject: "fourth"},
+		{UserID: bob, Channel: entity.ChannelEmail, Address: "a@example.com", Event: entity.EventRenewal, Subject: "other", Key: "renewal:1"},
+	}
+
+	for i := range deliveries {
+		deliveries[i].Status = entity.DeliveryPending
+		deliveries[i].CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		deliveries[i].UpdatedAt = deliveries[i].CreatedAt
+		deliveries[i].NextAttemptAt = deliveries[i].CreatedAt
+
+		id, err := repo.CreateDelivery(ctx, deliveries[i])
+		if err != nil {
+			t.Fatalf("CreateDelivery(%s) error = %v", deliveries[i].Subject, err)
+		}
+
+		deliveries[i].ID = id
+	}
+
+	duplicate := deliveries[0]
+	if _, err := repo.CreateDelivery(ctx, duplicate); !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Fatalf("CreateDelivery() of a used key error = %v, want %v", err, entity.ErrAlreadyExists)
+	}
+
+	deliveries[0].Status = entity.DeliveryFailed
+	deliveries[0].Attempts = 3
+	deliveries[0].LastError = "connection refused"
+	deliveries[0].UpdatedAt = start.Add(time.Hour)
+
+	if err := repo.UpdateDelivery(ctx, deliveries[0]); err != nil {
+		t.Fatalf("UpdateDelivery() error = %v", err)
+	}
+
+	got, err := repo.Deliveries(ctx, alice, 3)
+	if err != nil {
+		t.Fatalf("Deliveries() error = %v", err)
+	}
+
+	if want := []entity.Delivery{deliveries[3], deliveries[2], deliveries[1]}; !equalDeliveries(got, want) {
+		t.Fatalf("Deliveries() = %+v, want %+v", got, want)
+	}
+
+	if got, err = repo.Deliveries(ctx, alice, 10); err != nil {
+		t.Fatalf("Deliveries() error = %v", err)
+	}
+
+	if want := []entity.Delivery{deliveries[3], deliveries[2], deliveries[1], deliveries[0]}; !equalDeliveries(got, want) {
+		t.Fatalf("Deliveries() = %+v, want %+v", got, want)
+	}
+
+	deliveries[1].Attempts = 1
+	deliveries[1].LastError = "unexpected status 500"
+	deliveries[1].NextAttemptAt = start.Add(time.Hour)
+	deliveries[1].UpdatedAt = start.Add(2 * time.Minute)
+
+	if err := repo.UpdateDelivery(ctx, deliveries[1]); err != nil {
+		t.Fatalf("UpdateDelivery() error = %v", err)
+	}
+
+	due, err := repo.DueDeliveries(ctx, start.Add(10*time.Minute), 2)
+	if err != nil {
+		t.Fatalf("DueDeliveries() error = %v", err)
+	}
+
+	if want := []entity.Delivery{deliveries[2], deliveries[3]}; !equalDeliveries(due, want) {
+		t.Fatalf("DueDeliveries() = %+v, want %+v", due, want)
+	}
+
+	if due, err = repo.DueDeliveries(ctx, start.Add(time.Hour), 10); err != nil {
+		t.Fatalf("DueDeliveries() error = %v", err)
+	}
+
+	if want := []entity.Delivery{deliveries[2], deliveries[3], deliveries[4], deliveries[1]}; !equalDeliveries(due, want) {
+		t.Fatalf("DueDeliveries() past the retry = %+v, want %+v", due, want)
+	}
+}
+
+func testOutbox(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+	at := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	before := subscription(user, "Okko", "2026-01-01", "")
+	before.ID = uuid.Must(uuid.NewV4())
+	after := before
+	after.Price = entity.NewMoney(49900, "RUB")
+
+	other := subscription(user, "Kion", "2026-01-01", "")
+	other.ID = uuid.Must(uuid.NewV4())
+
+	events := []entity.SubscriptionEvent{
+		{Type: entity.SubscriptionCreated, SubscriptionID: before.ID, After: &before},
+		{Type: entity.SubscriptionUpdated, SubscriptionID: before.ID, Before: &before, After: &after},
+		{Type: entity.SubscriptionDeleted, SubscriptionID: before.ID, Before: &after},
+		{Type: entity.SubscriptionCreated, SubscriptionID: other.ID, After: &other},
+	}
+
+	for i := range events {
+		events[i].ID = uuid.Must(uuid.NewV4())
+		events[i].UserID = user
+		events[i].OccurredAt = at.Add(time.Duration(i) * time.Second)
+
+		if err := repo.AddOutboxEvent(ctx, events[i]); err != nil {
+			t.Fatalf("AddOutboxEvent() error = %v", err)
+		}
+	}
+
+	errRollback := errors.New("rollback")
+
+	err := repo.WithTx(ctx, func(ctx context.Context) error {
+		rolledBack := events[0]
+		rolledBack.ID = uuid.Must(uuid.NewV4())
+
+		if err := repo.AddOutboxEvent(ctx, rolledBack); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithTx() error = %v, want %v", err, errRollback)
+	}
+
+	pending, err := repo.PendingOutboxEvents(ctx, at.Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("PendingOutboxEvents() error = %v", err)
+	}
+
+	if len(pending) != len(events) {
+		t.Fatalf("PendingOutboxEvents() returned %d events, want %d", len(pending), len(events))
+	}
+
+	for i, e := range pending {
+		if !reflect.DeepEqual(e.Event, events[i]) {
+			t.Fatalf("pending event %d = %+v, want %+v", i, e.Event, events[i])
+		}
+
+		if i > 0 && e.Seq <= pending[i-1].Seq {
+			t.Fatalf("pending event %d has seq %d after %d", i, e.Seq, pending[i-1].Seq)
+		}
+
+		if e.Attempts != 0 || !e.NextAttemptAt.Equal(events[i].OccurredAt) {
+			t.Fatalf("pending event %d: attempts = %d, next attempt at %v", i, e.Attempts, e.NextAttemptAt)
+		}
+	}
+
+	if err := repo.MarkOutboxEventPublished(ctx, pending[0].Seq, at.Add(time.Minute)); err != nil {
+		t.Fatalf("MarkOutboxEventPublished() error = %v", err)
+	}
+
+	retryAt := at.Add(time.Hour)
+	if err := repo.MarkOutboxEventFailed(ctx, pending[1].Seq, "connection refused", retryAt); err != nil {
+		t.Fatalf("MarkOutboxEventFailed() error = %v", err)
+	}
+
+	if pending, err = repo.PendingOutboxEvents(ctx, at.Add(time.Minute), 1); err != nil {
+		t.Fatalf("PendingOutboxEvents() error = %v", err)
+	}
+
+	if len(pending) != 1 || pending[0].Event.ID != events[3].ID {
+		t.Fatalf("PendingOutboxEvents() before the retry = %+v, want the other subscription's event only", pending)
+	}
+
+	if pending, err = repo.PendingOutboxEvents(ctx, retryAt, 1); err != nil {
+		t.Fatalf("PendingOutboxEvents() error = %v", err)
+	}
+
+	if len(pending) != 1 || pending[0].Event.ID != events[1].ID {
+		t.Fatalf("PendingOutboxEvents() at the retry = %+v, want the second event only", pending)
+	}
+
+	if got := pending[0]; got.Attempts != 1 || got.LastError != "connection refused" || !got.NextAttemptAt.Equal(retryAt) {
+		t.Fatalf("failed event: attempts = %d, last error %q, next attempt at %v", got.Attempts, got.LastError, got.NextAttemptAt)
+	}
+
+	if purged, err := repo.PurgeOutboxEvents(ctx, at.Add(time.Minute)); err != nil || purged != 0 {
+		t.Fatalf("PurgeOutboxEvents() at the publish time = %d, %v, want 0", purged, err)
+	}
+
+	if purged, err := repo.PurgeOutboxEvents(ctx, at.Add(2*time.Minute)); err != nil || purged != 1 {
+		t.Fatalf("PurgeOutboxEvents() = %d, %v, want 1", purged, err)
+	}
+
+	if pending, err = repo.PendingOutboxEvents(ctx, retryAt, 10); err != nil {
+		t.Fatalf("PendingOutboxEvents() error = %v", err)
+	}
+
+	if len(pending) != 3 {
+		t.Fatalf("PendingOutboxEvents() after the purge returned %d events, want 3", len(pending))
+	}
+}
+
+func testWebhooks(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+	at := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	webhooks := []entity.Webhook{
+		{URL: "https://example.com/all", Secret: "0123456789abcdef", Enabled: true, CreatedAt: at},
+		{
+			URL:       "https://example.com/created",
+			Events:    []entity.SubscriptionEventType{entity.SubscriptionCreated, entity.SubscriptionDeleted},
+			UserID:    &user,
+			Secret:    "fedcba9876543210",
+			Enabled:   true,
+			CreatedAt: at.Add(time.Minute),
+		},
+	}
+
+	for i := range webhooks {
+		id, err := repo.CreateWebhook(ctx, webhooks[i])
+		if err != nil {
+			t.Fatalf("CreateWebhook() error = %v", err)
+		}
+
+		webhooks[i].ID = id
+	}
+
+	got, err := repo.WebhookByID(ctx, webhooks[1].ID)
+	if err != nil {
+		t.Fatalf("WebhookByID() error = %v", err)
+	}
+
+	if !equalWebhooks([]entity.Webhook{got}, webhooks[1:]) {
+		t.Fatalf("WebhookByID() = %+v, want %+v", got, webhooks[1])
+	}
+
+	if _, err := repo.WebhookByID(ctx, uuid.Must(uuid.NewV4())); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("WebhookByID() of a missing webhook error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	for i := 1; i <= 3; i++ {
+		disabled, err := repo.WebhookFailed(ctx, webhooks[0].ID, 3, at.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("WebhookFailed() error = %v", err)
+		}
+
+		if disabled != (i == 3) {
+			t.Fatalf("failure %d disabled = %t", i, disabled)
+		}
+	}
+
+	if disabled, err := repo.WebhookFailed(ctx, webhooks[0].ID, 3, at.Add(2*time.Hour)); err != nil || disabled {
+		t.Fatalf("WebhookFailed() of a disabled webhook = %t, %v, want false, nil", disabled, err)
+	}
+
+	webhooks[0].Enabled = false
+	webhooks[0].Failures = 4
+	webhooks[0].DisabledAt = ptr(at.Add(time.Hour))
+
+	if _, err := repo.WebhookFailed(ctx, webhooks[1].ID, 3, at); err != nil {
+		t.Fatalf("WebhookFailed() error = %v", err)
+	}
+
+	list, err := repo.Webhooks(ctx)
+	if err != nil {
+		t.Fatalf("Webhooks() error = %v", err)
+	}
+
+	webhooks[1].Failures = 1
+
+	if !equalWebhooks(list, webhooks) {
+		t.Fatalf("Webhooks() = %+v, want %+v", list, webhooks)
+	}
+
+	if err := repo.WebhookSucceeded(ctx, webhooks[1].ID); err != nil {
+		t.Fatalf("WebhookSucceeded() error = %v", err)
+	}
+
+	if err := repo.EnableWebhook(ctx, webhooks[0].ID); err != nil {
+		t.Fatalf("EnableWebhook() error = %v", err)
+	}
+
+	webhooks[0].Enabled = true
+	webhooks[0].Failures = 0
+	webhooks[0].DisabledAt = nil
+	webhooks[1].Failures = 0
+
+	if list, err = repo.Webhooks(ctx); err != nil {
+		t.Fatalf("Webhooks() error = %v", err)
+	}
+
+	if !equalWebhooks(list, webhooks) {
+		t.Fatalf("Webhooks() = %+v, want %+v", list, webhooks)
+	}
+
+	if err := repo.DeleteWebhook(ctx, webhooks[0].ID); err != nil {
+		t.Fatalf("DeleteWebhook() error = %v", err)
+	}
+
+	missing := webhooks[0].ID
+
+	if err := repo.DeleteWebhook(ctx, missing); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("DeleteWebhook() of a missing webhook error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	if err := repo.EnableWebhook(ctx, missing); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("EnableWebhook() of a missing webhook error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	if _, err := repo.WebhookFailed(ctx, missing, 3, at); !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("WebhookFailed() of a missing webhook error = %v, want %v", err, entity.ErrNotFound)
+	}
+
+	if list, err = repo.Webhooks(ctx); err != nil {
+		t.Fatalf("Webhooks() error = %v", err)
+	}
+
+	if !equalWebhooks(list, webhooks[1:]) {
+		t.Fatalf("Webhooks() = %+v, want %+v", list, webhooks[1:])
+	}
+}
+
+func testWebhookDeliveries(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	at := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	hooks := make([]uuid.UUID, 2)
+	for i := range hooks {
+		id, err := repo.CreateWebhook(ctx, entity.Webhook{
+			URL:       "https://example.com/hook",
+			Secret:    "0123456789abcdef",
+			Enabled:   true,
+			CreatedAt: at,
+		})
+		if err != nil {
+			t.Fatalf("CreateWebhook() error = %v", err)
+		}
+
+		hooks[i] = id
+	}
+
+	event := uuid.Must(uuid.NewV4())
+
+	deliveries := []entity.WebhookDelivery{
+		{WebhookID: hooks[0], EventID: event, NextAttemptAt: at.Add(2 * time.Second)},
+		{WebhookID: hooks[0], EventID: uuid.Must(uuid.NewV4()), NextAttemptAt: at.Add(time.Second)},
+		{WebhookID: hooks[0], EventID: uuid.Must(uuid.NewV4()), NextAttemptAt: at.Add(time.Hour)},
+		{WebhookID: hooks[1], EventID: event, NextAttemptAt: at},
+	}
+
+	for i := range deliveries {
+		deliveries[i].EventType = entity.SubscriptionCreated
+		deliveries[i].Payload = []byte(`{"id":"` + deliveries[i].EventID.String() + `"}`)
+		deliveries[i].Status = entity.DeliveryPending
+		deliveries[i].CreatedAt = at.Add(time.Duration(i) * time.Millisecond)
+		deliveries[i].UpdatedAt = deliveries[i].CreatedAt
+
+		id, err := repo.CreateWebhookDelivery(ctx, deliveries[i])
+		if err != nil {
+			t.Fatalf("CreateWebhookDelivery() error = %v", err)
+		}
+
+		deliveries[i].ID = id
+	}
+
+	if _, err := repo.CreateWebhookDelivery(ctx, deliveries[0]); !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Fatalf("CreateWebhookDelivery() of a queued event error = %v, want %v", err, entity.ErrAlreadyExists)
+	}
+
+	due, err := repo.DueWebhookDeliveries(ctx, at.Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("DueWebhookDeliveries() error = %v", err)
+	}
+
+	if want := []entity.WebhookDelivery{deliveries[3], deliveries[1], deliveries[0]}; !equalWebhookDeliveries(due, want) {
+		t.Fatalf("DueWebhookDeliveries() = %+v, want %+v", due, want)
+	}
+
+	deliveries[1].Status = entity.DeliverySent
+	deliveries[1].Attempts = 1
+	deliveries[1].ResponseStatus = 204
+	deliveries[1].UpdatedAt = at.Add(time.Minute)
+
+	deliveries[0].Attempts = 1
+	deliveries[0].ResponseStatus = 500
+	deliveries[0].LastError = "unexpected status 500"
+	deliveries[0].NextAttemptAt = at.Add(2 * time.Minute)
+	deliveries[0].UpdatedAt = at.Add(time.Minute)
+
+	for _, d := range deliveries[:2] {
+		if err := repo.UpdateWebhookDelivery(ctx, d); err != nil {
+			t.Fatalf("UpdateWebhookDelivery() error = %v", err)
+		}
+	}
+
+	if _, err := repo.WebhookFailed(ctx, hooks[1], 1, at); err != nil {
+		t.Fatalf("WebhookFailed() error = %v", err)
+	}
+
+	if due, err = repo.DueWebhookDeliveries(ctx, at.Add(2*time.Minute), 10); err != nil {
+		t.Fatalf("DueWebhookDeliveries() error = %v", err)
+	}
+
+	if want := []entity.WebhookDelivery{deliveries[0]}; !equalWebhookDeliveries(due, want) {
+		t.Fatalf("DueWebhookDeliveries() past the retry = %+v, want %+v", due, want)
+	}
+
+	got, err := repo.WebhookDeliveries(ctx, hooks[0], 2)
+	if err != nil {
+		t.Fatalf("WebhookDeliveries() error = %v", err)
+	}
+
+	if want := []entity.WebhookDelivery{deliveries[2], deliveries[1]}; !equalWebhookDeliveries(got, want) {
+		t.Fatalf("WebhookDeliveries() = %+v, want %+v", got, want)
+	}
+
+	if err := repo.DeleteWebhook(ctx, hooks[0]); err != nil {
+		t.Fatalf("DeleteWebhook() error = %v", err)
+	}
+
+	if got, err = repo.WebhookDeliveries(ctx, hooks[0], 10); err != nil || len(got) != 0 {
+		t.Fatalf("WebhookDeliveries() of a deleted webhook = %+v, %v, want none", got, err)
+	}
+}
+
+func testAuditLog(t *testing.T, repo Repo) {
+	ctx := context.Background()
+
+	at := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	okko := uuid.Must(uuid.NewV4())
+	ivi := uuid.Must(uuid.NewV4())
+
+	entries := []entity.AuditEntry{
+		{Actor: "alice", RequestID: "r1", Action: entity.AuditCreate, EntityID: okko, Changes: map[string]entity.FieldChange{
+			"service_name": {After: json.RawMessage(`"Okko"`)},
+		}},
+		{Actor: "bob", RequestID: "r2", Action: entity.AuditRestore, EntityID: ivi, Changes: map[string]entity.FieldChange{}},
+		{Actor: "alice", RequestID: "r3", Action: entity.AuditUpdate, EntityID: okko, Changes: map[string]entity.FieldChange{
+			"price": {Before: json.RawMessage(`{"amount":"399.00","currency":"RUB"}`), After: json.RawMessage(`{"amount":"499.00","currency":"RUB"}`)},
+		}},
+		{Actor: "job:expire", Action: entity.AuditDelete, EntityID: okko, Changes: map[string]entity.FieldChange{
+			"service_name": {Before: json.RawMessage(`"Okko"`)},
+		}},
+	}
+
+	for i := range entries {
+		entries[i].EntityType = entity.AuditEntitySubscription
+		entries[i].OccurredAt = at.Add(time.Duration(i) * time.Minute)
+
+		if err := repo.AddAuditEntry(ctx, entries[i]); err != nil {
+			t.Fatalf("AddAuditEntry() error = %v", err)
+		}
+	}
+
+	errRollback := errors.New("rollback")
+
+	err := repo.WithTx(ctx, func(ctx context.Context) error {
+		if err := repo.AddAuditEntry(ctx, entries[0]); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithTx() error = %v, want %v", err, errRollback)
+	}
+
+	all, err := repo.AuditEntries(ctx, entity.AuditFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("AuditEntries() error = %v", err)
+	}
+
+	if len(all) != len(entries) {
+		t.Fatalf("AuditEntries() returned %d entries, want %d", len(all), len(entries))
+	}
+
+	for i, got := range all {
+		want := entries[len(entries)-1-i]
+		want.ID = got.ID
+
+		if i > 0 && got.ID >= all[i-1].ID {
+			t.Fatalf("entry %d has id %d after %d", i, got.ID, all[i-1].ID)
+		}
+
+		if !got.OccurredAt.Equal(want.OccurredAt) {
+			t.Fatalf("entry %d occurred at %v, want %v", i, got.OccurredAt, want.OccurredAt)
+		}
+
+		got.OccurredAt = want.OccurredAt
+		if !equalAuditEntries(got, want) {
+			t.Fatalf("entry %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter entity.AuditFilter
+		want   []int64
+	}{
+		{"entity", entity.AuditFilter{EntityType: entity.AuditEntitySubscription, EntityID: &okko}, []int64{all[0].ID, all[1].ID, all[3].ID}},
+		{"actor", entity.AuditFilter{Actor: "alice"}, []int64{all[1].ID, all[3].ID}},
+		{"request", entity.AuditFilter{RequestID: "r2"}, []int64{all[2].ID}},
+		{"action", entity.AuditFilter{Action: entity.AuditCreate}, []int64{all[3].ID}},
+		{"restore", entity.AuditFilter{Action: entity.AuditRestore}, []int64{all[2].ID}},
+		{"period", entity.AuditFilter{From: ptr(at.Add(time.Minute)), To: ptr(at.Add(3 * time.Minute))}, []int64{all[1].ID, all[2].ID}},
+		{"before", entity.AuditFilter{Before: &all[1].ID}, []int64{all[2].ID, all[3].ID}},
+		{"limit", entity.AuditFilter{EntityID: &okko, Limit: 2}, []int64{all[0].ID, all[1].ID}},
+		{"other entity type", entity.AuditFilter{EntityType: "budget"}, nil},
+	}
+
+	for _, tt := range tests {
+		if tt.filter.Limit == 0 {
+			tt.filter.Limit = 10
+		}
+
+		got, err := repo.AuditEntries(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("AuditEntries(%s) error = %v", tt.name, err)
+		}
+
+		var ids []int64
+		for _, e := range got {
+			ids = append(ids, e.ID)
+		}
+
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Fatalf("AuditEntries(%s) = %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+}
+
+func subscription(userID uuid.UUID, serviceName, start, end string) entity.Subscription {
+	sub := entity.Subscription{
+		ServiceName:   serviceName,
+		Price:         entity.NewMoney(39900, "RUB"),
+		BillingPeriod: entity.Monthly,
+		UserID:        userID,
+		StartDate:     *day(start),
+		Status:        entity.StatusActive,
+		Category:      entity.CategoryOther,
+		// Every subscription starts at the first version.
+		Version: 1,
+	}
+
+	if end != "" {
+		sub.EndDate = day(end)
+	}
+
+	return sub
+}
+
+func listParams(userID uuid.UUID, sortBy entity.SubscriptionSort, desc bool) entity.SubscriptionsListParams {
+	return entity.SubscriptionsListParams{
+		UserID: userID,
+		Sort:   sortBy,
+		Desc:   desc,
+		Limit:  entity.MaxPageLimit,
+	}
+}
+
+// create stores the subscription, a subscription without ServiceID gets the catalog entry named after its service.
+func create(t *testing.T, repo Repo, sub entity.Subscription) uuid.UUID {
+	t.Helper()
+
+	if sub.ServiceID.IsNil() {
+		sub.ServiceID = serviceID(t, repo, sub.ServiceName)
+	}
+
+	id, err := repo.CreateSubscription(context.Background(), sub)
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+
+	return id
+}
+
+// serviceID returns the ID of the catalog entry with the name, adding the entry on first use.
+func serviceID(t *testing.T, repo Repo, name string) uuid.UUID {
+	t.Helper()
+
+	ctx := context.Background()
+
+	e, err := repo.CatalogEntryByName(ctx, name)
+	if err == nil {
+		return e.ID
+	}
+
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Fatalf("CatalogEntryByName() error = %v", err)
+	}
+
+	id, err := repo.CreateCatalogEntry(ctx, entity.CatalogEntry{Name: name})
+	if err != nil {
+		t.Fatalf("CreateCatalogEntry() error = %v", err)
+	}
+
+	return id
+}
+
+// purge deletes the subscription and empties the trash, which removes it for good.
+func purge(t *testing.T, repo Repo, id uuid.UUID) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	sub, err := repo.SubscriptionByID(ctx, id)
+	if err != nil {
+		t.Fatalf("SubscriptionByID() error = %v", err)
+	}
+
+	if err := repo.DeleteSubscription(ctx, id, sub.Version, time.Now()); err != nil {
+		t.Fatalf("DeleteSubscription() error = %v", err)
+	}
+
+	if _, err := repo.PurgeSubscriptions(ctx, time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("PurgeSubscriptions() error = %v", err)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func day(s string) *time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return &t
+}
+
+func assertEqual(t *testing.T, got, want entity.Subscription) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("subscription = %+v, want %+v", got, want)
+	}
+}
+
+func assertIDs(t *testing.T, subs []entity.Subscription, ids ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, 0, len(subs))
+	for _, s := range subs {
+		got = append(got, s.ID)
+	}
+
+	if !reflect.DeepEqual(got, ids) {
+		t.Fatalf("subscription ids = %v, want %v", got, ids)
+	}
+}
+
+func assertHistory(t *testing.T, history []entity.StatusChange, want ...entity.StatusChange) {
+	t.Helper()
+
+	if len(history) == 0 && len(want) == 0 {
+		return
+	}
+
+	for i := range history {
+		history[i].ChangedAt = history[i].ChangedAt.UTC()
+	}
+
+	if !reflect.DeepEqual(history, want) {
+		t.Fatalf("status history = %+v, want %+v", history, want)
+	}
+}
+
+// equalDeliveries compares delivery logs, times compare as instants whatever their location.
+func equalDeliveries(got, want []entity.Delivery) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for i := range got {
+		g, w := got[i], want[i]
+		if !g.NextAttemptAt.Equal(w.NextAttemptAt) || !g.CreatedAt.Equal(w.CreatedAt) || !g.UpdatedAt.Equal(w.UpdatedAt) {
+			return false
+		}
+
+		g.NextAttemptAt, g.CreatedAt, g.UpdatedAt = w.NextAttemptAt, w.CreatedAt, w.UpdatedAt
+		if !reflect.DeepEqual(g, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalWebhooks(got, want []entity.Webhook) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for i := range got {
+		g, w := got[i], want[i]
+		if !g.CreatedAt.Equal(w.CreatedAt) || !equalTimes(g.DisabledAt, w.DisabledAt) {
+			return false
+		}
+
+		g.CreatedAt, g.DisabledAt = w.CreatedAt, w.DisabledAt
+		if !reflect.DeepEqual(g, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalWebhookDeliveries compares payloads as JSON, Postgres doesn't keep their formatting.
+func equalWebhookDeliveries(got, want []entity.WebhookDelivery) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for i := range got {
+		g, w := got[i], want[i]
+		if !g.NextAttemptAt.Equal(w.NextAttemptAt) || !g.CreatedAt.Equal(w.CreatedAt) || !g.UpdatedAt.Equal(w.UpdatedAt) {
+			return false
+		}
+
+		var gotPayload, wantPayload any
+		if json.Unmarshal(g.Payload, &gotPayload) != nil || json.Unmarshal(w.Payload, &wantPayload) != nil ||
+			!reflect.DeepEqual(gotPayload, wantPayload) {
+			return false
+		}
+
+		g.NextAttemptAt, g.CreatedAt, g.UpdatedAt, g.Payload = w.NextAttemptAt, w.CreatedAt, w.UpdatedAt, w.Payload
+		if !reflect.DeepEqual(g, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalAuditEntries compares the changes as JSON, Postgres doesn't keep their formatting.
+func equalAuditEntries(got, want entity.AuditEntry) bool {
+	gotChanges, err := json.Marshal(got.Changes)
+	if err != nil {
+		return false
+	}
+
+	wantChanges, err := json.Marshal(want.Changes)
+	if err != nil {
+		return false
+	}
+
+	var g, w any
+	if json.Unmarshal(gotChanges, &g) != nil || json.Unmarshal(wantChanges, &w) != nil || !reflect.DeepEqual(g, w) {
+		return false
+	}
+
+	got.Changes, want.Changes = nil, nil
+
+	return reflect.DeepEqual(got, want)
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+func assertEntry(t *testing.T, got, want entity.CatalogEntry) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("catalog entry = %+v, want %+v", got, want)
+	}
+}
+
+func assertPrices(t *testing.T, history []entity.PricePeriod, want ...entity.PricePeriod) {
+	t.Helper()
+
+	if !reflect.DeepEqual(history, want) {
+		t.Fatalf("price history = %+v, want %+v", history, want)
+	}
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func (r *SubscriptionRepo) AddAuditEntry(ctx context.Context, e entity.AuditEntry) error {
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return fmt.Errorf("repository: AddAuditEntry: %w", err)
+	}
+
+	query := `
+	INSERT INTO audit_log (occurred_at, actor, request_id, action, entity_type, entity_id, changes)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.conn(ctx).ExecContext(ctx, query, deliveryTime(e.OccurredAt), e.Actor, e.RequestID, e.Action, e.EntityType,
+		e.EntityID, string(changes))
+	if err != nil {
+		return fmt.Errorf("repository: AddAuditEntry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) AuditEntries(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	query := sq.Select("id, occurred_at, actor, request_id, action, entity_type, entity_id, changes").
+		From("audit_log").
+		Where(auditConditions(filter)).
+		OrderBy("id DESC").
+		Limit(uint64(filter.Limit))
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("repository: AuditEntries: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: AuditEntries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []entity.AuditEntry{}
+	for rows.Next() {
+		var (
+			e                   entity.AuditEntry
+			occurredAt, changes string
+		)
+
+		err := rows.Scan(&e.ID, &occurredAt, &e.Actor, &e.RequestID, &e.Action, &e.EntityType, &e.EntityID, &changes)
+		if err != nil {
+			return nil, fmt.Errorf("repository: AuditEntries: %w", err)
+		}
+
+		if e.OccurredAt, err = time.Parse(deliveryTimeLayout, occurredAt); err != nil {
+			return nil, fmt.Errorf("repository: AuditEntries: parse occurred_at: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, fmt.Errorf("repository: AuditEntries: entry %d: %w", e.ID, err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: AuditEntries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func auditConditions(filter entity.AuditFilter) sq.And {
+	conds := sq.And{}
+
+	if filter.EntityType != "" {
+		conds = append(conds, sq.Eq{"entity_type": filter.EntityType})
+	}
+
+	if filter.EntityID != nil {
+		conds = append(conds, sq.Eq{"entity_id": *filter.EntityID})
+	}
+
+	if filter.Actor != "" {
+		conds = append(conds, sq.Eq{"actor": filter.Actor})
+	}
+
+	if filter.RequestID != "" {
+		conds = append(conds, sq.Eq{"request_id": filter.RequestID})
+	}
+
+	if filter.Action != "" {
+		conds = append(conds, sq.Eq{"action": filter.Action})
+	}
+
+	if filter.From != nil {
+		conds = append(conds, sq.GtOrEq{"occurred_at": deliveryTime(*filter.From)})
+	}
+
+	if filter.To != nil {
+		conds = append(conds, sq.Lt{"occurred_at": deliveryTime(*filter.To)})
+	}
+
+	if filter.Before != nil {
+		conds = append(conds, sq.Lt{"id": *filter.Before})
+	}
+
+	return conds
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const budgetColumns = "id, user_id, period, category, limit_minor, limit_currency"
+
+func (r *SubscriptionRepo) CreateBudget(ctx context.Context, b entity.Budget) (uuid.UUID, error) {
+	id := uuid.Must(uuid.NewV4())
+
+	query := `
+	INSERT INTO budgets (` + budgetColumns + `)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, id, b.UserID, b.Period, b.Category, b.Limit.Amount, b.Limit.Currency)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository: CreateBudget: %w", uniqueError(err))
+	}
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) UpdateBudget(ctx context.Context, b entity.Budget) error {
+	query := `
+	UPDATE budgets
+	SET period = ?, category = ?, limit_minor = ?, limit_currency = ?
+	WHERE id = ?
+	`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, b.Period, b.Category, b.Limit.Amount, b.Limit.Currency, b.ID)
+	if err != nil {
+		return fmt.Errorf("repository: UpdateBudget: %w", uniqueError(err))
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("repository: UpdateBudget: %w", err)
+	} else if n == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	res, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM budgets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("repository: DeleteBudget: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("repository: DeleteBudget: %w", err)
+	} else if n == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) BudgetByID(ctx context.Context, id uuid.UUID) (entity.Budget, error) {
+	budgets, err := r.budgets(ctx, sq.Eq{"id": id})
+	if err != nil {
+		return entity.Budget{}, fmt.Errorf("repository: BudgetByID: %w", err)
+	}
+
+	if len(budgets) == 0 {
+		return entity.Budget{}, entity.ErrNotFound
+	}
+
+	return budgets[0], nil
+}
+
+func (r *SubscriptionRepo) Budgets(ctx context.Context, userID uuid.UUID) ([]entity.Budget, error) {
+	budgets, err := r.budgets(ctx, sq.Eq{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("repository: Budgets: %w", err)
+	}
+
+	return budgets, nil
+}
+
+func (r *SubscriptionRepo) budgets(ctx context.Context, where sq.Sqlizer) ([]entity.Budget, error) {
+	sqlQuery, args, err := sq.Select(budgetColumns).
+		From("budgets").
+		Where(where).
+		OrderBy("period", "category").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := []entity.Budget{}
+	for rows.Next() {
+		var b entity.Budget
+
+		if err := rows.Scan(&b.ID, &b.UserID, &b.Period, &b.Category, &b.Limit.Amount, &b.Limit.Currency); err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
+		}
+
+		budgets = append(budgets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err() %w", err)
+	}
+
+	return budgets, nil
+}
+
+// uniqueError reports a unique constraint violation as entity.ErrAlreadyExists.
+func uniqueError(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		return entity.ErrAlreadyExists
+	}
+
+	return err
+}
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) SetCalendarToken(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	query := `
+	INSERT INTO calendar_feeds (user_id, token_hash)
+	VALUES (?, ?)
+	ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = current_timestamp
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, userID, tokenHash); err != nil {
+		return fmt.Errorf("repository: SetCalendarToken: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DeleteCalendarToken(ctx context.Context, userID uuid.UUID) error {
+	if _, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM calendar_feeds WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("repository: DeleteCalendarToken: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) CalendarUser(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+
+	err := r.conn(ctx).QueryRowContext(ctx, `SELECT user_id FROM calendar_feeds WHERE token_hash = ?`, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, entity.ErrNotFound
+		}
+
+		return uuid.Nil, fmt.Errorf("repository: CalendarUser: %w", err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,261 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const catalogColumns = "id, name, category, homepage, default_price_minor, default_currency"
+
+func (r *SubscriptionRepo) CreateCatalogEntry(ctx context.Context, e entity.CatalogEntry) (uuid.UUID, error) {
+	id := uuid.Must(uuid.NewV4())
+
+	err := r.WithTx(ctx, func(ctx context.Context) error {
+		query := `
+		INSERT INTO services (id, name, category, homepage, default_price_minor, default_currency)
+		VALUES (?, ?, ?, ?, ?, ?)
+		`
+
+		amount, currency := defaultPrice(e)
+		if _, err := r.conn(ctx).ExecContext(ctx, query, id, e.Name, e.Category, e.Homepage, amount, currency); err != nil {
+			return err
+		}
+
+		return r.insertServiceNames(ctx, id, e)
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository: CreateCatalogEntry: %w", catalogError(err))
+	}
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) UpdateCatalogEntry(ctx context.Context, e entity.CatalogEntry) error {
+	err := r.WithTx(ctx, func(ctx context.Context) error {
+		query := `
+		UPDATE services
+		SET name = ?, category = ?, homepage = ?, default_price_minor = ?, default_currency = ?
+		WHERE id = ?
+		`
+
+		amount, currency := defaultPrice(e)
+		res, err := r.conn(ctx).ExecContext(ctx, query, e.Name, e.Category, e.Homepage, amount, currency, e.ID)
+		if err != nil {
+			return err
+		}
+
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return entity.ErrNotFound
+		}
+
+		if _, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM service_names WHERE service_id = ?`, e.ID); err != nil {
+			return err
+		}
+
+		if err := r.insertServiceNames(ctx, e.ID, e); err != nil {
+			return err
+		}
+
+		_, err = r.conn(ctx).ExecContext(ctx, `UPDATE subscriptions SET service_name = ?, version = version + 1 WHERE service_id = ? AND service_name <> ?`, e.Name, e.ID, e.Name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("repository: UpdateCatalogEntry: %w", catalogError(err))
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DeleteCatalogEntry(ctx context.Context, id uuid.UUID) error {
+	err := r.WithTx(ctx, func(ctx context.Context) error {
+		var inUse bool
+		err := r.conn(ctx).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM subscriptions WHERE service_id = ?)`, id).Scan(&inUse)
+		if err != nil {
+			return err
+		}
+
+		if inUse {
+			return entity.ErrServiceInUse
+		}
+
+		_, err = r.conn(ctx).ExecContext(ctx, `DELETE FROM services WHERE id = ?`, id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("repository: DeleteCatalogEntry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) CatalogEntryByID(ctx context.Context, id uuid.UUID) (entity.CatalogEntry, error) {
+	entries, err := r.catalogEntries(ctx, sq.Eq{"id": id})
+	if err != nil {
+		return entity.CatalogEntry{}, fmt.Errorf("repository: CatalogEntryByID: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return entity.CatalogEntry{}, entity.ErrNotFound
+	}
+
+	return entries[0], nil
+}
+
+func (r *SubscriptionRepo) CatalogEntryByName(ctx context.Context, name string) (entity.CatalogEntry, error) {
+	var id uuid.UUID
+
+	err := r.conn(ctx).QueryRowContext(ctx, `SELECT service_id FROM service_names WHERE name_key = ?`, entity.NameKey(name)).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.CatalogEntry{}, entity.ErrNotFound
+		}
+
+		return entity.CatalogEntry{}, fmt.Errorf("repository: CatalogEntryByName: %w", err)
+	}
+
+	return r.CatalogEntryByID(ctx, id)
+}
+
+func (r *SubscriptionRepo) CatalogEntries(ctx context.Context) ([]entity.CatalogEntry, error) {
+	entries, err := r.catalogEntries(ctx, sq.And{})
+	if err != nil {
+		return nil, fmt.Errorf("repository: CatalogEntries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// catalogEntries selects the services matching where together with their aliases.
+func (r *SubscriptionRepo) catalogEntries(ctx context.Context, where sq.Sqlizer) ([]entity.CatalogEntry, error) {
+	sqlQuery, args, err := sq.Select(catalogColumns).
+		From("services").
+		Where(where).
+		OrderBy("name", "id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		entries []entity.CatalogEntry
+		ids     []uuid.UUID
+	)
+
+	for rows.Next() {
+		var (
+			e        entity.CatalogEntry
+			amount   sql.NullInt64
+			currency sql.NullString
+		)
+
+		if err := rows.Scan(&e.ID, &e.Name, &e.Category, &e.Homepage, &amount, &currency); err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
+		}
+
+		if amount.Valid && currency.Valid {
+			e.DefaultPrice = &entity.Money{Amount: amount.Int64, Currency: currency.String}
+		}
+
+		e.Aliases = []string{}
+		entries = append(entries, e)
+		ids = append(ids, e.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err() %w", err)
+	}
+
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	aliasQuery, args, err := sq.Select("service_id, name").
+		From("service_names").
+		Where(sq.Eq{"service_id": ids}).
+		Where(sq.Gt{"position": 0}).
+		OrderBy("service_id", "position").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	aliasRows, err := r.conn(ctx).QueryContext(ctx, aliasQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer aliasRows.Close()
+
+	aliases := make(map[uuid.UUID][]string)
+	for aliasRows.Next() {
+		var (
+			id    uuid.UUID
+			alias string
+		)
+
+		if err := aliasRows.Scan(&id, &alias); err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
+		}
+
+		aliases[id] = append(aliases[id], alias)
+	}
+
+	if err := aliasRows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err() %w", err)
+	}
+
+	for i := range entries {
+		if a, ok := aliases[entries[i].ID]; ok {
+			entries[i].Aliases = a
+		}
+	}
+
+	return entries, nil
+}
+
+// insertServiceNames stores the canonical name and the aliases of the service under their keys.
+func (r *SubscriptionRepo) insertServiceNames(ctx context.Context, id uuid.UUID, e entity.CatalogEntry) error {
+	query := `INSERT INTO service_names (name_key, service_id, name, position) VALUES (?, ?, ?, ?)`
+
+	for position, name := range e.Names() {
+		if _, err := r.conn(ctx).ExecContext(ctx, query, entity.NameKey(name), id, name, position); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func defaultPrice(e entity.CatalogEntry) (sql.NullInt64, sql.NullString) {
+	if e.DefaultPrice == nil {
+		return sql.NullInt64{}, sql.NullString{}
+	}
+
+	return sql.NullInt64{Int64: e.DefaultPrice.Amount, Valid: true}, sql.NullString{String: e.DefaultPrice.Currency, Valid: true}
+}
+
+// catalogError turns a name clash into entity.ErrAlreadyExists.
+func catalogError(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE) {
+		return fmt.Errorf("%w: %s", entity.ErrAlreadyExists, sqliteErr.Error())
+	}
+
+	return err
+}
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+)
+
+func (r *SubscriptionRepo) EndedSubscriptions(ctx context.Context, before time.Time) ([]entity.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE status <> ?
+	AND end_date < ?
+	AND deleted_at IS NULL
+	ORDER BY end_date, id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, entity.StatusExpired, date(before))
+	if err != nil {
+		return nil, fmt.Errorf("repository: EndedSubscriptions: %w", err)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("repository: EndedSubscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
@@ -0,0 +1,195 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const deliveryColumns = "id, user_id, channel, address, event, subject, dedup_key, body, data, status, attempts, last_error, " +
+	"next_attempt_at, created_at, updated_at"
+
+// deliveryTimeLayout keeps every fraction digit, so the log sorts by time as text.
+const deliveryTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+func (r *SubscriptionRepo) NotificationPreferences(ctx context.Context, userID uuid.UUID) ([]entity.NotificationPreference, error) {
+	query := `
+	SELECT channel, address, events
+	FROM notification_preferences
+	WHERE user_id = ?
+	ORDER BY channel, address
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: NotificationPreferences: %w", err)
+	}
+	defer rows.Close()
+
+	prefs := []entity.NotificationPreference{}
+	for rows.Next() {
+		var (
+			p      entity.NotificationPreference
+			events string
+		)
+
+		if err := rows.Scan(&p.Channel, &p.Address, &events); err != nil {
+			return nil, fmt.Errorf("repository: NotificationPreferences: %w", err)
+		}
+
+		if events != "" {
+			for _, event := range strings.Split(events, ",") {
+				p.Events = append(p.Events, entity.NotificationEvent(event))
+			}
+		}
+
+		prefs = append(prefs, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: NotificationPreferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+func (r *SubscriptionRepo) SetNotificationPreferences(ctx context.Context, userID uuid.UUID, prefs []entity.NotificationPreference) error {
+	err := r.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM notification_preferences WHERE user_id = ?`, userID); err != nil {
+			return err
+		}
+
+		for _, p := range prefs {
+			events := make([]string, 0, len(p.Events))
+			for _, event := range p.Events {
+				events = append(events, string(event))
+			}
+
+			query := `INSERT INTO notification_preferences (user_id, channel, address, events) VALUES (?, ?, ?, ?)`
+			if _, err := r.conn(ctx).ExecContext(ctx, query, userID, p.Channel, p.Address, strings.Join(events, ",")); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("repository: SetNotificationPreferences: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) CreateDelivery(ctx context.Context, d entity.Delivery) (uuid.UUID, error) {
+	id := uuid.Must(uuid.NewV4())
+
+	query := `
+	INSERT INTO notification_deliveries (` + deliveryColumns + `)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, id, d.UserID, d.Channel, d.Address, d.Event, d.Subject, d.Key, d.Text, string(d.Data),
+		d.Status, d.Attempts, d.LastError, deliveryTime(d.NextAttemptAt), deliveryTime(d.CreatedAt), deliveryTime(d.UpdatedAt))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository: CreateDelivery: %w", uniqueError(err))
+	}
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) UpdateDelivery(ctx context.Context, d entity.Delivery) error {
+	query := `
+	UPDATE notification_deliveries
+	SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, updated_at = ?
+	WHERE id = ?
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, d.Status, d.Attempts, d.LastError, deliveryTime(d.NextAttemptAt), deliveryTime(d.UpdatedAt), d.ID)
+	if err != nil {
+		return fmt.Errorf("repository: UpdateDelivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) Deliveries(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Delivery, error) {
+	query := `
+	SELECT ` + deliveryColumns + `
+	FROM notification_deliveries
+	WHERE user_id = ?
+	ORDER BY created_at DESC, id
+	LIMIT ?
+	`
+
+	deliveries, err := r.deliveries(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: Deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *SubscriptionRepo) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.Delivery, error) {
+	query := `
+	SELECT ` + deliveryColumns + `
+	FROM notification_deliveries
+	WHERE status = ? AND next_attempt_at <= ?
+	ORDER BY next_attempt_at, created_at, id
+	LIMIT ?
+	`
+
+	deliveries, err := r.deliveries(ctx, query, entity.DeliveryPending, deliveryTime(now), limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: DueDeliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *SubscriptionRepo) deliveries(ctx context.Context, query string, args ...any) ([]entity.Delivery, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []entity.Delivery{}
+	for rows.Next() {
+		var (
+			d                                   entity.Delivery
+			data                                string
+			nextAttemptAt, createdAt, updatedAt string
+		)
+
+		err := rows.Scan(&d.ID, &d.UserID, &d.Channel, &d.Address, &d.Event, &d.Subject, &d.Key, &d.Text, &data,
+			&d.Status, &d.Attempts, &d.LastError, &nextAttemptAt, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
+		}
+
+		if data != "" {
+			d.Data = json.RawMessage(data)
+		}
+
+		if d.NextAttemptAt, err = time.Parse(deliveryTimeLayout, nextAttemptAt); err != nil {
+			return nil, fmt.Errorf("parse next_attempt_at: %w", err)
+		}
+
+		if d.CreatedAt, err = time.Parse(deliveryTimeLayout, createdAt); err != nil {
+			return nil, fmt.Errorf("parse created_at: %w", err)
+		}
+
+		if d.UpdatedAt, err = time.Parse(deliveryTimeLayout, updatedAt); err != nil {
+			return nil, fmt.Errorf("parse updated_at: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+)
+
+func (r *SubscriptionRepo) AddOutboxEvent(ctx context.Context, e entity.SubscriptionEvent) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("repository: AddOutboxEvent: %w", err)
+	}
+
+	query := `
+	INSERT INTO outbox_events (id, event_type, subscription_id, payload, created_at, next_attempt_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	at := nullTime(&e.OccurredAt)
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, e.ID, e.Type, e.SubscriptionID, string(payload), at, at); err != nil {
+		return fmt.Errorf("repository: AddOutboxEvent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) PendingOutboxEvents(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEvent, error) {
+	query := `
+	SELECT seq, payload, attempts, last_error, next_attempt_at
+	FROM outbox_events
+	WHERE published_at IS NULL
+		AND subscription_id NOT IN (
+			SELECT subscription_id
+			FROM outbox_events
+			WHERE published_at IS NULL AND next_attempt_at > ?
+		)
+	ORDER BY seq
+	LIMIT ?
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, nullTime(&now), limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: PendingOutboxEvents: %w", err)
+	}
+	defer rows.Close()
+
+	var events []entity.OutboxEvent
+	for rows.Next() {
+		var (
+			e             entity.OutboxEvent
+			payload       string
+			nextAttemptAt sql.NullString
+		)
+
+		if err := rows.Scan(&e.Seq, &payload, &e.Attempts, &e.LastError, &nextAttemptAt); err != nil {
+			return nil, fmt.Errorf("repository: PendingOutboxEvents: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(payload), &e.Event); err != nil {
+			return nil, fmt.Errorf("repository: PendingOutboxEvents: event %d: %w", e.Seq, err)
+		}
+
+		at, err := parseNullTime(nextAttemptAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository: PendingOutboxEvents: %w", err)
+		}
+
+		e.NextAttemptAt = *at
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: PendingOutboxEvents: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *SubscriptionRepo) MarkOutboxEventPublished(ctx context.Context, seq int64, at time.Time) error {
+	if _, err := r.conn(ctx).ExecContext(ctx, `UPDATE outbox_events SET published_at = ? WHERE seq = ?`, nullTime(&at), seq); err != nil {
+		return fmt.Errorf("repository: MarkOutboxEventPublished: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) MarkOutboxEventFailed(ctx context.Context, seq int64, lastError string, retryAt time.Time) error {
+	query := `
+	UPDATE outbox_events
+	SET attempts = attempts + 1, last_error = ?, next_attempt_at = ?
+	WHERE seq = ?
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, lastError, nullTime(&retryAt), seq); err != nil {
+		return fmt.Errorf("repository: MarkOutboxEventFailed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) PurgeOutboxEvents(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM outbox_events WHERE published_at < ?`, nullTime(&before))
+	if err != nil {
+		return 0, fmt.Errorf("repository: PurgeOutboxEvents: %w", err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("repository: PurgeOutboxEvents: %w", err)
+	}
+
+	return int(purged), nil
+}
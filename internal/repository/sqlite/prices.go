@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) SetPrice(ctx context.Context, period entity.PricePeriod) error {
+	query := `
+	INSERT INTO subscription_prices (subscription_id, price_minor, currency, effective_from)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT (subscription_id, effective_from)
+	DO UPDATE SET price_minor = excluded.price_minor, currency = excluded.currency
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, period.SubscriptionID, period.Price.Amount, period.Price.Currency,
+		date(period.EffectiveFrom))
+	if err != nil {
+		return fmt.Errorf("repository: SetPrice: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) PriceHistory(ctx context.Context, ids []uuid.UUID) ([]entity.PricePeriod, error) {
+	sqlQuery, args, err := sq.Select("subscription_id, price_minor, currency, effective_from").
+		From("subscription_prices").
+		Where(sq.Eq{"subscription_id": ids}).
+		OrderBy("subscription_id", "effective_from").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("repository: PriceHistory: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: PriceHistory: %w", err)
+	}
+	defer rows.Close()
+
+	var history []entity.PricePeriod
+	for rows.Next() {
+		var (
+			period        entity.PricePeriod
+			effectiveFrom string
+		)
+
+		if err := rows.Scan(&period.SubscriptionID, &period.Price.Amount, &period.Price.Currency, &effectiveFrom); err != nil {
+			return nil, fmt.Errorf("repository: PriceHistory: %w", err)
+		}
+
+		if period.EffectiveFrom, err = time.Parse(time.DateOnly, effectiveFrom); err != nil {
+			return nil, fmt.Errorf("repository: PriceHistory: parse effective_from: %w", err)
+		}
+
+		history = append(history, period)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: PriceHistory: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,459 @@
+// Package sqlite keeps subscriptions in an embedded SQLite database for small self-hosted installs.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+)
+
+// Dates are stored as YYYY-MM-DD text, which compares in calendar order.
+const subscriptionColumns = "id, service_id, service_name, price_minor, currency, user_id, start_date, end_date, billing_unit, billing_count, status, " +
+	"trial_start, trial_end, trial_ending_flagged_at, category, deleted_at, version"
+
+// notDeleted keeps the subscriptions in the trash out of a query.
+var notDeleted = sq.Eq{"deleted_at": nil}
+
+type SubscriptionRepo struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepo(db *sql.DB) *SubscriptionRepo {
+	return &SubscriptionRepo{db: db}
+}
+
+func (r *SubscriptionRepo) CreateSubscription(ctx context.Context, s entity.Subscription) (uuid.UUID, error) {
+
+	id := uuid.Must(uuid.NewV4())
+
+	query := `
+	INSERT INTO subscriptions (id, service_id, service_name, price_minor, currency, user_id, start_date, end_date, billing_unit, billing_count, status,
+		trial_start, trial_end, trial_ending_flagged_at, category)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, id, s.ServiceID, s.ServiceName, s.Price.Amount, s.Price.Currency, s.UserID,
+		date(s.StartDate), nullDate(s.EndDate), s.BillingPeriod.Unit, s.BillingPeriod.Count, s.Status,
+		nullDate(s.TrialStart), nullDate(s.TrialEnd), nullTime(s.TrialEndingFlaggedAt), s.Category)
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository: create subscription: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) UpdateSubscription(ctx context.Context, s entity.Subscription) error {
+
+	query := `
+	UPDATE subscriptions
+	SET
+	service_id = ?,
+	service_name = ?,
+	price_minor = ?,
+	currency = ?,
+	user_id = ?,
+	start_date = ?,
+	end_date = ?,
+	billing_unit = ?,
+	billing_count = ?,
+	status = ?,
+	trial_start = ?,
+	trial_end = ?,
+	trial_ending_flagged_at = ?,
+	category = ?,
+	version = version + 1
+	WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, s.ServiceID, s.ServiceName, s.Price.Amount, s.Price.Currency, s.UserID,
+		date(s.StartDate), nullDate(s.EndDate), s.BillingPeriod.Unit, s.BillingPeriod.Count, s.Status,
+		nullDate(s.TrialStart), nullDate(s.TrialEnd), nullTime(s.TrialEndingFlaggedAt), s.Category, s.ID, s.Version)
+
+	if err != nil {
+		return fmt.Errorf("repository: update subscription: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("repository: update subscription: %w", err)
+	} else if n == 0 {
+		return entity.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DeleteSubscription(ctx context.Context, id uuid.UUID, version int64, at time.Time) error {
+
+	query := `
+	UPDATE subscriptions
+	SET deleted_at = ?, version = version + 1
+	WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, at.UTC().Format(deliveryTimeLayout), id, version)
+
+	if err != nil {
+		return fmt.Errorf("repository: delete subscription: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("repository: delete subscription: %w", err)
+	} else if n == 0 {
+		return entity.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) SubscriptionByID(ctx context.Context, id uuid.UUID) (entity.Subscription, error) {
+
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE id = ? AND deleted_at IS NULL
+	`
+
+	subscription, err := scanSubscription(r.conn(ctx).QueryRowContext(ctx, query, id))
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Subscription{}, entity.ErrNotFound
+		}
+
+		return entity.Subscription{}, fmt.Errorf("repository: SubscriptionByID: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// SubscriptionForUpdate needs no row lock, the database has a single connection and
+// a transaction keeps it until it ends.
+func (r *SubscriptionRepo) SubscriptionForUpdate(ctx context.Context, id uuid.UUID) (entity.Subscription, error) {
+	return r.SubscriptionByID(ctx, id)
+}
+
+// sortColumns maps list sorts to the columns they order by.
+var sortColumns = map[entity.SubscriptionSort]string{
+	entity.SortStartDate:   "start_date",
+	entity.SortPrice:       "price_minor",
+	entity.SortServiceName: "service_name",
+}
+
+func (r *SubscriptionRepo) SubscriptionsList(ctx context.Context, params entity.SubscriptionsListParams) ([]entity.Subscription, error) {
+	where := sq.And{notDeleted, sq.Eq{"user_id": params.UserID}}
+
+	if params.ServiceName != "" {
+		where = append(where, sq.Eq{"service_name": params.ServiceName})
+	}
+
+	if params.ActiveOn != nil {
+		where = append(where,
+			sq.LtOrEq{"start_date": date(*params.ActiveOn)},
+			sq.Or{sq.Eq{"end_date": nil}, sq.GtOrEq{"end_date": date(*params.ActiveOn)}})
+	}
+
+	where = append(where, priceRange(params.Currency, params.PriceMin, params.PriceMax)...)
+
+	if params.HasEndDate != nil {
+		if *params.HasEndDate {
+			where = append(where, sq.NotEq{"end_date": nil})
+		} else {
+			where = append(where, sq.Eq{"end_date": nil})
+		}
+	}
+
+	if params.TrialOn != nil {
+		where = append(where,
+			sq.LtOrEq{"coalesce(trial_start, start_date)": date(*params.TrialOn)},
+			sq.GtOrEq{"trial_end": date(*params.TrialOn)})
+	}
+
+	if params.Category != "" {
+		where = append(where, sq.Eq{"category": params.Category})
+	}
+
+	for _, tag := range params.Tags {
+		where = append(where, sq.Expr(
+			"id IN (SELECT st.subscription_id FROM subscription_tags st JOIN tags t ON t.id = st.tag_id WHERE t.name = ?)", tag))
+	}
+
+	subscriptions, err := r.page(ctx, where, params.Sort, params.Desc, params.Cursor, params.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: SubscriptionList: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *SubscriptionRepo) SearchSubscriptions(ctx context.Context, filter entity.SubscriptionFilter) ([]entity.Subscription, int, error) {
+	where := sq.And{notDeleted}
+
+	if filter.ServiceName != "" {
+		where = append(where, sq.Expr("instr(unicode_lower(service_name), ?) > 0", strings.ToLower(filter.ServiceName)))
+	}
+
+	if len(filter.UserIDs) > 0 {
+		where = append(where, sq.Eq{"user_id": filter.UserIDs})
+	}
+
+	if filter.ActiveTo != nil {
+		where = append(where, sq.LtOrEq{"start_date": date(*filter.ActiveTo)})
+	}
+
+	if filter.ActiveFrom != nil {
+		where = append(where, sq.Or{sq.Eq{"end_date": nil}, sq.GtOrEq{"end_date": date(*filter.ActiveFrom)}})
+	}
+
+	where = append(where, priceRange(filter.Currency, filter.PriceMin, filter.PriceMax)...)
+
+	countQuery, args, err := sq.Select("count(*)").
+		From("subscriptions").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository: SearchSubscriptions: %w", err)
+	}
+
+	var total int
+	if err := r.conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("repository: SearchSubscriptions: %w", err)
+	}
+
+	subscriptions, err := r.page(ctx, where, filter.Sort, filter.Desc, filter.Cursor, filter.Limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository: SearchSubscriptions: %w", err)
+	}
+
+	return subscriptions, total, nil
+}
+
+// page selects up to limit subscriptions matching where in the sort order, starting right after cursor.
+func (r *SubscriptionRepo) page(ctx context.Context, where sq.And, sortBy entity.SubscriptionSort, desc bool, cursor *entity.ListCursor, limit int) ([]entity.Subscription, error) {
+	column := sortColumns[sortBy]
+
+	direction, after := "ASC", ">"
+	if desc {
+		direction, after = "DESC", "<"
+	}
+
+	if cursor != nil {
+		var value any = cursor.Value
+		if sortBy == entity.SortPrice {
+			value, _ = cursor.PriceValue()
+		}
+
+		where = append(where, sq.Or{
+			sq.Expr(column+" "+after+" ?", value),
+			sq.And{sq.Eq{column: value}, sq.Expr("id "+after+" ?", cursor.ID)},
+		})
+	}
+
+	sqlQuery, args, err := sq.Select(subscriptionColumns).
+		From("subscriptions").
+		Where(where).
+		OrderBy(column+" "+direction, "id "+direction).
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSubscriptions(rows)
+}
+
+func priceRange(currency string, priceMin, priceMax *int64) []sq.Sqlizer {
+	var where []sq.Sqlizer
+
+	if currency != "" {
+		where = append(where, sq.Eq{"currency": currency})
+	}
+
+	if priceMin != nil {
+		where = append(where, sq.GtOrEq{"price_minor": *priceMin})
+	}
+
+	if priceMax != nil {
+		where = append(where, sq.LtOrEq{"price_minor": *priceMax})
+	}
+
+	return where
+}
+
+func (r *SubscriptionRepo) SubscriptionsInPeriod(ctx context.Context, params entity.SubscriptionsSumParams) ([]entity.Subscription, error) {
+	query := sq.Select(subscriptionColumns).
+		From("subscriptions").
+		Where(notDeleted).
+		Where(sq.Eq{"user_id": params.UserID}).
+		Where(sq.Or{sq.Eq{"end_date": nil}, sq.GtOrEq{"end_date": date(params.StartDate)}}).
+		OrderBy("start_date", "id")
+
+	if !params.ServiceID.IsNil() {
+		query = query.Where(sq.Eq{"service_id": params.ServiceID})
+	}
+
+	if params.EndDate != nil {
+		query = query.Where(sq.LtOrEq{"start_date": date(*params.EndDate)})
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("repository: SubscriptionsInPeriod: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: SubscriptionsInPeriod: %w", err)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("repository: SubscriptionsInPeriod: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanSubscription reads one row selected with subscriptionColumns.
+func scanSubscription(row scanner) (entity.Subscription, error) {
+	var (
+		s                    entity.Subscription
+		startDate            string
+		endDate              sql.NullString
+		trialStart, trialEnd sql.NullString
+		trialEndingFlaggedAt sql.NullString
+		deletedAt            sql.NullString
+	)
+
+	err := row.Scan(
+		&s.ID,
+		&s.ServiceID,
+		&s.ServiceName,
+		&s.Price.Amount,
+		&s.Price.Currency,
+		&s.UserID,
+		&startDate,
+		&endDate,
+		&s.BillingPeriod.Unit,
+		&s.BillingPeriod.Count,
+		&s.Status,
+		&trialStart,
+		&trialEnd,
+		&trialEndingFlaggedAt,
+		&s.Category,
+		&deletedAt,
+		&s.Version)
+	if err != nil {
+		return entity.Subscription{}, err
+	}
+
+	if s.StartDate, err = time.Parse(time.DateOnly, startDate); err != nil {
+		return entity.Subscription{}, fmt.Errorf("parse start_date: %w", err)
+	}
+
+	if s.EndDate, err = parseNullDate(endDate); err != nil {
+		return entity.Subscription{}, fmt.Errorf("parse end_date: %w", err)
+	}
+
+	if s.TrialStart, err = parseNullDate(trialStart); err != nil {
+		return entity.Subscription{}, fmt.Errorf("parse trial_start: %w", err)
+	}
+
+	if s.TrialEnd, err = parseNullDate(trialEnd); err != nil {
+		return entity.Subscription{}, fmt.Errorf("parse trial_end: %w", err)
+	}
+
+	if s.TrialEndingFlaggedAt, err = parseNullTime(trialEndingFlaggedAt); err != nil {
+		return entity.Subscription{}, fmt.Errorf("parse trial_ending_flagged_at: %w", err)
+	}
+
+	if s.DeletedAt, err = parseNullTime(deletedAt); err != nil {
+		return entity.Subscription{}, fmt.Errorf("parse deleted_at: %w", err)
+	}
+
+	return s, nil
+}
+
+// scanSubscriptions drains and closes rows selected with subscriptionColumns.
+func scanSubscriptions(rows *sql.Rows) ([]entity.Subscription, error) {
+	defer rows.Close()
+
+	var subscriptions []entity.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
+		}
+
+		subscriptions = append(subscriptions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err() %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func date(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
+
+func nullDate(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: date(*t), Valid: true}
+}
+
+func parseNullDate(s sql.NullString) (*time.Time, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, s.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// nullTime stores a moment as RFC 3339 text in UTC, which compares in time order.
+func nullTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: t.UTC().Format(time.RFC3339Nano), Valid: true}
+}
+
+func parseNullTime(s sql.NullString) (*time.Time, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,28 @@
+package sqlite_test
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/repository/repotest"
+	"online-subscribe-rest-service/internal/repository/sqlite"
+	pkgsqlite "online-subscribe-rest-service/pkg/sqlite"
+	"path/filepath"
+	"testing"
+)
+
+// TestConformance runs the suite against a fresh database file for every test.
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repo {
+		db, err := pkgsqlite.Open(context.Background(), filepath.Join(t.TempDir(), "subscriptions.db"))
+		if err != nil {
+			t.Fatalf("failed to open database: %v", err)
+		}
+
+		t.Cleanup(func() { db.Close() })
+
+		if err := pkgsqlite.UpMigrations(db); err != nil {
+			t.Fatalf("failed to up migrations: %v", err)
+		}
+
+		return sqlite.NewSubscriptionRepo(db)
+	})
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) AddStatusChange(ctx context.Context, change entity.StatusChange) error {
+	query := `
+	INSERT INTO subscription_status_history (subscription_id, from_status, to_status, effective_date, changed_at)
+	VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, change.SubscriptionID, change.From, change.To,
+		date(change.EffectiveDate), change.ChangedAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("repository: AddStatusChange: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) StatusHistory(ctx context.Context, ids []uuid.UUID) ([]entity.StatusChange, error) {
+	sqlQuery, args, err := sq.Select("subscription_id, from_status, to_status, effective_date, changed_at").
+		From("subscription_status_history").
+		Where(sq.Eq{"subscription_id": ids}).
+		OrderBy("subscription_id", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("repository: StatusHistory: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: StatusHistory: %w", err)
+	}
+	defer rows.Close()
+
+	var history []entity.StatusChange
+	for rows.Next() {
+		var (
+			change                   entity.StatusChange
+			effectiveDate, changedAt string
+		)
+
+		if err := rows.Scan(&change.SubscriptionID, &change.From, &change.To, &effectiveDate, &changedAt); err != nil {
+			return nil, fmt.Errorf("repository: StatusHistory: %w", err)
+		}
+
+		if change.EffectiveDate, err = time.Parse(time.DateOnly, effectiveDate); err != nil {
+			return nil, fmt.Errorf("repository: StatusHistory: parse effective_date: %w", err)
+		}
+
+		if change.ChangedAt, err = time.Parse(time.RFC3339Nano, changedAt); err != nil {
+			return nil, fmt.Errorf("repository: StatusHistory: parse changed_at: %w", err)
+		}
+
+		history = append(history, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: StatusHistory: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) SetTags(ctx context.Context, userID, subscriptionID uuid.UUID, tags []string) error {
+	err := r.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM subscription_tags WHERE subscription_id = ?`, subscriptionID); err != nil {
+			return err
+		}
+
+		for _, tag := range tags {
+			query := `
+			INSERT INTO tags (id, user_id, name)
+			VALUES (?, ?, ?)
+			ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+			`
+
+			var tagID uuid.UUID
+			if err := r.conn(ctx).QueryRowContext(ctx, query, uuid.Must(uuid.NewV4()), userID, tag).Scan(&tagID); err != nil {
+				return err
+			}
+
+			query = `INSERT INTO subscription_tags (subscription_id, tag_id) VALUES (?, ?)`
+			if _, err := r.conn(ctx).ExecContext(ctx, query, subscriptionID, tagID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("repository: SetTags: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) SubscriptionTags(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]string, error) {
+	sqlQuery, args, err := sq.Select("st.subscription_id, t.name").
+		From("subscription_tags st").
+		Join("tags t ON t.id = st.tag_id").
+		Where(sq.Eq{"st.subscription_id": ids}).
+		OrderBy("st.subscription_id", "t.name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("repository: SubscriptionTags: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: SubscriptionTags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make(map[uuid.UUID][]string)
+	for rows.Next() {
+		var (
+			id  uuid.UUID
+			tag string
+		)
+
+		if err := rows.Scan(&id, &tag); err != nil {
+			return nil, fmt.Errorf("repository: SubscriptionTags: %w", err)
+		}
+
+		tags[id] = append(tags[id], tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: SubscriptionTags: %w", err)
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) RestoreSubscription(ctx context.Context, id uuid.UUID) error {
+	res, err := r.conn(ctx).ExecContext(ctx, `UPDATE subscriptions SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("repository: RestoreSubscription: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("repository: RestoreSubscription: %w", err)
+	} else if n == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DeletedSubscriptions(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE user_id = ?
+	AND deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC, id
+	LIMIT ?
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: DeletedSubscriptions: %w", err)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("repository: DeletedSubscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *SubscriptionRepo) PurgeSubscriptions(ctx context.Context, before time.Time) ([]entity.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE deleted_at < ?
+	ORDER BY deleted_at, id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, before.UTC().Format(deliveryTimeLayout))
+	if err != nil {
+		return nil, fmt.Errorf("repository: PurgeSubscriptions: %w", err)
+	}
+
+	purged, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("repository: PurgeSubscriptions: %w", err)
+	}
+
+	if len(purged) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(purged))
+	for i, s := range purged {
+		ids[i] = s.ID
+	}
+
+	tags, err := r.SubscriptionTags(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range purged {
+		purged[i].Tags = tags[purged[i].ID]
+	}
+
+	sqlQuery, args, err := sq.Delete("subscriptions").Where(sq.Eq{"id": ids}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("repository: PurgeSubscriptions: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, sqlQuery, args...); err != nil {
+		return nil, fmt.Errorf("repository: PurgeSubscriptions: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+)
+
+func (r *SubscriptionRepo) EndingTrials(ctx context.Context, from, to time.Time) ([]entity.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE trial_ending_flagged_at IS NULL
+	AND status = ?
+	AND trial_end BETWEEN ? AND ?
+	AND deleted_at IS NULL
+	ORDER BY trial_end, id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, entity.StatusActive, date(from), date(to))
+	if err != nil {
+		return nil, fmt.Errorf("repository: EndingTrials: %w", err)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("repository: EndingTrials: %w", err)
+	}
+
+	return subscriptions, nil
+}
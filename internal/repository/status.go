@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) AddStatusChange(ctx context.Context, change entity.StatusChange) error {
+	query := `
+	INSERT INTO subscription_status_history (subscription_id, from_status, to_status, effective_date, changed_at)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, change.SubscriptionID, change.From, change.To, change.EffectiveDate, change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("repository: AddStatusChange: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) StatusHistory(ctx context.Context, ids []uuid.UUID) ([]entity.StatusChange, error) {
+	sqlQuery, args, err := sq.Select("subscription_id, from_status, to_status, effective_date, changed_at").
+		PlaceholderFormat(sq.Dollar).
+		From("subscription_status_history").
+		Where(sq.Eq{"subscription_id": ids}).
+		OrderBy("subscription_id", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("repository: StatusHistory: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: StatusHistory: %w", err)
+	}
+	defer rows.Close()
+
+	var history []entity.StatusChange
+	for rows.Next() {
+		var change entity.StatusChange
+		if err := rows.Scan(&change.SubscriptionID, &change.From, &change.To, &change.EffectiveDate, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("repository: StatusHistory: %w", err)
+		}
+
+		history = append(history, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: StatusHistory: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (r *SubscriptionRepo) RestoreSubscription(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.conn(ctx).Exec(ctx, `UPDATE subscriptions SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("repository: RestoreSubscription: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DeletedSubscriptions(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE user_id = $1
+	AND deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC, id
+	LIMIT $2
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: DeletedSubscriptions: %w", err)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("repository: DeletedSubscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// PurgeSubscriptions locks the rows it removes, in a transaction a concurrent restore waits for it.
+func (r *SubscriptionRepo) PurgeSubscriptions(ctx context.Context, before time.Time) ([]entity.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE deleted_at < $1
+	ORDER BY deleted_at, id
+	FOR UPDATE
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("repository: PurgeSubscriptions: %w", err)
+	}
+
+	purged, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("repository: PurgeSubscriptions: %w", err)
+	}
+
+	if len(purged) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(purged))
+	for i, s := range purged {
+		ids[i] = s.ID
+	}
+
+	tags, err := r.SubscriptionTags(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range purged {
+		purged[i].Tags = tags[purged[i].ID]
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, `DELETE FROM subscriptions WHERE id = ANY($1)`, ids); err != nil {
+		return nil, fmt.Errorf("repository: PurgeSubscriptions: %w", err)
+	}
+
+	return purged, nil
+}
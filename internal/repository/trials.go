@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+)
+
+func (r *SubscriptionRepo) EndingTrials(ctx context.Context, from, to time.Time) ([]entity.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE trial_ending_flagged_at IS NULL
+	AND status = $1
+	AND trial_end BETWEEN $2 AND $3
+	AND deleted_at IS NULL
+	ORDER BY trial_end, id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, entity.StatusActive, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("repository: EndingTrials: %w", err)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("repository: EndingTrials: %w", err)
+	}
+
+	return subscriptions, nil
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	webhookColumns         = "id, url, events, user_id, secret, enabled, failures, disabled_at, created_at"
+	webhookDeliveryColumns = "id, webhook_id, event_id, event_type, payload, status, attempts, response_status, last_error, " +
+		"next_attempt_at, created_at, updated_at"
+)
+
+func (r *SubscriptionRepo) CreateWebhook(ctx context.Context, w entity.Webhook) (uuid.UUID, error) {
+	id := uuid.Must(uuid.NewV4())
+
+	query := `
+	INSERT INTO webhooks (` + webhookColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, id, w.URL, eventTypes(w.Events), w.UserID, w.Secret, w.Enabled, w.Failures,
+		w.DisabledAt, w.CreatedAt)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository: CreateWebhook: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) WebhookByID(ctx context.Context, id uuid.UUID) (entity.Webhook, error) {
+	webhooks, err := r.webhooks(ctx, sq.Eq{"id": id})
+	if err != nil {
+		return entity.Webhook{}, fmt.Errorf("repository: WebhookByID: %w", err)
+	}
+
+	if len(webhooks) == 0 {
+		return entity.Webhook{}, entity.ErrNotFound
+	}
+
+	return webhooks[0], nil
+}
+
+func (r *SubscriptionRepo) Webhooks(ctx context.Context) ([]entity.Webhook, error) {
+	webhooks, err := r.webhooks(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("repository: Webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *SubscriptionRepo) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.conn(ctx).Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repository: DeleteWebhook: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) EnableWebhook(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.conn(ctx).Exec(ctx, `UPDATE webhooks SET enabled = true, failures = 0, disabled_at = NULL WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repository: EnableWebhook: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) WebhookSucceeded(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.conn(ctx).Exec(ctx, `UPDATE webhooks SET failures = 0 WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("repository: WebhookSucceeded: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) WebhookFailed(ctx context.Context, id uuid.UUID, disableAfter int, at time.Time) (bool, error) {
+	query := `
+	UPDATE webhooks
+	SET failures = failures + 1,
+		enabled = enabled AND failures + 1 < $1,
+		disabled_at = CASE WHEN enabled AND failures + 1 >= $1 THEN $2 ELSE disabled_at END
+	WHERE id = $3
+	RETURNING disabled_at = $2
+	`
+
+	var disabled *bool
+	if err := r.conn(ctx).QueryRow(ctx, query, disableAfter, at, id).Scan(&disabled); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, entity.ErrNotFound
+		}
+
+		return false, fmt.Errorf("repository: WebhookFailed: %w", err)
+	}
+
+	return disabled != nil && *disabled, nil
+}
+
+func (r *SubscriptionRepo) CreateWebhookDelivery(ctx context.Context, d entity.WebhookDelivery) (uuid.UUID, error) {
+	id := uuid.Must(uuid.NewV4())
+
+	query := `
+	INSERT INTO webhook_deliveries (` + webhookDeliveryColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, id, d.WebhookID, d.EventID, d.EventType, []byte(d.Payload), d.Status, d.Attempts,
+		d.ResponseStatus, d.LastError, d.NextAttemptAt, d.CreatedAt, d.UpdatedAt)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository: CreateWebhookDelivery: %w", uniqueError(err))
+	}
+
+	return id, nil
+}
+
+func (r *SubscriptionRepo) UpdateWebhookDelivery(ctx context.Context, d entity.WebhookDelivery) error {
+	query := `
+	UPDATE webhook_deliveries
+	SET status = $1, attempts = $2, response_status = $3, last_error = $4, next_attempt_at = $5, updated_at = $6
+	WHERE id = $7
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, d.Status, d.Attempts, d.ResponseStatus, d.LastError, d.NextAttemptAt, d.UpdatedAt, d.ID)
+	if err != nil {
+		return fmt.Errorf("repository: UpdateWebhookDelivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepo) DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	query := `
+	SELECT ` + webhookDeliveryColumns + `
+	FROM webhook_deliveries
+	WHERE status = $1 AND next_attempt_at <= $2
+		AND webhook_id IN (SELECT id FROM webhooks WHERE enabled)
+	ORDER BY next_attempt_at, created_at, id
+	LIMIT $3
+	`
+
+	deliveries, err := r.webhookDeliveries(ctx, query, entity.DeliveryPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: DueWebhookDeliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *SubscriptionRepo) WebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]entity.WebhookDelivery, error) {
+	query := `
+	SELECT ` + webhookDeliveryColumns + `
+	FROM webhook_deliveries
+	WHERE webhook_id = $1
+	ORDER BY created_at DESC, id
+	LIMIT $2
+	`
+
+	deliveries, err := r.webhookDeliveries(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: WebhookDeliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *SubscriptionRepo) webhooks(ctx context.Context, where sq.Sqlizer) ([]entity.Webhook, error) {
+	query := sq.Select(webhookColumns).PlaceholderFormat(sq.Dollar).
+		From("webhooks").
+		OrderBy("created_at", "id")
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []entity.Webhook{}
+	for rows.Next() {
+		var (
+			w      entity.Webhook
+			events []string
+		)
+
+		err := rows.Scan(&w.ID, &w.URL, &events, &w.UserID, &w.Secret, &w.Enabled, &w.Failures, &w.DisabledAt, &w.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
+		}
+
+		for _, event := range events {
+			w.Events = append(w.Events, entity.SubscriptionEventType(event))
+		}
+
+		webhooks = append(webhooks, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err() %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *SubscriptionRepo) webhookDeliveries(ctx context.Context, query string, args ...any) ([]entity.WebhookDelivery, error) {
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []entity.WebhookDelivery{}
+	for rows.Next() {
+		var (
+			d       entity.WebhookDelivery
+			payload []byte
+		)
+
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts, &d.ResponseStatus,
+			&d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan() %w", err)
+		}
+
+		d.Payload = payload
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err() %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func eventTypes(events []entity.SubscriptionEventType) []string {
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, string(event))
+	}
+
+	return types
+}
@@ -0,0 +1,226 @@
+// Package scheduler runs the background jobs of the service on cron schedules.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/audit"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/cron"
+	"online-subscribe-rest-service/pkg/logger"
+	"sync"
+	"time"
+)
+
+// Locker hands out locks shared by every replica of the service, see postgres.AdvisoryLocker.
+// Everything that takes a Locker takes nil too and locks nothing then, which suits a service
+// running as a single process.
+type Locker interface {
+	// TryLock takes the lock called name unless somebody else holds it, ok is false then.
+	// unlock releases a lock that was taken.
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// Job is a task run on a schedule.
+type Job struct {
+	Name string
+	// Schedule is a cron expression in UTC, see package cron.
+	Schedule string
+	// Exclusive jobs hold a lock named after the job while they run, so that of the replicas
+	// sharing the locker only one runs the job at a time. Nothing records which due time was
+	// served, so a replica coming to the same due time after the lock was released runs the job
+	// again.
+	Exclusive bool
+	// Run does the job and sums up what it did. The changes it makes are audited as made by job:<Name>.
+	// Since a due time may be served twice, Run must be idempotent: it works out what is left to do
+	// from the stored state, the way expiring, flagging trials, purging the trash or the outbox and deduplicated
+	// renewal notices do.
+	Run func(ctx context.Context) (string, error)
+}
+
+type Scheduler struct {
+	log    logger.Logger
+	locker Locker
+
+	mu   sync.Mutex
+	jobs []*scheduledJob
+}
+
+type scheduledJob struct {
+	Job
+	schedule cron.Schedule
+	// status is guarded by Scheduler.mu.
+	status entity.JobStatus
+}
+
+// New creates a scheduler, locker may be nil, see Locker.
+func New(log logger.Logger, locker Locker) *Scheduler {
+	return &Scheduler{log: log, locker: locker}
+}
+
+// Add registers the job, it fails when the job's schedule isn't a valid cron expression. Jobs must be
+// added before Run is called.
+func (s *Scheduler) Add(job Job) error {
+	schedule, err := cron.Parse(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("scheduler: job %s: %w", job.Name, err)
+	}
+
+	if schedule.Next(time.Now().UTC()).IsZero() {
+		return fmt.Errorf("scheduler: job %s: schedule %q never fires", job.Name, job.Schedule)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		if j.Name == job.Name {
+			return fmt.Errorf("scheduler: job %s is added twice", job.Name)
+		}
+	}
+
+	s.jobs = append(s.jobs, &scheduledJob{
+		Job:      job,
+		schedule: schedule,
+		status: entity.JobStatus{
+			Name:      job.Name,
+			Schedule:  job.Schedule,
+			Exclusive: job.Exclusive,
+		},
+	})
+
+	return nil
+}
+
+// Run runs every job whenever it is due until ctx is done, then waits for the running jobs to return.
+// A job never overlaps itself: when a run outlasts the next due time that time is skipped.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	jobs := s.jobs
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+
+	for _, j := range jobs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// Jobs returns the status of every job in the order they were added.
+func (s *Scheduler) Jobs() []entity.JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]entity.JobStatus, 0, len(s.jobs))
+
+	for _, j := range s.jobs {
+		status := j.status
+
+		if status.LastRun != nil {
+			run := *status.LastRun
+			status.LastRun = &run
+		}
+
+		if status.LastSuccess != nil {
+			at := *status.LastSuccess
+			status.LastSuccess = &at
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *scheduledJob) {
+	for {
+		next := j.schedule.Next(time.Now().UTC())
+
+		s.mu.Lock()
+		j.status.NextRun = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.run(ctx, j)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, j *scheduledJob) {
+	run := entity.JobRun{StartedAt: time.Now().UTC()}
+
+	s.mu.Lock()
+	j.status.Running = true
+	s.mu.Unlock()
+
+	result, err := s.runLocked(ctx, j)
+
+	run.FinishedAt = time.Now().UTC()
+
+	switch {
+	case errors.Is(err, errLocked):
+		run.Outcome = entity.JobSkipped
+
+		s.log.DebugF("scheduler: job %s is running on another replica", j.Name)
+	case err != nil:
+		run.Outcome = entity.JobFailed
+		run.Error = err.Error()
+
+		s.log.ErrorF("scheduler: job %s failed: %v", j.Name, err)
+	default:
+		run.Outcome = entity.JobSucceeded
+		run.Result = result
+
+		s.log.InfoW("job finished", map[string]any{
+			"job":      j.Name,
+			"result":   result,
+			"duration": run.FinishedAt.Sub(run.StartedAt).String(),
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.status.Running = false
+	j.status.LastRun = &run
+
+	if run.Outcome == entity.JobSucceeded {
+		j.status.LastSuccess = &run.FinishedAt
+	}
+}
+
+var errLocked = errors.New("job is locked")
+
+// runLocked runs the job, holding its lock if it is exclusive. It fails with errLocked when
+// somebody else holds the lock.
+func (s *Scheduler) runLocked(ctx context.Context, j *scheduledJob) (string, error) {
+	if j.Exclusive && s.locker != nil {
+		unlock, ok, err := s.locker.TryLock(ctx, "scheduler:"+j.Name)
+		if err != nil {
+			return "", fmt.Errorf("failed to take lock: %w", err)
+		}
+
+		if !ok {
+			return "", errLocked
+		}
+
+		defer unlock()
+	}
+
+	return j.Run(audit.WithActor(ctx, audit.JobPrefix+j.Name, ""))
+}
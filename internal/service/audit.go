@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// AuditLog returns a page of the audit entries matching the filter, newest first.
+func (s *Service) AuditLog(ctx context.Context, filter entity.AuditFilter) (entity.AuditPage, error) {
+	page, err := s.auditPage(ctx, filter)
+	if err != nil {
+		return entity.AuditPage{}, fmt.Errorf("failed to get audit log: %w", err)
+	}
+
+	return page, nil
+}
+
+// SubscriptionHistory returns a page of the subscription's changes, newest first. The history
+// outlives the subscription, it is missing only for a subscription that never existed.
+func (s *Service) SubscriptionHistory(ctx context.Context, id uuid.UUID, limit int, before *int64) (entity.AuditPage, error) {
+	page, err := s.auditPage(ctx, entity.AuditFilter{
+		EntityType: entity.AuditEntitySubscription,
+		EntityID:   &id,
+		Before:     before,
+		Limit:      limit,
+	})
+	if err != nil {
+		return entity.AuditPage{}, fmt.Errorf("failed to get history of subscription %s: %w", id, err)
+	}
+
+	if len(page.Entries) == 0 && before == nil {
+		// Subscriptions created before the audit log have no history yet.
+		if _, err := s.repo.SubscriptionByID(ctx, id); err != nil {
+			return entity.AuditPage{}, fmt.Errorf("failed to get history of subscription %s: %w", id, err)
+		}
+	}
+
+	return page, nil
+}
+
+// auditPage reads one entry past the page to tell whether there is a next one.
+func (s *Service) auditPage(ctx context.Context, filter entity.AuditFilter) (entity.AuditPage, error) {
+	limit := filter.Limit
+	filter.Limit++
+
+	entries, err := s.repo.AuditEntries(ctx, filter)
+	if err != nil {
+		return entity.AuditPage{}, err
+	}
+
+	page := entity.AuditPage{Entries: entries}
+
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextBefore = &page.Entries[limit-1].ID
+	}
+
+	return page, nil
+}
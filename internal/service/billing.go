@@ -0,0 +1,152 @@
+package service
+
+import (
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+)
+
+const monthLayout = "2006-01"
+
+// dateOf drops the clock part of t, keeping the calendar date in UTC.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// monthStart returns the first day of the month t belongs to.
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// addMonths shifts t by n months, clamping the day to the length of the resulting month,
+// so a subscription started on the 31st is billed on the 30th in April and on the 28th in February.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// chargeDate returns the date of the n-th charge of a subscription started on start,
+// the first charge (n = 0) happens on the start date itself.
+func chargeDate(start time.Time, period entity.BillingPeriod, n int) time.Time {
+	switch period.Unit {
+	case entity.BillingDay:
+		return start.AddDate(0, 0, n*period.Count)
+	case entity.BillingWeek:
+		return start.AddDate(0, 0, 7*n*period.Count)
+	case entity.BillingYear:
+		return addMonths(start, 12*n*period.Count)
+	default:
+		return addMonths(start, n*period.Count)
+	}
+}
+
+// billingPeriod returns the subscription's billing period, subscriptions stored before periods existed are monthly.
+func billingPeriod(sub entity.Subscription) entity.BillingPeriod {
+	if sub.BillingPeriod.IsZero() {
+		return entity.Monthly
+	}
+
+	return sub.BillingPeriod
+}
+
+// billingStart returns the day of the subscription's first charge: its start date,
+// or the day after the free trial ends.
+func billingStart(sub entity.Subscription) time.Time {
+	start := dateOf(sub.StartDate)
+	if sub.TrialEnd != nil {
+		if afterTrial := dateOf(*sub.TrialEnd).AddDate(0, 0, 1); afterTrial.After(start) {
+			return afterTrial
+		}
+	}
+
+	return start
+}
+
+// chargeDates returns the billing dates of sub that fall within [from, to].
+// A subscription is charged when billing starts and then once every billing period
+// for as long as it is active; the end date itself is still an active day.
+func chargeDates(sub entity.Subscription, from, to time.Time) []time.Time {
+	start := billingStart(sub)
+
+	last := dateOf(to)
+	if sub.EndDate != nil && sub.EndDate.Before(last) {
+		last = dateOf(*sub.EndDate)
+	}
+
+	from = dateOf(from)
+
+	period := billingPeriod(sub)
+
+	var dates []time.Time
+	for k := 0; ; k++ {
+		date := chargeDate(start, period, k)
+		if date.After(last) {
+			break
+		}
+
+		if !date.Before(from) {
+			dates = append(dates, date)
+		}
+	}
+
+	return dates
+}
+
+// monthsBetween counts the calendar months from the month of from to the month of to.
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// monthlyBreakdown lays out one zero-amount entry for every calendar month within [from, to].
+func monthlyBreakdown(from, to time.Time, currency string) []entity.MonthlySum {
+	var months []entity.MonthlySum
+
+	for m := monthStart(from); !m.After(to); m = m.AddDate(0, 1, 0) {
+		months = append(months, entity.MonthlySum{Month: m.Format(monthLayout), Amount: entity.NewMoney(0, currency)})
+	}
+
+	return months
+}
+
+// addMonthly adds the amounts of the charges to the months laid out by monthlyBreakdown from the month of from.
+func addMonthly(months []entity.MonthlySum, from time.Time, charges []charge) error {
+	for _, c := range charges {
+		month := &months[monthsBetween(from, c.date)]
+
+		var err error
+		if month.Amount, err = month.Amount.Add(c.amount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextChargeDate returns the first billing date of sub after the given day.
+func nextChargeDate(sub entity.Subscription, after time.Time) time.Time {
+	start := billingStart(sub)
+	period := billingPeriod(sub)
+
+	for k := 0; ; k++ {
+		if date := chargeDate(start, period, k); date.After(after) {
+			return date
+		}
+	}
+}
+
+// isPaused reports whether the day falls into one of the intervals the subscription spent paused.
+func isPaused(intervals []entity.Interval, day time.Time) bool {
+	for _, interval := range intervals {
+		if interval.Contains(day) {
+			return true
+		}
+	}
+
+	return false
+}
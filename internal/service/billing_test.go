@@ -0,0 +1,192 @@
+package service
+
+import (
+	"online-subscribe-rest-service/internal/entity"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func dates(ss ...string) []time.Time {
+	var ts []time.Time
+	for _, s := range ss {
+		ts = append(ts, date(s))
+	}
+
+	return ts
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		name  string
+		start string
+		n     int
+		want  string
+	}{
+		{"same day", "2025-01-15", 1, "2025-02-15"},
+		{"clamped to February", "2025-01-31", 1, "2025-02-28"},
+		{"clamped to leap February", "2024-01-31", 1, "2024-02-29"},
+		{"clamped to April", "2025-03-31", 1, "2025-04-30"},
+		{"not sticky after clamping", "2025-01-31", 2, "2025-03-31"},
+		{"over the year end", "2025-11-30", 3, "2026-02-28"},
+		{"leap day in a year", "2024-02-29", 12, "2025-02-28"},
+		{"backwards", "2025-03-31", -1, "2025-02-28"},
+		{"zero", "2025-05-31", 0, "2025-05-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addMonths(date(tt.start), tt.n); !got.Equal(date(tt.want)) {
+				t.Errorf("addMonths(%s, %d) = %s, want %s", tt.start, tt.n, got.Format(time.DateOnly), tt.want)
+			}
+		})
+	}
+}
+
+func TestChargeDate(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  string
+		period entity.BillingPeriod
+		n      int
+		want   string
+	}{
+		{"first charge on the start date", "2025-01-31", entity.Monthly, 0, "2025-01-31"},
+		{"monthly", "2025-01-31", entity.Monthly, 1, "2025-02-28"},
+		{"monthly from the start, not the previous charge", "2025-01-31", entity.Monthly, 2, "2025-03-31"},
+		{"quarterly", "2025-11-30", entity.Quarterly, 1, "2026-02-28"},
+		{"yearly from a leap day", "2024-02-29", entity.Yearly, 1, "2025-02-28"},
+		{"yearly back on a leap day", "2024-02-29", entity.Yearly, 4, "2028-02-29"},
+		{"daily", "2025-02-27", entity.BillingPeriod{Unit: entity.BillingDay, Count: 14}, 1, "2025-03-13"},
+		{"weekly", "2025-12-29", entity.BillingPeriod{Unit: entity.BillingWeek, Count: 2}, 1, "2026-01-12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chargeDate(date(tt.start), tt.period, tt.n); !got.Equal(date(tt.want)) {
+				t.Errorf("chargeDate(%s, %v, %d) = %s, want %s", tt.start, tt.period, tt.n, got.Format(time.DateOnly), tt.want)
+			}
+		})
+	}
+}
+
+func TestChargeDates(t *testing.T) {
+	end := date("2025-04-30")
+	trialEnd := date("2025-01-14")
+
+	tests := []struct {
+		name string
+		sub  entity.Subscription
+		from string
+		to   string
+		want []time.Time
+	}{
+		{
+			name: "month ends",
+			sub:  entity.Subscription{StartDate: date("2025-01-31"), BillingPeriod: entity.Monthly},
+			from: "2025-01-01",
+			to:   "2025-05-31",
+			want: dates("2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30", "2025-05-31"),
+		},
+		{
+			name: "charges before the range are skipped",
+			sub:  entity.Subscription{StartDate: date("2025-01-31"), BillingPeriod: entity.Monthly},
+			from: "2025-03-01",
+			to:   "2025-04-29",
+			want: dates("2025-03-31"),
+		},
+		{
+			name: "end date is still charged",
+			sub:  entity.Subscription{StartDate: date("2025-01-30"), BillingPeriod: entity.Monthly, EndDate: &end},
+			from: "2025-01-01",
+			to:   "2025-12-31",
+			want: dates("2025-01-30", "2025-02-28", "2025-03-30", "2025-04-30"),
+		},
+		{
+			name: "stored before billing periods",
+			sub:  entity.Subscription{StartDate: date("2025-01-15")},
+			from: "2025-01-01",
+			to:   "2025-03-14",
+			want: dates("2025-01-15", "2025-02-15"),
+		},
+		{
+			name: "billing starts after the trial",
+			sub:  entity.Subscription{StartDate: date("2025-01-01"), BillingPeriod: entity.Monthly, TrialEnd: &trialEnd},
+			from: "2025-01-01",
+			to:   "2025-03-01",
+			want: dates("2025-01-15", "2025-02-15"),
+		},
+		{
+			name: "quarterly",
+			sub:  entity.Subscription{StartDate: date("2024-11-30"), BillingPeriod: entity.Quarterly},
+			from: "2025-01-01",
+			to:   "2025-12-31",
+			want: dates("2025-02-28", "2025-05-30", "2025-08-30", "2025-11-30"),
+		},
+		{
+			name: "nothing in the range",
+			sub:  entity.Subscription{StartDate: date("2025-01-01"), BillingPeriod: entity.Yearly},
+			from: "2025-02-01",
+			to:   "2025-12-31",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chargeDates(tt.sub, date(tt.from), date(tt.to))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chargeDates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextChargeDate(t *testing.T) {
+	sub := entity.Subscription{StartDate: date("2025-01-31"), BillingPeriod: entity.Monthly}
+
+	tests := []struct {
+		after string
+		want  string
+	}{
+		{"2025-01-30", "2025-01-31"},
+		{"2025-01-31", "2025-02-28"},
+		{"2025-02-28", "2025-03-31"},
+		{"2025-04-01", "2025-04-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.after, func(t *testing.T) {
+			if got := nextChargeDate(sub, date(tt.after)); !got.Equal(date(tt.want)) {
+				t.Errorf("nextChargeDate(%s) = %s, want %s", tt.after, got.Format(time.DateOnly), tt.want)
+			}
+		})
+	}
+}
+
+func TestMonthlyBreakdown(t *testing.T) {
+	months := monthlyBreakdown(date("2024-11-15"), date("2025-02-01"), "RUB")
+
+	var got []string
+	for _, m := range months {
+		got = append(got, m.Month)
+	}
+
+	want := []string{"2024-11", "2024-12", "2025-01", "2025-02"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("monthlyBreakdown() months = %v, want %v", got, want)
+	}
+
+	if i := monthsBetween(date("2024-11-15"), date("2025-02-01")); i != 3 {
+		t.Errorf("monthsBetween() = %d, want 3", i)
+	}
+}
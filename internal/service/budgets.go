@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (s *Service) CreateBudget(ctx context.Context, b entity.Budget) (uuid.UUID, error) {
+	if b.Limit.Currency == "" {
+		b.Limit.Currency = s.currency
+	}
+
+	id, err := s.repo.CreateBudget(ctx, b)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create budget: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdateBudget changes the budget's period, category and limit, a budget never moves to another user.
+func (s *Service) UpdateBudget(ctx context.Context, b entity.Budget) error {
+	if b.Limit.Currency == "" {
+		b.Limit.Currency = s.currency
+	}
+
+	if err := s.repo.UpdateBudget(ctx, b); err != nil {
+		return fmt.Errorf("failed to update budget %s: %w", b.ID, err)
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.DeleteBudget(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete budget %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// BudgetByID returns the budget together with where it stands in the current period.
+func (s *Service) BudgetByID(ctx context.Context, id uuid.UUID) (entity.BudgetStatus, error) {
+	b, err := s.repo.BudgetByID(ctx, id)
+	if err != nil {
+		return entity.BudgetStatus{}, fmt.Errorf("failed to get budget by id %s: %w", id, err)
+	}
+
+	statuses, err := s.statuses(ctx, b.UserID, []entity.Budget{b})
+	if err != nil {
+		return entity.BudgetStatus{}, fmt.Errorf("failed to get budget by id %s: %w", id, err)
+	}
+
+	return statuses[0], nil
+}
+
+// Budgets returns the user's budgets together with where they stand in the current period.
+func (s *Service) Budgets(ctx context.Context, userID uuid.UUID) ([]entity.BudgetStatus, error) {
+	statuses, err := s.budgetStatuses(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budgets of user %s: %w", userID, err)
+	}
+
+	if statuses == nil {
+		statuses = []entity.BudgetStatus{}
+	}
+
+	return statuses, nil
+}
+
+func (s *Service) budgetStatuses(ctx context.Context, userID uuid.UUID) ([]entity.BudgetStatus, error) {
+	budgets, err := s.repo.Budgets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(budgets) == 0 {
+		return nil, nil
+	}
+
+	return s.statuses(ctx, userID, budgets)
+}
+
+// statuses counts the charges of the user's subscriptions in the current period of every budget
+// in the currency of the budget's limit.
+func (s *Service) statuses(ctx context.Context, userID uuid.UUID, budgets []entity.Budget) ([]entity.BudgetStatus, error) {
+	today := dateOf(s.now())
+
+	// The current year holds the current month, so one look at the subscriptions serves every budget.
+	yearStart, yearEnd := budgetPeriod(entity.BudgetYearly, today)
+
+	subs, ledger, err := s.userLedger(ctx, userID, yearStart, yearEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]entity.BudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		start, end := budgetPeriod(b.Period, today)
+
+		status := entity.BudgetStatus{
+			Budget:      b,
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Spent:       entity.NewMoney(0, b.Limit.Currency),
+			Projected:   entity.NewMoney(0, b.Limit.Currency),
+		}
+
+		for _, sub := range subs {
+			if b.Category != "" && b.Category != subscriptionCategory(sub) {
+				continue
+			}
+
+			charges, err := s.convertedCharges(ctx, ledger, sub, start, end, b.Limit.Currency)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, charge := range charges {
+				if status.Projected, err = status.Projected.Add(charge.amount); err != nil {
+					return nil, err
+				}
+
+				if !charge.date.After(today) {
+					if status.Spent, err = status.Spent.Add(charge.amount); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+
+		status.Remaining = entity.NewMoney(b.Limit.Amount-status.Projected.Amount, b.Limit.Currency)
+		status.OverBudget = status.Projected.Amount > b.Limit.Amount
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// budgetCheck holds the budget statuses of the users a change touches, taken before the change.
+type budgetCheck struct {
+	users  []uuid.UUID
+	before map[uuid.UUID][]entity.BudgetStatus
+}
+
+// checkBudgets takes the budget statuses of the users ahead of a change. Budgets only add a warning
+// to the change, so a user whose budgets can't be counted, say for a missing exchange rate, is
+// logged and left out instead of failing it.
+func (s *Service) checkBudgets(ctx context.Context, userIDs ...uuid.UUID) budgetCheck {
+	check := budgetCheck{before: make(map[uuid.UUID][]entity.BudgetStatus, len(userIDs))}
+
+	for _, userID := range userIDs {
+		if _, ok := check.before[userID]; ok {
+			continue
+		}
+
+		statuses, err := s.budgetStatuses(ctx, userID)
+		if err != nil {
+			s.log.WarnF("service: failed to check budgets of user %s: %v", userID, err)
+			continue
+		}
+
+		check.users = append(check.users, userID)
+		check.before[userID] = statuses
+	}
+
+	return check
+}
+
+// exceeded returns the budgets of the checked users the committed change pushed over their limit
+// and raises an alert for each. Like checkBudgets it logs and skips the users it can't count.
+func (s *Service) exceeded(ctx context.Context, check budgetCheck) []entity.BudgetStatus {
+	var exceeded []entity.BudgetStatus
+
+	for _, userID := range check.users {
+		after, err := s.budgetStatuses(ctx, userID)
+		if err != nil {
+			s.log.WarnF("service: failed to check budgets of user %s: %v", userID, err)
+			continue
+		}
+
+		exceeded = append(exceeded, exceededBudgets(check.before[userID], after)...)
+	}
+
+	s.alertExceeded(ctx, exceeded)
+
+	return exceeded
+}
+
+// exceededBudgets returns the budgets that are over their limit now but weren't in before.
+func exceededBudgets(before, after []entity.BudgetStatus) []entity.BudgetStatus {
+	wasOver := make(map[uuid.UUID]bool, len(before))
+	for _, status := range before {
+		wasOver[status.ID] = status.OverBudget
+	}
+
+	var exceeded []entity.BudgetStatus
+	for _, status := range after {
+		if status.OverBudget && !wasOver[status.ID] {
+			exceeded = append(exceeded, status)
+		}
+	}
+
+	return exceeded
+}
+
+// alertExceeded tells the alerts about every budget a committed change pushed over its limit.
+func (s *Service) alertExceeded(ctx context.Context, exceeded []entity.BudgetStatus) {
+	for _, status := range exceeded {
+		s.alerts.BudgetExceeded(ctx, status)
+	}
+}
+
+// budgetPeriod returns the first and the last day of the calendar month or year the day falls into.
+func budgetPeriod(period entity.BudgetPeriod, day time.Time) (time.Time, time.Time) {
+	if period == entity.BudgetYearly {
+		start := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, -1)
+	}
+
+	start := monthStart(day)
+	return start, start.AddDate(0, 1, -1)
+}
+
+func subscriptionCategory(sub entity.Subscription) entity.Category {
+	if sub.Category == "" {
+		return entity.CategoryOther
+	}
+
+	return sub.Category
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestBudgetLimit(t *testing.T) {
+	const limit = 100000
+
+	tests := []struct {
+		name      string
+		price     int64
+		remaining int64
+		over      bool
+	}{
+		{name: "under", price: limit - 1, remaining: 1},
+		{name: "at", price: limit, remaining: 0},
+		{name: "over", price: limit + 1, remaining: -1, over: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, alerts := newTestService(t, "2026-10-18")
+			ctx := context.Background()
+
+			user := uuid.Must(uuid.NewV4())
+
+			budgetID, err := s.CreateBudget(ctx, entity.Budget{UserID: user, Period: entity.BudgetMonthly, Limit: entity.NewMoney(limit, "RUB")})
+			if err != nil {
+				t.Fatalf("CreateBudget() error = %v", err)
+			}
+
+			_, exceeded, err := s.CreateSubscription(ctx, monthly(user, "Okko", tt.price, "2026-10-01", ""))
+			if err != nil {
+				t.Fatalf("CreateSubscription() error = %v", err)
+			}
+
+			if got := len(exceeded) == 1 && exceeded[0].ID == budgetID; got != tt.over || len(exceeded) > 1 {
+				t.Errorf("CreateSubscription() exceeded = %v, want the budget only when over", exceeded)
+			}
+
+			if got := len(alerts.exceeded); tt.over && got != 1 || !tt.over && got != 0 {
+				t.Errorf("alerts raised = %d, over budget = %v", got, tt.over)
+			}
+
+			status, err := s.BudgetByID(ctx, budgetID)
+			if err != nil {
+				t.Fatalf("BudgetByID() error = %v", err)
+			}
+
+			if status.OverBudget != tt.over {
+				t.Errorf("BudgetByID() over budget = %v, want %v", status.OverBudget, tt.over)
+			}
+
+			if want := entity.NewMoney(tt.remaining, "RUB"); status.Remaining != want {
+				t.Errorf("BudgetByID() remaining = %v, want %v", status.Remaining, want)
+			}
+
+			if want := entity.NewMoney(tt.price, "RUB"); status.Projected != want || status.Spent != want {
+				t.Errorf("BudgetByID() projected = %v, spent = %v, want %v", status.Projected, status.Spent, want)
+			}
+		})
+	}
+}
+
+func TestBudgetAlertsOnlyWhenCrossed(t *testing.T) {
+	s, _, alerts := newTestService(t, "2026-10-18")
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+
+	if _, err := s.CreateBudget(ctx, entity.Budget{UserID: user, Period: entity.BudgetMonthly, Limit: entity.NewMoney(100000, "RUB")}); err != nil {
+		t.Fatalf("CreateBudget() error = %v", err)
+	}
+
+	// A changed price takes effect today, so the charge counted has to be still ahead in the month.
+	id := mustCreate(t, s, monthly(user, "Okko", 50000, "2026-10-25", ""))
+
+	// Each step updates the price, only the ones that cross the limit report the budget.
+	steps := []struct {
+		price    int64
+		exceeded int
+	}{
+		{price: 100000, exceeded: 0},
+		{price: 150000, exceeded: 1},
+		{price: 200000, exceeded: 0},
+		{price: 50000, exceeded: 0},
+		{price: 120000, exceeded: 1},
+	}
+
+	for i, step := range steps {
+		sub, err := s.SubscriptionByID(ctx, id)
+		if err != nil {
+			t.Fatalf("SubscriptionByID() error = %v", err)
+		}
+
+		sub.Price = entity.NewMoney(step.price, "RUB")
+
+		_, exceeded, err := s.UpdateSubscription(ctx, sub)
+		if err != nil {
+			t.Fatalf("step %d: UpdateSubscription() error = %v", i, err)
+		}
+
+		if len(exceeded) != step.exceeded {
+			t.Errorf("step %d: UpdateSubscription() exceeded %d budgets, want %d", i, len(exceeded), step.exceeded)
+		}
+	}
+
+	if len(alerts.exceeded) != 2 {
+		t.Errorf("alerts raised = %d, want 2", len(alerts.exceeded))
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (s *Service) CreateCatalogEntry(ctx context.Context, e entity.CatalogEntry) (uuid.UUID, error) {
+	id, err := s.repo.CreateCatalogEntry(ctx, trimNames(e))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create catalog entry: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdateCatalogEntry replaces the catalog entry, subscriptions to the service are renamed along with it.
+func (s *Service) UpdateCatalogEntry(ctx context.Context, e entity.CatalogEntry) error {
+	e = trimNames(e)
+
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		current, err := s.repo.CatalogEntryByID(ctx, e.ID)
+		if err != nil {
+			return err
+		}
+
+		var renamed []entity.Subscription
+		if current.Name != e.Name {
+			if renamed, err = s.serviceSubscriptions(ctx, current); err != nil {
+				return err
+			}
+		}
+
+		if err := s.repo.UpdateCatalogEntry(ctx, e); err != nil {
+			return err
+		}
+
+		for i := range renamed {
+			changed, err := s.storedSubscription(ctx, renamed[i].ID)
+			if err != nil {
+				return err
+			}
+
+			if err := s.recordEvent(ctx, entity.SubscriptionUpdated, &renamed[i], changed); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update catalog entry %s: %w", e.ID, err)
+	}
+
+	return nil
+}
+
+// serviceSubscriptions returns every subscription to the catalog entry with its tags.
+func (s *Service) serviceSubscriptions(ctx context.Context, e entity.CatalogEntry) ([]entity.Subscription, error) {
+	// Subscriptions carry the entry's name, the search narrows them down and the ID picks the exact ones.
+	filter := entity.SubscriptionFilter{
+		Sort:        entity.SortStartDate,
+		Limit:       entity.MaxPageLimit,
+		ServiceName: e.Name,
+	}
+
+	var subs []entity.Subscription
+
+	for {
+		page, _, err := s.repo.SearchSubscriptions(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to find subscriptions to %s: %w", e.Name, err)
+		}
+
+		for _, sub := range page {
+			if sub.ServiceID == e.ID {
+				subs = append(subs, sub)
+			}
+		}
+
+		if len(page) < filter.Limit {
+			break
+		}
+
+		cursor := entity.NewListCursor(filter.Sort, filter.Desc, page[len(page)-1])
+		filter.Cursor = &cursor
+	}
+
+	if err := s.withTags(ctx, subs); err != nil {
+		return nil, fmt.Errorf("service: failed to get tags of subscriptions to %s: %w", e.Name, err)
+	}
+
+	return subs, nil
+}
+
+// DeleteCatalogEntry removes a service nobody is subscribed to.
+func (s *Service) DeleteCatalogEntry(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.DeleteCatalogEntry(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete catalog entry %s: %w", id, err)
+	}
+
+	return nil
+}
+
+func (s *Service) CatalogEntryByID(ctx context.Context, id uuid.UUID) (entity.CatalogEntry, error) {
+	e, err := s.repo.CatalogEntryByID(ctx, id)
+	if err != nil {
+		return entity.CatalogEntry{}, fmt.Errorf("failed to get catalog entry by id %s: %w", id, err)
+	}
+
+	return e, nil
+}
+
+func (s *Service) CatalogEntries(ctx context.Context) ([]entity.CatalogEntry, error) {
+	entries, err := s.repo.CatalogEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+
+	if entries == nil {
+		entries = []entity.CatalogEntry{}
+	}
+
+	return entries, nil
+}
+
+// resolveService points sub to its catalog entry and gives it the entry's canonical name.
+// A subscription without ServiceID is matched by name, and a name the catalog doesn't know
+// becomes a new entry. A subscription without a price or category takes the entry's default ones.
+func (s *Service) resolveService(ctx context.Context, sub *entity.Subscription) error {
+	var (
+		e   entity.CatalogEntry
+		err error
+	)
+
+	switch {
+	case !sub.ServiceID.IsNil():
+		e, err = s.repo.CatalogEntryByID(ctx, sub.ServiceID)
+		if errors.Is(err, entity.ErrNotFound) {
+			return fmt.Errorf("%w: unknown service id %s", entity.ErrInvalidSubscription, sub.ServiceID)
+		}
+	default:
+		e, err = s.repo.CatalogEntryByName(ctx, sub.ServiceName)
+		if errors.Is(err, entity.ErrNotFound) {
+			e = entity.CatalogEntry{Name: strings.TrimSpace(sub.ServiceName)}
+			e.ID, err = s.repo.CreateCatalogEntry(ctx, e)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("service: failed to resolve service: %w", err)
+	}
+
+	sub.ServiceID = e.ID
+	sub.ServiceName = e.Name
+
+	if sub.Category == "" {
+		sub.Category = e.Category
+	}
+
+	if sub.Category == "" {
+		sub.Category = entity.CategoryOther
+	}
+
+	if sub.Price.Amount == 0 {
+		if e.DefaultPrice == nil {
+			return fmt.Errorf("%w: price is empty and %s has no default price", entity.ErrInvalidSubscription, e.Name)
+		}
+
+		sub.Price = *e.DefaultPrice
+	}
+
+	return nil
+}
+
+// lookupService returns the ID of the catalog entry known by the name, uuid.Nil when there is none.
+func (s *Service) lookupService(ctx context.Context, name string) (uuid.UUID, error) {
+	e, err := s.repo.CatalogEntryByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return uuid.Nil, nil
+		}
+
+		return uuid.Nil, err
+	}
+
+	return e.ID, nil
+}
+
+func trimNames(e entity.CatalogEntry) entity.CatalogEntry {
+	e.Name = strings.TrimSpace(e.Name)
+
+	aliases := make([]string, 0, len(e.Aliases))
+	for _, alias := range e.Aliases {
+		aliases = append(aliases, strings.TrimSpace(alias))
+	}
+	e.Aliases = aliases
+
+	return e
+}
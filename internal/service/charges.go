@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// charge is one billing of a subscription. Its price is in the subscription's own currency,
+// amount is the price converted to the currency the charges were asked in.
+type charge struct {
+	date   time.Time
+	price  entity.Money
+	amount entity.Money
+}
+
+// ledger holds the pauses and price changes of a set of subscriptions, everything besides
+// the subscriptions themselves that decides what they were charged.
+type ledger struct {
+	paused   map[uuid.UUID][]entity.Interval
+	prices   map[uuid.UUID][]entity.PricePeriod
+	currency string
+}
+
+// userLedger returns the user's subscriptions that overlap [from, to] along with their ledger.
+func (s *Service) userLedger(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]entity.Subscription, ledger, error) {
+	subs, err := s.repo.SubscriptionsInPeriod(ctx, entity.SubscriptionsSumParams{
+		UserID:    userID,
+		StartDate: from,
+		EndDate:   &to,
+	})
+	if err != nil {
+		return nil, ledger{}, err
+	}
+
+	l, err := s.ledger(ctx, subs)
+	if err != nil {
+		return nil, ledger{}, err
+	}
+
+	return subs, l, nil
+}
+
+func (s *Service) ledger(ctx context.Context, subs []entity.Subscription) (ledger, error) {
+	paused, err := s.pausedIntervals(ctx, subs)
+	if err != nil {
+		return ledger{}, err
+	}
+
+	prices, err := s.priceHistories(ctx, subs)
+	if err != nil {
+		return ledger{}, err
+	}
+
+	return ledger{paused: paused, prices: prices, currency: s.currency}, nil
+}
+
+// charges returns the charges of sub within [from, to]. This is how every sum, report, budget, forecast
+// and renewal counts charges: every billing date except the ones falling on paused days, each at the
+// price in effect on its date. Their amounts are left in the subscription's currency.
+func (l ledger) charges(sub entity.Subscription, from, to time.Time) []charge {
+	var charges []charge
+
+	for _, date := range chargeDates(sub, from, to) {
+		if isPaused(l.paused[sub.ID], date) {
+			continue
+		}
+
+		price := l.priceOn(sub, date)
+		charges = append(charges, charge{date: date, price: price, amount: price})
+	}
+
+	return charges
+}
+
+// convertedCharges returns the charges of sub within [from, to] with their amounts converted
+// to currency at the rate of each charge's date.
+func (s *Service) convertedCharges(ctx context.Context, l ledger, sub entity.Subscription, from, to time.Time, currency string) ([]charge, error) {
+	charges := l.charges(sub, from, to)
+
+	for i := range charges {
+		var err error
+		if charges[i].amount, err = s.convert(ctx, charges[i].price, currency, charges[i].date); err != nil {
+			return nil, err
+		}
+	}
+
+	return charges, nil
+}
+
+// sumCharges adds the amounts of the charges up, starting from zero in currency.
+func sumCharges(charges []charge, currency string) (entity.Money, error) {
+	total := entity.NewMoney(0, currency)
+
+	for _, c := range charges {
+		var err error
+		if total, err = total.Add(c.amount); err != nil {
+			return entity.Money{}, err
+		}
+	}
+
+	return total, nil
+}
+
+// priceOn returns the price of sub on the day, prices stored before currencies existed are in the default currency.
+func (l ledger) priceOn(sub entity.Subscription, day time.Time) entity.Money {
+	price := priceOn(sub, l.prices[sub.ID], day)
+	if price.Currency == "" {
+		price.Currency = l.currency
+	}
+
+	return price
+}
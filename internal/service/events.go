@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/audit"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// recordEvent puts the event about a change of the subscription into the outbox and the change
+// into the audit log. It runs in the transaction making the change, so the event is published and
+// the change audited if and only if the change commits, and after the change locked the
+// subscription's row, so its events are added in commit order. before is nil for a created
+// or restored subscription and after for a deleted or purged one.
+func (s *Service) recordEvent(ctx context.Context, typ entity.SubscriptionEventType, before, after *entity.Subscription) error {
+	sub := after
+	if sub == nil {
+		sub = before
+	}
+
+	event := entity.SubscriptionEvent{
+		ID:             uuid.Must(uuid.NewV4()),
+		Type:           typ,
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		OccurredAt:     s.now().UTC(),
+		Before:         before,
+		After:          after,
+	}
+
+	if err := s.repo.AddOutboxEvent(ctx, event); err != nil {
+		return fmt.Errorf("service: failed to record %s event: %w", typ, err)
+	}
+
+	if err := s.audit(ctx, auditActions[typ], sub.ID, event.OccurredAt, before, after); err != nil {
+		return fmt.Errorf("service: failed to audit %s of subscription %s: %w", auditActions[typ], sub.ID, err)
+	}
+
+	return nil
+}
+
+var auditActions = map[entity.SubscriptionEventType]entity.AuditAction{
+	entity.SubscriptionCreated:  entity.AuditCreate,
+	entity.SubscriptionUpdated:  entity.AuditUpdate,
+	entity.SubscriptionDeleted:  entity.AuditDelete,
+	entity.SubscriptionRestored: entity.AuditRestore,
+	entity.SubscriptionPurged:   entity.AuditPurge,
+}
+
+// audit appends the change of the subscription to the audit log on behalf of the context's actor.
+func (s *Service) audit(ctx context.Context, action entity.AuditAction, id uuid.UUID, at time.Time, before, after *entity.Subscription) error {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		return err
+	}
+
+	actor, requestID := audit.Actor(ctx)
+
+	return s.repo.AddAuditEntry(ctx, entity.AuditEntry{
+		OccurredAt: at,
+		Actor:      actor,
+		RequestID:  requestID,
+		Action:     action,
+		EntityType: entity.AuditEntitySubscription,
+		EntityID:   id,
+		Changes:    changes,
+	})
+}
+
+// storedSubscription reads the subscription the way it is stored, tags included, for the payload of an event.
+func (s *Service) storedSubscription(ctx context.Context, id uuid.UUID) (*entity.Subscription, error) {
+	sub, err := s.repo.SubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to read subscription %s: %w", id, err)
+	}
+
+	subs := []entity.Subscription{sub}
+	if err := s.withTags(ctx, subs); err != nil {
+		return nil, fmt.Errorf("service: failed to get tags of subscription %s: %w", id, err)
+	}
+
+	return &subs[0], nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+)
+
+// Forecast projects the charges of the user's subscriptions from tomorrow over the next params.Months months.
+// Subscriptions are billed on their usual dates until their end date, at the price in effect on each
+// date, so price changes scheduled ahead are taken into account. Paused subscriptions aren't charged
+// until they are resumed. Amounts are converted at the latest known rate.
+func (s *Service) Forecast(ctx context.Context, params entity.ForecastParams) (entity.Forecast, error) {
+	today := dateOf(s.now())
+	from := today.AddDate(0, 0, 1)
+	to := addMonths(today, params.Months)
+
+	subs, ledger, err := s.userLedger(ctx, params.UserID, from, to)
+	if err != nil {
+		return entity.Forecast{}, fmt.Errorf("failed to get forecast %w", err)
+	}
+
+	subs = slices.DeleteFunc(subs, func(sub entity.Subscription) bool {
+		return sub.Status == entity.StatusExpired || (params.ExcludeCancelling && sub.Status == entity.StatusCancelled)
+	})
+
+	currency := params.TargetCurrency
+	if currency == "" {
+		currency = s.currency
+	}
+
+	forecast := entity.Forecast{
+		UserID:  params.UserID,
+		From:    from,
+		To:      to,
+		Charges: []entity.ForecastCharge{},
+		Months:  monthlyBreakdown(from, to, currency),
+		Total:   entity.NewMoney(0, currency),
+	}
+
+	for _, sub := range subs {
+		charges, err := s.convertedCharges(ctx, ledger, sub, from, to, currency)
+		if err != nil {
+			return entity.Forecast{}, fmt.Errorf("failed to get forecast %w", err)
+		}
+
+		for _, charge := range charges {
+			forecast.Charges = append(forecast.Charges, entity.ForecastCharge{
+				Date:           charge.date,
+				SubscriptionID: sub.ID,
+				ServiceName:    sub.ServiceName,
+				Price:          charge.price,
+				Amount:         charge.amount,
+			})
+		}
+
+		if err := addMonthly(forecast.Months, from, charges); err != nil {
+			return entity.Forecast{}, fmt.Errorf("failed to get forecast %w", err)
+		}
+
+		total, err := sumCharges(charges, currency)
+		if err != nil {
+			return entity.Forecast{}, fmt.Errorf("failed to get forecast %w", err)
+		}
+
+		if forecast.Total, err = forecast.Total.Add(total); err != nil {
+			return entity.Forecast{}, fmt.Errorf("failed to get forecast %w", err)
+		}
+	}
+
+	slices.SortFunc(forecast.Charges, func(a, b entity.ForecastCharge) int {
+		return cmp.Or(a.Date.Compare(b.Date), cmp.Compare(a.ServiceName, b.ServiceName), cmp.Compare(a.SubscriptionID.String(), b.SubscriptionID.String()))
+	})
+
+	return forecast, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func (s *Service) NotificationPreferences(ctx context.Context, userID uuid.UUID) ([]entity.NotificationPreference, error) {
+	prefs, err := s.repo.NotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences of user %s: %w", userID, err)
+	}
+
+	return prefs, nil
+}
+
+// SetNotificationPreferences replaces the places the user is notified at, no preferences turn notifications off.
+func (s *Service) SetNotificationPreferences(ctx context.Context, userID uuid.UUID, prefs []entity.NotificationPreference) error {
+	if err := s.repo.SetNotificationPreferences(ctx, userID, prefs); err != nil {
+		return fmt.Errorf("failed to set notification preferences of user %s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// Deliveries returns the latest notifications sent to the user, newest first.
+func (s *Service) Deliveries(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Delivery, error) {
+	deliveries, err := s.repo.Deliveries(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries of user %s: %w", userID, err)
+	}
+
+	return deliveries, nil
+}
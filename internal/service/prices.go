@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// changePrice records a new price period for the subscription starting on from, today when nil,
+// and returns the latest price of its history, the one the subscription row keeps.
+// The start date is the earliest a price can take effect on.
+func (s *Service) changePrice(ctx context.Context, current entity.Subscription, price entity.Money, from *time.Time) (entity.Money, error) {
+	history, err := s.repo.PriceHistory(ctx, []uuid.UUID{current.ID})
+	if err != nil {
+		return entity.Money{}, fmt.Errorf("service: failed to get price history: %w", err)
+	}
+
+	// Subscriptions without history have had their current price since they started.
+	if len(history) == 0 {
+		initial := entity.PricePeriod{SubscriptionID: current.ID, Price: current.Price, EffectiveFrom: dateOf(current.StartDate)}
+		if err := s.repo.SetPrice(ctx, initial); err != nil {
+			return entity.Money{}, fmt.Errorf("service: failed to record price: %w", err)
+		}
+	}
+
+	on := dateOf(s.now())
+	if from != nil {
+		on = dateOf(*from)
+	}
+
+	if start := dateOf(current.StartDate); on.Before(start) {
+		on = start
+	}
+
+	if err := s.repo.SetPrice(ctx, entity.PricePeriod{SubscriptionID: current.ID, Price: price, EffectiveFrom: on}); err != nil {
+		return entity.Money{}, fmt.Errorf("service: failed to record price: %w", err)
+	}
+
+	history, err = s.repo.PriceHistory(ctx, []uuid.UUID{current.ID})
+	if err != nil {
+		return entity.Money{}, fmt.Errorf("service: failed to get price history: %w", err)
+	}
+
+	return history[len(history)-1].Price, nil
+}
+
+// PriceHistory returns the subscription's price periods, oldest first.
+func (s *Service) PriceHistory(ctx context.Context, id uuid.UUID) ([]entity.PricePeriod, error) {
+	sub, err := s.repo.SubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription by id %s: %w", id, err)
+	}
+
+	history, err := s.repo.PriceHistory(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history of subscription %s: %w", id, err)
+	}
+
+	if len(history) == 0 {
+		history = []entity.PricePeriod{{SubscriptionID: id, Price: sub.Price, EffectiveFrom: dateOf(sub.StartDate)}}
+	}
+
+	return history, nil
+}
+
+// priceHistories returns the price history of every subscription in subs.
+func (s *Service) priceHistories(ctx context.Context, subs []entity.Subscription) (map[uuid.UUID][]entity.PricePeriod, error) {
+	if len(subs) == 0 {
+		return nil, nil
+	}
+
+	history, err := s.repo.PriceHistory(ctx, subscriptionIDs(subs))
+	if err != nil {
+		return nil, err
+	}
+
+	bySubscription := make(map[uuid.UUID][]entity.PricePeriod)
+	for _, period := range history {
+		bySubscription[period.SubscriptionID] = append(bySubscription[period.SubscriptionID], period)
+	}
+
+	return bySubscription, nil
+}
+
+// priceOn returns the price of sub on the day according to its history, subscriptions without
+// history cost their current price.
+func priceOn(sub entity.Subscription, history []entity.PricePeriod, day time.Time) entity.Money {
+	price, ok := entity.PriceOn(history, day)
+	if !ok {
+		price = sub.Price
+	}
+
+	return price
+}
+
+func subscriptionIDs(subs []entity.Subscription) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestSumAcrossPriceChange(t *testing.T) {
+	s, _, _ := newTestService(t, "2026-10-18")
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+	id := mustCreate(t, s, monthly(user, "Okko", 39900, "2026-01-15", ""))
+
+	sub, err := s.SubscriptionByID(ctx, id)
+	if err != nil {
+		t.Fatalf("SubscriptionByID() error = %v", err)
+	}
+
+	// The new price takes effect in the middle of the period paid on March 15th,
+	// so that charge keeps the old price and the April one is the first at the new price.
+	from := date("2026-04-01")
+	sub.Price = entity.NewMoney(49900, "RUB")
+	sub.PriceEffectiveFrom = &from
+
+	if _, _, err := s.UpdateSubscription(ctx, sub); err != nil {
+		t.Fatalf("UpdateSubscription() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		want       int64
+	}{
+		{name: "old price only", start: "2026-01-01", end: "2026-03-31", want: 3 * 39900},
+		{name: "new price only", start: "2026-04-01", end: "2026-06-30", want: 3 * 49900},
+		{name: "both prices", start: "2026-01-01", end: "2026-06-30", want: 3*39900 + 3*49900},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := date(tt.end)
+
+			sum, err := s.SubscriptionsSum(ctx, entity.SubscriptionsSumParams{UserID: user, ServiceName: "Okko", StartDate: date(tt.start), EndDate: &end})
+			if err != nil {
+				t.Fatalf("SubscriptionsSum() error = %v", err)
+			}
+
+			if want := entity.NewMoney(tt.want, "RUB"); sum.TotalPrice != want {
+				t.Errorf("SubscriptionsSum() total = %v, want %v", sum.TotalPrice, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	// calendarPastDays and calendarMonths bound the renewals a calendar feed shows around today.
+	calendarPastDays = 31
+	calendarMonths   = 12
+)
+
+// Renewals returns the days the user's subscriptions are charged on within the period, oldest first,
+// each with the price in effect that day. Days the subscription is paused on aren't renewals.
+func (s *Service) Renewals(ctx context.Context, params entity.RenewalsParams) ([]entity.Renewal, error) {
+	from := dateOf(params.From)
+	to := dateOf(params.To)
+
+	subs, ledger, err := s.userLedger(ctx, params.UserID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renewals %w", err)
+	}
+
+	renewals := []entity.Renewal{}
+	for _, sub := range subs {
+		for _, charge := range ledger.charges(sub, from, to) {
+			renewals = append(renewals, entity.Renewal{
+				Date:           charge.date,
+				SubscriptionID: sub.ID,
+				UserID:         sub.UserID,
+				ServiceName:    sub.ServiceName,
+				Price:          charge.price,
+			})
+		}
+	}
+
+	slices.SortFunc(renewals, func(a, b entity.Renewal) int {
+		return cmp.Or(a.Date.Compare(b.Date), cmp.Compare(a.ServiceName, b.ServiceName), cmp.Compare(a.SubscriptionID.String(), b.SubscriptionID.String()))
+	})
+
+	return renewals, nil
+}
+
+// RemindRenewals raises an alert for every charge of any user due in days days and returns how many
+// it raised. Calling it again the same day raises the same alerts again, the alerts tell the repeats apart.
+func (s *Service) RemindRenewals(ctx context.Context, days int) (int, error) {
+	day := dateOf(s.now()).AddDate(0, 0, days)
+
+	filter := entity.SubscriptionFilter{
+		Sort:       entity.SortStartDate,
+		Limit:      entity.MaxPageLimit,
+		ActiveFrom: &day,
+		ActiveTo:   &day,
+	}
+
+	reminded := 0
+
+	for {
+		subs, _, err := s.repo.SearchSubscriptions(ctx, filter)
+		if err != nil {
+			return reminded, fmt.Errorf("failed to remind renewals: %w", err)
+		}
+
+		ledger, err := s.ledger(ctx, subs)
+		if err != nil {
+			return reminded, fmt.Errorf("failed to remind renewals: %w", err)
+		}
+
+		for _, sub := range subs {
+			for _, charge := range ledger.charges(sub, day, day) {
+				s.alerts.RenewalDue(ctx, entity.Renewal{
+					Date:           charge.date,
+					SubscriptionID: sub.ID,
+					UserID:         sub.UserID,
+					ServiceName:    sub.ServiceName,
+					Price:          charge.price,
+				})
+
+				reminded++
+			}
+		}
+
+		if len(subs) < filter.Limit {
+			return reminded, nil
+		}
+
+		cursor := entity.NewListCursor(filter.Sort, filter.Desc, subs[len(subs)-1])
+		filter.Cursor = &cursor
+	}
+}
+
+// CreateCalendarToken returns a new secret token of the user's calendar feed, revoking the previous one.
+// Only a hash of the token is stored.
+func (s *Service) CreateCalendarToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	if err := s.repo.SetCalendarToken(ctx, userID, hashToken(token)); err != nil {
+		return "", fmt.Errorf("failed to store calendar token: %w", err)
+	}
+
+	return token, nil
+}
+
+// DeleteCalendarToken turns the user's calendar feed off.
+func (s *Service) DeleteCalendarToken(ctx context.Context, userID uuid.UUID) error {
+	if err := s.repo.DeleteCalendarToken(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete calendar token of user %s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// CalendarRenewals returns the renewals of the feed the token belongs to, from a month ago to a year ahead.
+// An unknown token fails with entity.ErrNotFound.
+func (s *Service) CalendarRenewals(ctx context.Context, token string) ([]entity.Renewal, error) {
+	userID, err := s.repo.CalendarUser(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar: %w", err)
+	}
+
+	today := dateOf(s.now())
+
+	return s.Renewals(ctx, entity.RenewalsParams{
+		UserID: userID,
+		From:   today.AddDate(0, 0, -calendarPastDays),
+		To:     addMonths(today, calendarMonths),
+	})
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
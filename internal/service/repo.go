@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Repo is everything the service keeps in storage. Every backend in internal/repository implements it
+// and passes the repotest suite; the methods are documented once, on the focused interfaces below.
+type Repo interface {
+	Transactor
+	SubscriptionRepo
+	HistoryRepo
+	TrashRepo
+	CatalogRepo
+	CalendarRepo
+	BudgetRepo
+	NotificationRepo
+	OutboxRepo
+	AuditRepo
+	WebhookRepo
+}
+
+type Transactor interface {
+	// WithTx runs fn in a transaction that every Repo call made with fn's context joins.
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// SubscriptionRepo keeps the subscriptions and their tags. Subscriptions in the trash are invisible
+// to it, see TrashRepo.
+type SubscriptionRepo interface {
+	SubscriptionByID(context.Context, uuid.UUID) (entity.Subscription, error)
+	// SubscriptionForUpdate reads the subscription and keeps it from concurrent changes until the transaction ends.
+	SubscriptionForUpdate(context.Context, uuid.UUID) (entity.Subscription, error)
+	// UpdateSubscription stores the subscription and bumps its version, it fails with
+	// entity.ErrVersionMismatch unless the subscription is still at s.Version.
+	UpdateSubscription(context.Context, entity.Subscription) error
+	CreateSubscription(context.Context, entity.Subscription) (uuid.UUID, error)
+	// DeleteSubscription moves the subscription to the trash, its histories and tags stay until it is purged.
+	// It fails with entity.ErrVersionMismatch unless the subscription is at the version.
+	DeleteSubscription(ctx context.Context, id uuid.UUID, version int64, at time.Time) error
+	// SubscriptionsList returns up to params.Limit of the user's subscriptions matching the filters,
+	// starting right after params.Cursor.
+	SubscriptionsList(ctx context.Context, params entity.SubscriptionsListParams) ([]entity.Subscription, error)
+	// SearchSubscriptions returns a page of subscriptions of all users and the number of matches on all pages.
+	SearchSubscriptions(ctx context.Context, filter entity.SubscriptionFilter) ([]entity.Subscription, int, error)
+	// SubscriptionsInPeriod returns the user's subscriptions that overlap the requested period,
+	// only those to params.ServiceID when it is set.
+	SubscriptionsInPeriod(ctx context.Context, params entity.SubscriptionsSumParams) ([]entity.Subscription, error)
+
+	// EndingTrials returns the active subscriptions whose trial ends within [from, to] and that
+	// weren't flagged yet, those ending first go first.
+	EndingTrials(ctx context.Context, from, to time.Time) ([]entity.Subscription, error)
+	// EndedSubscriptions returns the subscriptions that ended before the day but aren't expired yet,
+	// those that ended first go first.
+	EndedSubscriptions(ctx context.Context, before time.Time) ([]entity.Subscription, error)
+
+	// SetTags replaces the subscription's tags with the given ones, which belong to the user.
+	SetTags(ctx context.Context, userID, subscriptionID uuid.UUID, tags []string) error
+	// SubscriptionTags returns the sorted tags of those subscriptions that have any.
+	SubscriptionTags(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]string, error)
+}
+
+// HistoryRepo keeps the prices and statuses the subscriptions had over time.
+type HistoryRepo interface {
+	// SetPrice records a price period, replacing the one taking effect on the same day.
+	SetPrice(context.Context, entity.PricePeriod) error
+	// PriceHistory returns the price periods of the subscriptions, grouped by subscription and oldest first.
+	PriceHistory(ctx context.Context, ids []uuid.UUID) ([]entity.PricePeriod, error)
+
+	AddStatusChange(context.Context, entity.StatusChange) error
+	// StatusHistory returns the status changes of the subscriptions, grouped by subscription and oldest first.
+	StatusHistory(ctx context.Context, ids []uuid.UUID) ([]entity.StatusChange, error)
+}
+
+// TrashRepo keeps the deleted subscriptions until they are restored or purged.
+type TrashRepo interface {
+	// RestoreSubscription takes the subscription out of the trash and bumps its version,
+	// it fails with entity.ErrNotFound when it isn't there.
+	RestoreSubscription(context.Context, uuid.UUID) error
+	// DeletedSubscriptions returns up to limit of the user's subscriptions in the trash, the latest deleted first.
+	DeletedSubscriptions(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Subscription, error)
+	// PurgeSubscriptions removes for good the subscriptions deleted before the moment, along with
+	// their histories and tags, and returns them as they were, tags included, the earliest deleted first.
+	PurgeSubscriptions(ctx context.Context, before time.Time) ([]entity.Subscription, error)
+}
+
+type CatalogRepo interface {
+	// CreateCatalogEntry fails with entity.ErrAlreadyExists when a name or alias is taken by another service.
+	CreateCatalogEntry(context.Context, entity.CatalogEntry) (uuid.UUID, error)
+	// UpdateCatalogEntry replaces the service and its aliases, subscriptions to it take the new name.
+	UpdateCatalogEntry(context.Context, entity.CatalogEntry) error
+	// DeleteCatalogEntry fails with entity.ErrServiceInUse while the service has subscriptions, those in the trash included.
+	DeleteCatalogEntry(context.Context, uuid.UUID) error
+	CatalogEntryByID(context.Context, uuid.UUID) (entity.CatalogEntry, error)
+	// CatalogEntryByName finds the service by its name or one of its aliases, ignoring case.
+	CatalogEntryByName(ctx context.Context, name string) (entity.CatalogEntry, error)
+	// CatalogEntries returns the whole catalog ordered by name.
+	CatalogEntries(context.Context) ([]entity.CatalogEntry, error)
+}
+
+type CalendarRepo interface {
+	// SetCalendarToken stores the hash of the user's calendar feed token, replacing the previous one.
+	SetCalendarToken(ctx context.Context, userID uuid.UUID, tokenHash string) error
+	DeleteCalendarToken(ctx context.Context, userID uuid.UUID) error
+	// CalendarUser returns the user the calendar feed token hash belongs to.
+	CalendarUser(ctx context.Context, tokenHash string) (uuid.UUID, error)
+}
+
+type BudgetRepo interface {
+	// CreateBudget fails with entity.ErrAlreadyExists when the user has a budget for the period and category.
+	CreateBudget(context.Context, entity.Budget) (uuid.UUID, error)
+	// UpdateBudget changes the budget's period, category and limit but never its user.
+	UpdateBudget(context.Context, entity.Budget) error
+	DeleteBudget(context.Context, uuid.UUID) error
+	BudgetByID(context.Context, uuid.UUID) (entity.Budget, error)
+	// Budgets returns the user's budgets, monthly ones first and the overall budget before the categories.
+	Budgets(ctx context.Context, userID uuid.UUID) ([]entity.Budget, error)
+}
+
+// NotificationRepo keeps the users' notification preferences and the log the notifier writes,
+// see notifier.Store for the writing side.
+type NotificationRepo interface {
+	// NotificationPreferences returns the places the user is notified at, ordered by channel and address.
+	NotificationPreferences(ctx context.Context, userID uuid.UUID) ([]entity.NotificationPreference, error)
+	// SetNotificationPreferences replaces the places the user is notified at.
+	SetNotificationPreferences(ctx context.Context, userID uuid.UUID, prefs []entity.NotificationPreference) error
+	// Deliveries returns the user's latest deliveries, newest first.
+	Deliveries(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Delivery, error)
+}
+
+// OutboxRepo is the writing side of the outbox, see outbox.Store for the relay's side.
+type OutboxRepo interface {
+	// AddOutboxEvent puts the event into the outbox, it is published once the transaction commits.
+	AddOutboxEvent(context.Context, entity.SubscriptionEvent) error
+}
+
+type AuditRepo interface {
+	// AddAuditEntry appends the entry to the audit log.
+	AddAuditEntry(context.Context, entity.AuditEntry) error
+	// AuditEntries returns up to filter.Limit entries matching the filter, newest first.
+	AuditEntries(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error)
+}
+
+// WebhookRepo manages the webhooks, see webhooks.Store for the side that delivers to them.
+type WebhookRepo interface {
+	CreateWebhook(context.Context, entity.Webhook) (uuid.UUID, error)
+	WebhookByID(context.Context, uuid.UUID) (entity.Webhook, error)
+	// Webhooks returns every webhook, oldest first.
+	Webhooks(context.Context) ([]entity.Webhook, error)
+	// DeleteWebhook removes the webhook along with its deliveries.
+	DeleteWebhook(context.Context, uuid.UUID) error
+	// EnableWebhook turns the webhook back on with a clean failure count.
+	EnableWebhook(context.Context, uuid.UUID) error
+	// WebhookDeliveries returns the webhook's latest deliveries, newest first.
+	WebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]entity.WebhookDelivery, error)
+}
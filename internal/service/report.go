@@ -0,0 +1,115 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// MonthlyReport returns what the user was charged each month of the range for all of their subscriptions,
+// broken down by service.
+func (s *Service) MonthlyReport(ctx context.Context, params entity.MonthlyReportParams) (entity.MonthlyReport, error) {
+	last := s.now()
+	if params.To != nil {
+		last = *params.To
+	}
+
+	from := monthStart(params.From)
+	to := monthStart(last).AddDate(0, 1, -1)
+
+	subs, ledger, err := s.userLedger(ctx, params.UserID, from, to)
+	if err != nil {
+		return entity.MonthlyReport{}, fmt.Errorf("failed to get monthly report %w", err)
+	}
+
+	currency := params.TargetCurrency
+	if currency == "" {
+		currency = s.currency
+	}
+
+	report := entity.MonthlyReport{
+		UserID:   params.UserID,
+		From:     from.Format(monthLayout),
+		To:       to.Format(monthLayout),
+		Rows:     []entity.MonthlyReportRow{},
+		Months:   monthlyBreakdown(from, to, currency),
+		Services: []entity.ServiceSum{},
+		Total:    entity.NewMoney(0, currency),
+	}
+
+	type cell struct {
+		month   int
+		service uuid.UUID
+	}
+
+	cells := make(map[cell]entity.Money)
+	services := make(map[uuid.UUID]entity.ServiceSum)
+
+	for _, sub := range subs {
+		service, ok := services[sub.ServiceID]
+		if !ok {
+			service = entity.ServiceSum{ServiceID: sub.ServiceID, ServiceName: sub.ServiceName, Amount: entity.NewMoney(0, currency)}
+		}
+
+		charges, err := s.convertedCharges(ctx, ledger, sub, from, to, currency)
+		if err != nil {
+			return entity.MonthlyReport{}, fmt.Errorf("failed to get monthly report %w", err)
+		}
+
+		if err := addMonthly(report.Months, from, charges); err != nil {
+			return entity.MonthlyReport{}, fmt.Errorf("failed to get monthly report %w", err)
+		}
+
+		for _, charge := range charges {
+			key := cell{month: monthsBetween(from, charge.date), service: sub.ServiceID}
+
+			if cells[key], err = cells[key].Add(charge.amount); err != nil {
+				return entity.MonthlyReport{}, fmt.Errorf("failed to get monthly report %w", err)
+			}
+		}
+
+		total, err := sumCharges(charges, currency)
+		if err != nil {
+			return entity.MonthlyReport{}, fmt.Errorf("failed to get monthly report %w", err)
+		}
+
+		if service.Amount, err = service.Amount.Add(total); err != nil {
+			return entity.MonthlyReport{}, fmt.Errorf("failed to get monthly report %w", err)
+		}
+
+		if report.Total, err = report.Total.Add(total); err != nil {
+			return entity.MonthlyReport{}, fmt.Errorf("failed to get monthly report %w", err)
+		}
+
+		services[sub.ServiceID] = service
+	}
+
+	for key, amount := range cells {
+		report.Rows = append(report.Rows, entity.MonthlyReportRow{
+			Month:       report.Months[key.month].Month,
+			ServiceID:   key.service,
+			ServiceName: services[key.service].ServiceName,
+			Amount:      amount,
+		})
+	}
+
+	for _, service := range services {
+		if service.Amount.Amount != 0 {
+			report.Services = append(report.Services, service)
+		}
+	}
+
+	slices.SortFunc(report.Rows, func(a, b entity.MonthlyReportRow) int {
+		return cmp.Or(cmp.Compare(a.Month, b.Month), cmp.Compare(a.ServiceName, b.ServiceName))
+	})
+
+	slices.SortFunc(report.Services, func(a, b entity.ServiceSum) int {
+		return cmp.Or(cmp.Compare(b.Amount.Amount, a.Amount.Amount), cmp.Compare(a.ServiceName, b.ServiceName))
+	})
+
+	return report, nil
+}
@@ -2,55 +2,203 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/logger"
+	"sort"
+	"time"
 
 	"github.com/gofrs/uuid/v5"
 )
 
-type Repo interface {
-	SubscriptionByID(context.Context, uuid.UUID) (entity.Subscription, error)
-	UpdateSubscription(context.Context, entity.Subscription) error
-	CreateSubscription(context.Context, entity.Subscription) (uuid.UUID, error)
-	DeleteSubscription(context.Context, uuid.UUID) error
-	SubscriptionsList(context.Context, uuid.UUID) ([]entity.Subscription, error)
-	SubscriptionsSum(ctx context.Context, params entity.SubscriptionsSumParams) (entity.UserSubscriptionsSum, error)
+// ExchangeRates converts amounts between currencies.
+type ExchangeRates interface {
+	// Rate returns how many units of to one unit of from buys on the given date.
+	Rate(ctx context.Context, from, to string, on time.Time) (float64, error)
+}
+
+// Alerts is told about the events the user should hear of right away.
+type Alerts interface {
+	// BudgetExceeded is called once a change to a subscription pushes the projected spending over the budget.
+	BudgetExceeded(ctx context.Context, status entity.BudgetStatus)
+	// TrialEnding is called once per trial end, when the trial is about to end.
+	TrialEnding(ctx context.Context, sub entity.Subscription)
+	// RenewalDue is called ahead of a charge, possibly more than once for the same one.
+	RenewalDue(ctx context.Context, renewal entity.Renewal)
 }
 
 type Service struct {
-	repo Repo
+	log      logger.Logger
+	repo     Repo
+	rates    ExchangeRates
+	alerts   Alerts
+	currency string
+	now      func() time.Time
 }
 
-func NewService(repo Repo) *Service {
+// NewService creates the service, currency is used for prices and sums that don't name one.
+func NewService(log logger.Logger, repo Repo, rates ExchangeRates, alerts Alerts, currency string) *Service {
 	return &Service{
-		repo: repo,
+		log:      log,
+		repo:     repo,
+		rates:    rates,
+		alerts:   alerts,
+		currency: currency,
+		now:      time.Now,
 	}
 }
 
-func (s *Service) UpdateSubscription(ctx context.Context, sub entity.Subscription) error {
-	_, err := s.repo.SubscriptionByID(ctx, sub.ID)
-	if err != nil {
-		return fmt.Errorf("service: failed to find subscription with id %s: %w", sub.ID, err)
+// UpdateSubscription changes the subscription unless it moved past sub.Version meanwhile, then it fails
+// with entity.ErrVersionMismatch. It returns the subscription as stored and the budgets the change pushed
+// over their limit, those of the previous owner too when the subscription moves to another user, alerts
+// are raised for each.
+func (s *Service) UpdateSubscription(ctx context.Context, sub entity.Subscription) (entity.Subscription, []entity.BudgetStatus, error) {
+	sub.BillingPeriod = billingPeriod(sub)
+	sub.Tags = entity.NormalizeTags(sub.Tags)
+
+	owners := []uuid.UUID{sub.UserID}
+	if stored, err := s.repo.SubscriptionByID(ctx, sub.ID); err == nil {
+		owners = append(owners, stored.UserID)
 	}
 
-	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
-		return fmt.Errorf("service: failed to update subscription: %w", err)
+	check := s.checkBudgets(ctx, owners...)
+
+	var changed *entity.Subscription
+
+	// No row lock is taken: the update below applies only to sub.Version, so a concurrent change
+	// makes it fail with entity.ErrVersionMismatch and the whole transaction roll back.
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		previous, err := s.storedSubscription(ctx, sub.ID)
+		if err != nil {
+			return err
+		}
+
+		current := *previous
+
+		if err := s.resolveService(ctx, &sub); err != nil {
+			return err
+		}
+
+		if sub.Price.Currency == "" {
+			sub.Price.Currency = s.currency
+		}
+
+		// The status only changes through ChangeStatus, so its history stays complete.
+		sub.Status = current.Status
+
+		if sub.TrialEnd != nil && sub.TrialStart == nil {
+			sub.TrialStart = &sub.StartDate
+		}
+
+		// A moved trial end has to be flagged again.
+		if sub.TrialEnd != nil && current.TrialEnd != nil && dateOf(*sub.TrialEnd).Equal(dateOf(*current.TrialEnd)) {
+			sub.TrialEndingFlaggedAt = current.TrialEndingFlaggedAt
+		} else {
+			sub.TrialEndingFlaggedAt = nil
+		}
+
+		if sub.Price != current.Price || sub.PriceEffectiveFrom != nil {
+			if sub.Price, err = s.changePrice(ctx, current, sub.Price, sub.PriceEffectiveFrom); err != nil {
+				return err
+			}
+		}
+
+		if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+			return fmt.Errorf("service: failed to update subscription: %w", err)
+		}
+
+		if err := s.repo.SetTags(ctx, sub.UserID, sub.ID, sub.Tags); err != nil {
+			return fmt.Errorf("service: failed to set tags: %w", err)
+		}
+
+		if changed, err = s.storedSubscription(ctx, sub.ID); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, entity.SubscriptionUpdated, previous, changed)
+	})
+	if err != nil {
+		return entity.Subscription{}, nil, err
 	}
 
-	return nil
+	return *changed, s.exceeded(ctx, check), nil
 }
 
-func (s *Service) CreateSubscription(ctx context.Context, sub entity.Subscription) (uuid.UUID, error) {
-	id, err := s.repo.CreateSubscription(ctx, sub)
+// CreateSubscription returns the user's budgets the new subscription pushed over their limit next to its ID,
+// alerts are raised for each.
+func (s *Service) CreateSubscription(ctx context.Context, sub entity.Subscription) (uuid.UUID, []entity.BudgetStatus, error) {
+	sub.BillingPeriod = billingPeriod(sub)
+	sub.Tags = entity.NormalizeTags(sub.Tags)
+
+	sub.Status = entity.StatusActive
+	sub.TrialEndingFlaggedAt = nil
+	sub.DeletedAt = nil
+	if sub.TrialEnd != nil && sub.TrialStart == nil {
+		sub.TrialStart = &sub.StartDate
+	}
+
+	check := s.checkBudgets(ctx, sub.UserID)
+
+	var id uuid.UUID
+
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.resolveService(ctx, &sub); err != nil {
+			return err
+		}
+
+		if sub.Price.Currency == "" {
+			sub.Price.Currency = s.currency
+		}
+
+		var err error
+		id, err = s.repo.CreateSubscription(ctx, sub)
+		if err != nil {
+			return err
+		}
+
+		err = s.repo.SetPrice(ctx, entity.PricePeriod{SubscriptionID: id, Price: sub.Price, EffectiveFrom: dateOf(sub.StartDate)})
+		if err != nil {
+			return fmt.Errorf("service: failed to record price: %w", err)
+		}
+
+		if len(sub.Tags) > 0 {
+			if err := s.repo.SetTags(ctx, sub.UserID, id, sub.Tags); err != nil {
+				return fmt.Errorf("service: failed to set tags: %w", err)
+			}
+		}
+
+		created, err := s.storedSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, entity.SubscriptionCreated, nil, created)
+	})
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, nil, err
 	}
 
-	return id, nil
+	return id, s.exceeded(ctx, check), nil
 }
 
-func (s *Service) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+// DeleteSubscription moves the subscription at the version to the trash, it can be restored until
+// the purge job removes it.
+func (s *Service) DeleteSubscription(ctx context.Context, id uuid.UUID, version int64) error {
+	// As in UpdateSubscription no row lock is taken, the delete applies only to the version.
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		deleted, err := s.storedSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.DeleteSubscription(ctx, id, version, s.now().UTC()); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, entity.SubscriptionDeleted, deleted, nil)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to delete subscription with id %s: %w", id, err)
 	}
 
@@ -63,26 +211,207 @@ func (s *Service) SubscriptionByID(ctx context.Context, id uuid.UUID) (entity.Su
 		return entity.Subscription{}, fmt.Errorf("failed to get subscription by id %s: %w", id, err)
 	}
 
-	return sub, nil
+	subs := []entity.Subscription{sub}
+	if err := s.withTags(ctx, subs); err != nil {
+		return entity.Subscription{}, fmt.Errorf("failed to get tags of subscription %s: %w", id, err)
+	}
+
+	return subs[0], nil
 }
 
-func (s *Service) SubscriptionsList(ctx context.Context, userID uuid.UUID) ([]entity.Subscription, error) {
+// SubscriptionsList returns a page of the user's subscriptions, when params.Period is set every
+// subscription also carries its price normalized to that period.
+func (s *Service) SubscriptionsList(ctx context.Context, params entity.SubscriptionsListParams) (entity.SubscriptionsPage, error) {
+	// One extra row tells whether there is a next page.
+	query := params
+	query.Limit++
+	query.Tags = entity.NormalizeTags(params.Tags)
 
-	subs, err := s.repo.SubscriptionsList(ctx, userID)
+	// The filter matches aliases too, subscriptions are stored under the canonical name.
+	if query.ServiceName != "" {
+		e, err := s.repo.CatalogEntryByName(ctx, query.ServiceName)
+		if err == nil {
+			query.ServiceName = e.Name
+		} else if !errors.Is(err, entity.ErrNotFound) {
+			return entity.SubscriptionsPage{}, fmt.Errorf("failed to get subscriptions list by userID %s: %w", params.UserID, err)
+		}
+	}
+
+	subs, err := s.repo.SubscriptionsList(ctx, query)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get subscriptions list by userID %s: %w", userID, err)
+		return entity.SubscriptionsPage{}, fmt.Errorf("failed to get subscriptions list by userID %s: %w", params.UserID, err)
+	}
+
+	page := entity.SubscriptionsPage{Items: subs}
+	if len(subs) > params.Limit {
+		page.Items = subs[:params.Limit]
+		page.NextCursor = entity.NewListCursor(params.Sort, params.Desc, page.Items[params.Limit-1]).Encode()
 	}
 
-	return subs, nil
+	if page.Items == nil {
+		page.Items = []entity.Subscription{}
+	}
+
+	if err := s.withTags(ctx, page.Items); err != nil {
+		return entity.SubscriptionsPage{}, fmt.Errorf("failed to get subscriptions list by userID %s: %w", params.UserID, err)
+	}
+
+	if params.Period != nil {
+		for i := range page.Items {
+			price, err := billingPeriod(page.Items[i]).Normalize(page.Items[i].Price, *params.Period)
+			if err != nil {
+				return entity.SubscriptionsPage{}, fmt.Errorf("failed to get subscriptions list by userID %s: %w", params.UserID, err)
+			}
+
+			page.Items[i].PeriodPrice = &price
+		}
+	}
+
+	return page, nil
 
 }
 
+// SearchSubscriptions returns a page of subscriptions of any user matching the filter
+// and the number of matching subscriptions on all pages.
+func (s *Service) SearchSubscriptions(ctx context.Context, filter entity.SubscriptionFilter) (entity.SubscriptionsPage, int, error) {
+	// One extra row tells whether there is a next page.
+	query := filter
+	query.Limit++
+
+	subs, total, err := s.repo.SearchSubscriptions(ctx, query)
+	if err != nil {
+		return entity.SubscriptionsPage{}, 0, fmt.Errorf("failed to search subscriptions: %w", err)
+	}
+
+	page := entity.SubscriptionsPage{Items: subs}
+	if len(subs) > filter.Limit {
+		page.Items = subs[:filter.Limit]
+		page.NextCursor = entity.NewListCursor(filter.Sort, filter.Desc, page.Items[filter.Limit-1]).Encode()
+	}
+
+	if page.Items == nil {
+		page.Items = []entity.Subscription{}
+	}
+
+	if err := s.withTags(ctx, page.Items); err != nil {
+		return entity.SubscriptionsPage{}, 0, fmt.Errorf("failed to search subscriptions: %w", err)
+	}
+
+	return page, total, nil
+}
+
+// SubscriptionsSum returns the amount the user was charged for the service within the requested period,
+// counting and converting charges as convertedCharges does; when the period has no end date it lasts
+// until today. A service name is looked up in the catalog, a name it doesn't know sums to zero.
 func (s *Service) SubscriptionsSum(ctx context.Context, params entity.SubscriptionsSumParams) (entity.UserSubscriptionsSum, error) {
-	subSum, err := s.repo.SubscriptionsSum(ctx, params)
+	if params.ServiceID.IsNil() {
+		var err error
+		if params.ServiceID, err = s.lookupService(ctx, params.ServiceName); err != nil {
+			return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
+		}
+	}
+
+	var subs []entity.Subscription
+	if !params.ServiceID.IsNil() {
+		var err error
+		if subs, err = s.repo.SubscriptionsInPeriod(ctx, params); err != nil {
+			return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
+		}
+	}
+
+	ledger, err := s.ledger(ctx, subs)
 	if err != nil {
 		return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
 	}
 
+	from := dateOf(params.StartDate)
+	to := dateOf(s.now())
+	if params.EndDate != nil {
+		to = dateOf(*params.EndDate)
+	}
+
+	currency := params.TargetCurrency
+	if currency == "" {
+		currency = s.currency
+	}
+
+	subSum := entity.UserSubscriptionsSum{
+		UserID:     params.UserID,
+		TotalPrice: entity.NewMoney(0, currency),
+		Months:     monthlyBreakdown(from, to, currency),
+		Original:   []entity.Money{},
+	}
+
+	if params.Period != nil {
+		subSum.PeriodPrice = &entity.Money{Currency: currency}
+	}
+
+	original := make(map[string]entity.Money)
+
+	for _, sub := range subs {
+		charges, err := s.convertedCharges(ctx, ledger, sub, from, to, currency)
+		if err != nil {
+			return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
+		}
+
+		if err := addMonthly(subSum.Months, from, charges); err != nil {
+			return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
+		}
+
+		for _, charge := range charges {
+			if subSum.TotalPrice, err = subSum.TotalPrice.Add(charge.amount); err != nil {
+				return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
+			}
+
+			if original[charge.price.Currency], err = original[charge.price.Currency].Add(charge.price); err != nil {
+				return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
+			}
+		}
+
+		if subSum.PeriodPrice != nil {
+			price := ledger.priceOn(sub, to)
+
+			price, err := billingPeriod(sub).Normalize(price, *params.Period)
+			if err != nil {
+				return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
+			}
+
+			amount, err := s.convert(ctx, price, currency, to)
+			if err != nil {
+				return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
+			}
+
+			if *subSum.PeriodPrice, err = subSum.PeriodPrice.Add(amount); err != nil {
+				return entity.UserSubscriptionsSum{}, fmt.Errorf("failed to get subscriptions sum %w", err)
+			}
+		}
+	}
+
+	for _, amount := range original {
+		subSum.Original = append(subSum.Original, amount)
+	}
+
+	sort.Slice(subSum.Original, func(i, j int) bool { return subSum.Original[i].Currency < subSum.Original[j].Currency })
+
 	return subSum, nil
 }
+
+// convert converts price to currency at the rate of the given date.
+func (s *Service) convert(ctx context.Context, price entity.Money, currency string, on time.Time) (entity.Money, error) {
+	if price.Currency == currency {
+		return price, nil
+	}
+
+	rate, err := s.rates.Rate(ctx, price.Currency, currency, on)
+	if err != nil {
+		return entity.Money{}, fmt.Errorf("convert %s to %s: %w", price.Currency, currency, err)
+	}
+
+	converted, err := price.Convert(rate, currency)
+	if err != nil {
+		return entity.Money{}, fmt.Errorf("convert %s to %s: %w", price.Currency, currency, err)
+	}
+
+	return converted, nil
+}
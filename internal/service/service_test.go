@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/internal/exchange"
+	"online-subscribe-rest-service/internal/repository/memory"
+	"online-subscribe-rest-service/pkg/logger"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// recordedAlerts keeps the alerts the service raised.
+type recordedAlerts struct {
+	mu       sync.Mutex
+	exceeded []entity.BudgetStatus
+}
+
+func (a *recordedAlerts) BudgetExceeded(_ context.Context, status entity.BudgetStatus) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.exceeded = append(a.exceeded, status)
+}
+
+func (a *recordedAlerts) TrialEnding(context.Context, entity.Subscription) {}
+func (a *recordedAlerts) RenewalDue(context.Context, entity.Renewal)       {}
+
+// newTestService returns a service over a memory store whose clock stands at now, a date.
+func newTestService(t *testing.T, now string) (*Service, *memory.SubscriptionRepo, *recordedAlerts) {
+	t.Helper()
+
+	log, err := logger.New("mock")
+	if err != nil {
+		t.Fatalf("logger.New() error = %v", err)
+	}
+
+	repo := memory.NewSubscriptionRepo()
+	alerts := &recordedAlerts{}
+
+	s := NewService(log, repo, exchange.NewStaticRates("RUB", nil), alerts, "RUB")
+	s.now = func() time.Time { return date(now).Add(12 * time.Hour) }
+
+	return s, repo, alerts
+}
+
+// monthly returns a monthly subscription of the user in RUB, end may be empty.
+func monthly(userID uuid.UUID, name string, minor int64, start, end string) entity.Subscription {
+	sub := entity.Subscription{
+		ServiceName: name,
+		Price:       entity.NewMoney(minor, "RUB"),
+		UserID:      userID,
+		StartDate:   date(start),
+	}
+
+	if end != "" {
+		e := date(end)
+		sub.EndDate = &e
+	}
+
+	return sub
+}
+
+func mustCreate(t *testing.T, s *Service, sub entity.Subscription) uuid.UUID {
+	t.Helper()
+
+	id, _, err := s.CreateSubscription(context.Background(), sub)
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+
+	return id
+}
+
+func TestPurgeSubscriptionsRecordsEvents(t *testing.T) {
+	s, repo, _ := newTestService(t, "2026-10-18")
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+	id := mustCreate(t, s, monthly(user, "Okko", 39900, "2026-01-01", ""))
+
+	if err := s.DeleteSubscription(ctx, id, 1); err != nil {
+		t.Fatalf("DeleteSubscription() error = %v", err)
+	}
+
+	// Not yet past the retention.
+	if purged, err := s.PurgeSubscriptions(ctx, time.Hour); err != nil || purged != 0 {
+		t.Fatalf("PurgeSubscriptions() = %d, %v, want 0", purged, err)
+	}
+
+	s.now = func() time.Time { return date("2026-10-20") }
+
+	if purged, err := s.PurgeSubscriptions(ctx, time.Hour); err != nil || purged != 1 {
+		t.Fatalf("PurgeSubscriptions() = %d, %v, want 1", purged, err)
+	}
+
+	events, err := repo.PendingOutboxEvents(ctx, s.now(), 10)
+	if err != nil {
+		t.Fatalf("PendingOutboxEvents() error = %v", err)
+	}
+
+	last := events[len(events)-1].Event
+	if last.Type != entity.SubscriptionPurged || last.SubscriptionID != id || last.Before == nil || last.After != nil {
+		t.Fatalf("last event = %s of %s, before %v, after %v, want %s of %s with only before",
+			last.Type, last.SubscriptionID, last.Before, last.After, entity.SubscriptionPurged, id)
+	}
+
+	entries, err := repo.AuditEntries(ctx, entity.AuditFilter{EntityID: &id, Limit: 10})
+	if err != nil {
+		t.Fatalf("AuditEntries() error = %v", err)
+	}
+
+	if len(entries) != 3 || entries[0].Action != entity.AuditPurge {
+		t.Fatalf("audit entries = %+v, want create, delete and purge", entries)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"slices"
+)
+
+// SpendingByCategory splits what the user was charged within the period for all of their subscriptions
+// by category and by tag. Without an end date the period lasts until today.
+func (s *Service) SpendingByCategory(ctx context.Context, params entity.SpendingParams) (entity.SpendingByCategory, error) {
+	from := dateOf(params.StartDate)
+	to := dateOf(s.now())
+	if params.EndDate != nil {
+		to = dateOf(*params.EndDate)
+	}
+
+	subs, ledger, err := s.userLedger(ctx, params.UserID, from, to)
+	if err != nil {
+		return entity.SpendingByCategory{}, fmt.Errorf("failed to get spending by category %w", err)
+	}
+
+	if err := s.withTags(ctx, subs); err != nil {
+		return entity.SpendingByCategory{}, fmt.Errorf("failed to get spending by category %w", err)
+	}
+
+	currency := params.TargetCurrency
+	if currency == "" {
+		currency = s.currency
+	}
+
+	spending := entity.SpendingByCategory{
+		UserID:     params.UserID,
+		Total:      entity.NewMoney(0, currency),
+		Categories: []entity.CategorySpending{},
+		Tags:       []entity.TagSpending{},
+		Untagged:   entity.NewMoney(0, currency),
+	}
+
+	byCategory := make(map[entity.Category]entity.Money)
+	byTag := make(map[string]entity.Money)
+
+	for _, sub := range subs {
+		charges, err := s.convertedCharges(ctx, ledger, sub, from, to, currency)
+		if err != nil {
+			return entity.SpendingByCategory{}, fmt.Errorf("failed to get spending by category %w", err)
+		}
+
+		total, err := sumCharges(charges, currency)
+		if err != nil {
+			return entity.SpendingByCategory{}, fmt.Errorf("failed to get spending by category %w", err)
+		}
+
+		if total.Amount == 0 {
+			continue
+		}
+
+		category := subscriptionCategory(sub)
+
+		if spending.Total, err = spending.Total.Add(total); err != nil {
+			return entity.SpendingByCategory{}, fmt.Errorf("failed to get spending by category %w", err)
+		}
+
+		if byCategory[category], err = byCategory[category].Add(total); err != nil {
+			return entity.SpendingByCategory{}, fmt.Errorf("failed to get spending by category %w", err)
+		}
+
+		if len(sub.Tags) == 0 {
+			if spending.Untagged, err = spending.Untagged.Add(total); err != nil {
+				return entity.SpendingByCategory{}, fmt.Errorf("failed to get spending by category %w", err)
+			}
+		}
+
+		for _, tag := range sub.Tags {
+			if byTag[tag], err = byTag[tag].Add(total); err != nil {
+				return entity.SpendingByCategory{}, fmt.Errorf("failed to get spending by category %w", err)
+			}
+		}
+	}
+
+	for category, amount := range byCategory {
+		spending.Categories = append(spending.Categories, entity.CategorySpending{Category: category, Amount: amount})
+	}
+
+	for tag, amount := range byTag {
+		spending.Tags = append(spending.Tags, entity.TagSpending{Tag: tag, Amount: amount})
+	}
+
+	slices.SortFunc(spending.Categories, func(a, b entity.CategorySpending) int {
+		return cmp.Or(cmp.Compare(b.Amount.Amount, a.Amount.Amount), cmp.Compare(a.Category, b.Category))
+	})
+
+	slices.SortFunc(spending.Tags, func(a, b entity.TagSpending) int {
+		return cmp.Or(cmp.Compare(b.Amount.Amount, a.Amount.Amount), cmp.Compare(a.Tag, b.Tag))
+	})
+
+	return spending, nil
+}
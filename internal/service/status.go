@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// ChangeStatus moves the subscription to a new lifecycle status effective on the given day
+// and records the transition in its history.
+//
+// Cancelling an active subscription keeps it paid until the end of the current billing period,
+// so its end date moves to the day before the next charge; a paused one ends right away.
+func (s *Service) ChangeStatus(ctx context.Context, id uuid.UUID, to entity.SubscriptionStatus, on time.Time) (entity.Subscription, error) {
+	on = dateOf(on)
+
+	var sub entity.Subscription
+
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+
+		sub, err = s.repo.SubscriptionForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("service: failed to find subscription with id %s: %w", id, err)
+		}
+
+		if err := sub.Status.CheckTransition(to); err != nil {
+			return fmt.Errorf("service: subscription %s: %w", id, err)
+		}
+
+		history, err := s.repo.StatusHistory(ctx, []uuid.UUID{id})
+		if err != nil {
+			return fmt.Errorf("service: failed to get status history of subscription %s: %w", id, err)
+		}
+
+		if on.Before(dateOf(sub.StartDate)) {
+			return fmt.Errorf("service: %w: %s takes effect before the subscription starts", entity.ErrInvalidTransition, to)
+		}
+
+		if len(history) > 0 && on.Before(history[len(history)-1].EffectiveDate) {
+			return fmt.Errorf("service: %w: %s takes effect before the previous status change", entity.ErrInvalidTransition, to)
+		}
+
+		if to == entity.StatusCancelled {
+			end := on
+			if sub.Status == entity.StatusActive {
+				end = nextChargeDate(sub, on).AddDate(0, 0, -1)
+			}
+
+			if sub.EndDate == nil || end.Before(*sub.EndDate) {
+				sub.EndDate = &end
+			}
+		}
+
+		previous, err := s.storedSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		change := entity.StatusChange{
+			SubscriptionID: id,
+			From:           sub.Status,
+			To:             to,
+			EffectiveDate:  on,
+			ChangedAt:      s.now().UTC(),
+		}
+
+		sub.Status = to
+
+		if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+			return fmt.Errorf("service: failed to update subscription: %w", err)
+		}
+
+		if err := s.repo.AddStatusChange(ctx, change); err != nil {
+			return fmt.Errorf("service: failed to record status change: %w", err)
+		}
+
+		changed, err := s.storedSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// The caller gets the subscription at its new version.
+		sub = *changed
+
+		return s.recordEvent(ctx, entity.SubscriptionUpdated, previous, changed)
+	})
+	if err != nil {
+		return entity.Subscription{}, err
+	}
+
+	return sub, nil
+}
+
+// ExpireSubscriptions moves every subscription that ended before today to the expired status,
+// effective on the day after its end date, and returns the expired subscriptions.
+func (s *Service) ExpireSubscriptions(ctx context.Context) ([]entity.Subscription, error) {
+	today := dateOf(s.now())
+
+	ended, err := s.repo.EndedSubscriptions(ctx, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ended subscriptions: %w", err)
+	}
+
+	var expired []entity.Subscription
+
+	for _, e := range ended {
+		sub, ok, err := s.expire(ctx, e.ID, today)
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire subscription %s: %w", e.ID, err)
+		}
+
+		if ok {
+			expired = append(expired, sub)
+		}
+	}
+
+	return expired, nil
+}
+
+// expire expires the subscription unless it was expired, prolonged or deleted meanwhile, ok reports whether it was.
+func (s *Service) expire(ctx context.Context, id uuid.UUID, today time.Time) (sub entity.Subscription, ok bool, err error) {
+	err = s.repo.WithTx(ctx, func(ctx context.Context) error {
+		sub, err = s.repo.SubscriptionForUpdate(ctx, id)
+		if errors.Is(err, entity.ErrNotFound) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if sub.Status == entity.StatusExpired || sub.EndDate == nil || !dateOf(*sub.EndDate).Before(today) {
+			return nil
+		}
+
+		previous, err := s.storedSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		history, err := s.repo.StatusHistory(ctx, []uuid.UUID{id})
+		if err != nil {
+			return err
+		}
+
+		on := dateOf(*sub.EndDate).AddDate(0, 0, 1)
+		if len(history) > 0 && on.Before(history[len(history)-1].EffectiveDate) {
+			on = history[len(history)-1].EffectiveDate
+		}
+
+		change := entity.StatusChange{
+			SubscriptionID: id,
+			From:           sub.Status,
+			To:             entity.StatusExpired,
+			EffectiveDate:  on,
+			ChangedAt:      s.now().UTC(),
+		}
+
+		sub.Status = entity.StatusExpired
+
+		if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+			return err
+		}
+
+		if err := s.repo.AddStatusChange(ctx, change); err != nil {
+			return err
+		}
+
+		changed, err := s.storedSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		ok = true
+
+		return s.recordEvent(ctx, entity.SubscriptionUpdated, previous, changed)
+	})
+
+	return sub, ok, err
+}
+
+// StatusHistory returns the subscription's status changes, oldest first.
+func (s *Service) StatusHistory(ctx context.Context, id uuid.UUID) ([]entity.StatusChange, error) {
+	if _, err := s.repo.SubscriptionByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to get subscription by id %s: %w", id, err)
+	}
+
+	history, err := s.repo.StatusHistory(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history of subscription %s: %w", id, err)
+	}
+
+	if history == nil {
+		history = []entity.StatusChange{}
+	}
+
+	return history, nil
+}
+
+// pausedIntervals returns the paused intervals of every subscription in subs.
+func (s *Service) pausedIntervals(ctx context.Context, subs []entity.Subscription) (map[uuid.UUID][]entity.Interval, error) {
+	if len(subs) == 0 {
+		return nil, nil
+	}
+
+	history, err := s.repo.StatusHistory(ctx, subscriptionIDs(subs))
+	if err != nil {
+		return nil, err
+	}
+
+	bySubscription := make(map[uuid.UUID][]entity.StatusChange)
+	for _, change := range history {
+		bySubscription[change.SubscriptionID] = append(bySubscription[change.SubscriptionID], change)
+	}
+
+	intervals := make(map[uuid.UUID][]entity.Interval, len(bySubscription))
+	for id, changes := range bySubscription {
+		intervals[id] = entity.PausedIntervals(changes)
+	}
+
+	return intervals, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"online-subscribe-rest-service/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestPauseSkipsCharges(t *testing.T) {
+	s, _, _ := newTestService(t, "2026-10-18")
+	ctx := context.Background()
+
+	user := uuid.Must(uuid.NewV4())
+	id := mustCreate(t, s, monthly(user, "Okko", 39900, "2026-01-15", ""))
+
+	// Paused from March 1st to May 1st: the March 15th and April 15th charges are skipped.
+	if _, err := s.ChangeStatus(ctx, id, entity.StatusPaused, date("2026-03-01")); err != nil {
+		t.Fatalf("ChangeStatus(paused) error = %v", err)
+	}
+
+	if _, err := s.ChangeStatus(ctx, id, entity.StatusActive, date("2026-05-01")); err != nil {
+		t.Fatalf("ChangeStatus(active) error = %v", err)
+	}
+
+	end := date("2026-06-30")
+
+	sum, err := s.SubscriptionsSum(ctx, entity.SubscriptionsSumParams{UserID: user, ServiceName: "Okko", StartDate: date("2026-01-01"), EndDate: &end})
+	if err != nil {
+		t.Fatalf("SubscriptionsSum() error = %v", err)
+	}
+
+	if want := entity.NewMoney(4*39900, "RUB"); sum.TotalPrice != want {
+		t.Errorf("SubscriptionsSum() total = %v, want %v", sum.TotalPrice, want)
+	}
+
+	wantMonths := []int64{39900, 39900, 0, 0, 39900, 39900}
+	if len(sum.Months) != len(wantMonths) {
+		t.Fatalf("SubscriptionsSum() = %d months, want %d", len(sum.Months), len(wantMonths))
+	}
+
+	for i, month := range sum.Months {
+		if want := entity.NewMoney(wantMonths[i], "RUB"); month.Amount != want {
+			t.Errorf("SubscriptionsSum() %s = %v, want %v", month.Month, month.Amount, want)
+		}
+	}
+
+	renewals, err := s.Renewals(ctx, entity.RenewalsParams{UserID: user, From: date("2026-01-01"), To: end})
+	if err != nil {
+		t.Fatalf("Renewals() error = %v", err)
+	}
+
+	want := dates("2026-01-15", "2026-02-15", "2026-05-15", "2026-06-15")
+	if len(renewals) != len(want) {
+		t.Fatalf("Renewals() = %d renewals, want %d", len(renewals), len(want))
+	}
+
+	for i, r := range renewals {
+		if !r.Date.Equal(want[i]) {
+			t.Errorf("renewal %d on %s, want %s", i, r.Date.Format(time.DateOnly), want[i].Format(time.DateOnly))
+		}
+	}
+}
+
+func TestCancelEndDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		end     string
+		pauseOn string
+		on      string
+		want    string
+	}{
+		{name: "active runs to the end of the paid period", on: "2026-03-20", want: "2026-04-14"},
+		{name: "on a billing date the new period is paid", on: "2026-03-15", want: "2026-04-14"},
+		{name: "an earlier end date stays", end: "2026-03-31", on: "2026-03-20", want: "2026-03-31"},
+		{name: "paused ends right away", pauseOn: "2026-03-01", on: "2026-03-20", want: "2026-03-20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newTestService(t, "2026-10-18")
+			ctx := context.Background()
+
+			id := mustCreate(t, s, monthly(uuid.Must(uuid.NewV4()), "Okko", 39900, "2026-01-15", tt.end))
+
+			if tt.pauseOn != "" {
+				if _, err := s.ChangeStatus(ctx, id, entity.StatusPaused, date(tt.pauseOn)); err != nil {
+					t.Fatalf("ChangeStatus(paused) error = %v", err)
+				}
+			}
+
+			sub, err := s.ChangeStatus(ctx, id, entity.StatusCancelled, date(tt.on))
+			if err != nil {
+				t.Fatalf("ChangeStatus(cancelled) error = %v", err)
+			}
+
+			if sub.Status != entity.StatusCancelled || sub.EndDate == nil || !sub.EndDate.Equal(date(tt.want)) {
+				t.Errorf("cancelled subscription: status %s, end date %v, want cancelled and %s", sub.Status, sub.EndDate, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeStatusRejectsEarlierDates(t *testing.T) {
+	s, _, _ := newTestService(t, "2026-10-18")
+	ctx := context.Background()
+
+	id := mustCreate(t, s, monthly(uuid.Must(uuid.NewV4()), "Okko", 39900, "2026-01-15", ""))
+
+	if _, err := s.ChangeStatus(ctx, id, entity.StatusPaused, date("2026-01-01")); !errors.Is(err, entity.ErrInvalidTransition) {
+		t.Fatalf("ChangeStatus() before the start error = %v, want %v", err, entity.ErrInvalidTransition)
+	}
+
+	if _, err := s.ChangeStatus(ctx, id, entity.StatusPaused, date("2026-03-01")); err != nil {
+		t.Fatalf("ChangeStatus(paused) error = %v", err)
+	}
+
+	if _, err := s.ChangeStatus(ctx, id, entity.StatusActive, date("2026-02-01")); !errors.Is(err, entity.ErrInvalidTransition) {
+		t.Fatalf("ChangeStatus() before the previous change error = %v, want %v", err, entity.ErrInvalidTransition)
+	}
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"online-subscribe-rest-service/internal/entity"
+)
+
+// withTags fills in the tags of every subscription in subs.
+func (s *Service) withTags(ctx context.Context, subs []entity.Subscription) error {
+	if len(subs) == 0 {
+		return nil
+	}
+
+	tags, err := s.repo.SubscriptionTags(ctx, subscriptionIDs(subs))
+	if err != nil {
+		return err
+	}
+
+	for i := range subs {
+		subs[i].Tags = tags[subs[i].ID]
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// RestoreSubscription takes the subscription out of the trash.
+func (s *Service) RestoreSubscription(ctx context.Context, id uuid.UUID) (entity.Subscription, error) {
+	var restored *entity.Subscription
+
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.RestoreSubscription(ctx, id); err != nil {
+			return err
+		}
+
+		var err error
+		if restored, err = s.storedSubscription(ctx, id); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, entity.SubscriptionRestored, nil, restored)
+	})
+	if err != nil {
+		return entity.Subscription{}, fmt.Errorf("failed to restore subscription with id %s: %w", id, err)
+	}
+
+	return *restored, nil
+}
+
+// DeletedSubscriptions returns up to limit of the user's subscriptions in the trash, the latest deleted first.
+func (s *Service) DeletedSubscriptions(ctx context.Context, userID uuid.UUID, limit int) ([]entity.Subscription, error) {
+	subs, err := s.repo.DeletedSubscriptions(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted subscriptions of user %s: %w", userID, err)
+	}
+
+	if subs == nil {
+		return []entity.Subscription{}, nil
+	}
+
+	if err := s.withTags(ctx, subs); err != nil {
+		return nil, fmt.Errorf("failed to get tags of deleted subscriptions: %w", err)
+	}
+
+	return subs, nil
+}
+
+// PurgeSubscriptions removes for good the subscriptions that lay in the trash longer than retention
+// and returns how many were removed. Each removal is published and audited as subscription.purged.
+func (s *Service) PurgeSubscriptions(ctx context.Context, retention time.Duration) (int, error) {
+	var purged []entity.Subscription
+
+	err := s.repo.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if purged, err = s.repo.PurgeSubscriptions(ctx, s.now().UTC().Add(-retention)); err != nil {
+			return err
+		}
+
+		for i := range purged {
+			if err := s.recordEvent(ctx, entity.SubscriptionPurged, &purged[i], nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted subscriptions: %w", err)
+	}
+
+	return len(purged), nil
+}
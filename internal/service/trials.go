@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// FlagEndingTrials flags the active subscriptions whose free trial ends within the next days,
+// today included, so users can cancel them before the first charge. Every subscription is flagged
+// once per trial end and raises an alert; the newly flagged ones are returned.
+func (s *Service) FlagEndingTrials(ctx context.Context, days int) ([]entity.Subscription, error) {
+	today := dateOf(s.now())
+	until := today.AddDate(0, 0, days)
+
+	ending, err := s.repo.EndingTrials(ctx, today, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ending trials: %w", err)
+	}
+
+	var flagged []entity.Subscription
+
+	for _, e := range ending {
+		sub, ok, err := s.flagTrialEnding(ctx, e.ID, today, until)
+		if err != nil {
+			return flagged, fmt.Errorf("failed to flag ending trial of subscription %s: %w", e.ID, err)
+		}
+
+		if ok {
+			flagged = append(flagged, sub)
+			s.alerts.TrialEnding(ctx, sub)
+		}
+	}
+
+	return flagged, nil
+}
+
+// flagTrialEnding flags the subscription unless it was flagged, changed or deleted meanwhile so its trial
+// no longer ends within [from, to], ok reports whether it was. The subscription is locked first, so
+// concurrent callers never flag it twice.
+func (s *Service) flagTrialEnding(ctx context.Context, id uuid.UUID, from, to time.Time) (sub entity.Subscription, ok bool, err error) {
+	err = s.repo.WithTx(ctx, func(ctx context.Context) error {
+		current, err := s.repo.SubscriptionForUpdate(ctx, id)
+		if errors.Is(err, entity.ErrNotFound) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if current.TrialEndingFlaggedAt != nil || current.Status != entity.StatusActive || current.TrialEnd == nil ||
+			dateOf(*current.TrialEnd).Before(from) || dateOf(*current.TrialEnd).After(to) {
+			return nil
+		}
+
+		// The snapshot is taken before the change, so the audit log sees the version move too.
+		previous, err := s.storedSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		flaggedAt := s.now().UTC()
+		current.TrialEndingFlaggedAt = &flaggedAt
+
+		if err := s.repo.UpdateSubscription(ctx, current); err != nil {
+			return err
+		}
+
+		changed, err := s.storedSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		sub, ok = *changed, true
+
+		return s.recordEvent(ctx, entity.SubscriptionUpdated, previous, changed)
+	})
+
+	return sub, ok, err
+}
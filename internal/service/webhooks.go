@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// CreateWebhook registers an enabled webhook.
+func (s *Service) CreateWebhook(ctx context.Context, w entity.Webhook) (uuid.UUID, error) {
+	w.Enabled = true
+	w.Failures = 0
+	w.DisabledAt = nil
+	w.CreatedAt = s.now().UTC()
+
+	id, err := s.repo.CreateWebhook(ctx, w)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return id, nil
+}
+
+// WebhookByID returns the webhook without its secret.
+func (s *Service) WebhookByID(ctx context.Context, id uuid.UUID) (entity.Webhook, error) {
+	w, err := s.repo.WebhookByID(ctx, id)
+	if err != nil {
+		return entity.Webhook{}, fmt.Errorf("failed to get webhook by id %s: %w", id, err)
+	}
+
+	w.Secret = ""
+
+	return w, nil
+}
+
+// Webhooks returns every webhook without its secret, oldest first.
+func (s *Service) Webhooks(ctx context.Context) ([]entity.Webhook, error) {
+	webhooks, err := s.repo.Webhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.DeleteWebhook(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// EnableWebhook turns a webhook disabled after failing too often back on, the deliveries that
+// were waiting for it go out again.
+func (s *Service) EnableWebhook(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.EnableWebhook(ctx, id); err != nil {
+		return fmt.Errorf("failed to enable webhook %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// WebhookDeliveries returns the webhook's latest deliveries, newest first.
+func (s *Service) WebhookDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]entity.WebhookDelivery, error) {
+	if _, err := s.repo.WebhookByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to get deliveries of webhook %s: %w", id, err)
+	}
+
+	deliveries, err := s.repo.WebhookDeliveries(ctx, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries of webhook %s: %w", id, err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,81 @@
+// Package webhooks delivers subscription events to the endpoints integrators register,
+// signing every request and retrying the failed ones.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/internal/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Store keeps the webhooks and their deliveries, the service manages the webhooks through service.WebhookRepo.
+type Store interface {
+	WebhookByID(ctx context.Context, id uuid.UUID) (entity.Webhook, error)
+	// Webhooks returns every webhook, oldest first.
+	Webhooks(ctx context.Context) ([]entity.Webhook, error)
+	// WebhookSucceeded resets the count of the webhook's failures in a row.
+	WebhookSucceeded(ctx context.Context, id uuid.UUID) error
+	// WebhookFailed counts a failed attempt and disables the webhook at disableAfter failures in a row,
+	// it reports whether this failure disabled the webhook.
+	WebhookFailed(ctx context.Context, id uuid.UUID, disableAfter int, at time.Time) (bool, error)
+	// CreateWebhookDelivery fails with entity.ErrAlreadyExists when the event was already queued for the webhook.
+	CreateWebhookDelivery(ctx context.Context, d entity.WebhookDelivery) (uuid.UUID, error)
+	// UpdateWebhookDelivery records the outcome of the latest attempt.
+	UpdateWebhookDelivery(ctx context.Context, d entity.WebhookDelivery) error
+	// DueWebhookDeliveries returns up to limit pending deliveries to enabled webhooks due by now, due first go first.
+	DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error)
+}
+
+// Publisher is the outbox.Publisher that queues a delivery of the event for every enabled webhook
+// that wants it. The Sender delivers them, so a slow endpoint never holds the outbox up.
+type Publisher struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewPublisher(store Store) *Publisher {
+	return &Publisher{store: store, now: time.Now}
+}
+
+// Publish queues the deliveries of the event. An event published again is queued once per webhook.
+func (p *Publisher) Publish(ctx context.Context, event entity.SubscriptionEvent) error {
+	webhooks, err := p.store.Webhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to list webhooks: %w", err)
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to encode event %s: %w", event.ID, err)
+	}
+
+	now := p.now().UTC()
+
+	for _, w := range webhooks {
+		if !w.Enabled || !w.Wants(event) {
+			continue
+		}
+
+		d := entity.WebhookDelivery{
+			WebhookID:     w.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        entity.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		if _, err := p.store.CreateWebhookDelivery(ctx, d); err != nil && !errors.Is(err, entity.ErrAlreadyExists) {
+			return fmt.Errorf("webhooks: failed to queue event %s for webhook %s: %w", event.ID, w.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/pkg/logger"
+	"online-subscribe-rest-service/pkg/publicnet"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Locker keeps replicas from sending at the same time, see scheduler.Locker.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// maxBackoff caps the pause before a failed delivery is tried again.
+const maxBackoff = 6 * time.Hour
+
+// batchSize is how many due deliveries the sender reads at once.
+const batchSize = 100
+
+type Options struct {
+	// Timeout bounds a single attempt.
+	Timeout time.Duration
+	// MaxAttempts is how many times a delivery is tried before it is given up on.
+	MaxAttempts int
+	// Backoff is the pause after the first failed attempt, doubled with every failure.
+	Backoff time.Duration
+	// DisableAfter is how many attempts a webhook may fail in a row before it is disabled.
+	DisableAfter int
+	// PollInterval is how often the sender looks for due deliveries.
+	PollInterval time.Duration
+	// Concurrency is how many webhooks are posted to at once, the deliveries of one webhook go one by one.
+	Concurrency int
+}
+
+// Sender posts the queued deliveries to their webhooks. A request carries the event as JSON and
+// the X-Signature header, the hex HMAC-SHA256 of the body keyed with the webhook's secret.
+type Sender struct {
+	log    logger.Logger
+	store  Store
+	locker Locker
+	client *http.Client
+	opts   Options
+	now    func() time.Time
+}
+
+// NewSender creates the sender, locker may be nil, see scheduler.Locker.
+func NewSender(log logger.Logger, store Store, locker Locker, opts Options) *Sender {
+	return &Sender{
+		log:    log,
+		store:  store,
+		locker: locker,
+		client: &http.Client{Timeout: opts.Timeout, Transport: publicnet.Transport()},
+		opts:   opts,
+		now:    time.Now,
+	}
+}
+
+// Run sends the due deliveries until ctx is done.
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sendDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendDue sends the due deliveries, batch after batch while whole batches are due. The webhooks of
+// a batch are posted to in parallel, so a slow endpoint holds up only its own deliveries.
+func (s *Sender) sendDue(ctx context.Context) {
+	if s.locker != nil {
+		unlock, ok, err := s.locker.TryLock(ctx, "webhooks")
+		if err != nil {
+			s.log.ErrorF("webhooks: failed to take lock: %v", err)
+			return
+		}
+
+		// Another replica is sending.
+		if !ok {
+			return
+		}
+
+		defer unlock()
+	}
+
+	for ctx.Err() == nil {
+		deliveries, err := s.store.DueWebhookDeliveries(ctx, s.now().UTC(), batchSize)
+		if err != nil {
+			s.log.ErrorF("webhooks: failed to read due deliveries: %v", err)
+			return
+		}
+
+		var (
+			order     []uuid.UUID
+			byWebhook = make(map[uuid.UUID][]entity.WebhookDelivery)
+		)
+
+		for _, d := range deliveries {
+			if _, ok := byWebhook[d.WebhookID]; !ok {
+				order = append(order, d.WebhookID)
+			}
+
+			byWebhook[d.WebhookID] = append(byWebhook[d.WebhookID], d)
+		}
+
+		var (
+			wg     sync.WaitGroup
+			failed atomic.Bool
+			slots  = make(chan struct{}, max(s.opts.Concurrency, 1))
+		)
+
+		for _, id := range order {
+			slots <- struct{}{}
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				if err := s.deliverAll(ctx, id, byWebhook[id]); err != nil {
+					s.log.ErrorF("webhooks: failed to read webhook %s: %v", id, err)
+					failed.Store(true)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		// The deliveries of a webhook that couldn't be read are still due, the next poll retries them.
+		if len(deliveries) < batchSize || failed.Load() {
+			return
+		}
+	}
+}
+
+// deliverAll makes an attempt at each of the webhook's deliveries in order, it stops when the webhook
+// gets disabled. It fails only when the webhook can't be read.
+func (s *Sender) deliverAll(ctx context.Context, id uuid.UUID, deliveries []entity.WebhookDelivery) error {
+	w, err := s.webhook(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deliveries {
+		// The webhook was disabled by an earlier delivery or deleted meanwhile.
+		if w == nil || !w.Enabled || ctx.Err() != nil {
+			return nil
+		}
+
+		s.deliver(ctx, w, d)
+	}
+
+	return nil
+}
+
+// webhook reads the webhook, it is nil when the webhook is gone.
+func (s *Sender) webhook(ctx context.Context, id uuid.UUID) (*entity.Webhook, error) {
+	w, err := s.store.WebhookByID(ctx, id)
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// deliver makes an attempt at the delivery and records its outcome on the delivery and the webhook.
+func (s *Sender) deliver(ctx context.Context, w *entity.Webhook, d entity.WebhookDelivery) {
+	status, err := s.post(ctx, w, d)
+
+	now := s.now().UTC()
+
+	d.Attempts++
+	d.ResponseStatus = status
+	d.UpdatedAt = now
+
+	if err == nil {
+		d.Status = entity.DeliverySent
+		d.LastError = ""
+
+		if err := s.store.WebhookSucceeded(ctx, w.ID); err != nil {
+			s.log.ErrorF("webhooks: failed to record success of webhook %s: %v", w.ID, err)
+		}
+	} else {
+		d.LastError = err.Error()
+
+		if d.Attempts >= s.opts.MaxAttempts {
+			d.Status = entity.DeliveryFailed
+
+			s.log.WarnF("webhooks: gave up delivering event %s to webhook %s after %d attempts: %v", d.EventID, w.ID, d.Attempts, err)
+		} else {
+			d.NextAttemptAt = now.Add(s.retryDelay(d.Attempts))
+
+			s.log.WarnF("webhooks: failed to deliver event %s to webhook %s, retrying at %s: %v",
+				d.EventID, w.ID, d.NextAttemptAt.Format(time.RFC3339), err)
+		}
+
+		disabled, err := s.store.WebhookFailed(ctx, w.ID, s.opts.DisableAfter, now)
+		if err != nil {
+			s.log.ErrorF("webhooks: failed to record failure of webhook %s: %v", w.ID, err)
+		}
+
+		if disabled {
+			w.Enabled = false
+
+			s.log.WarnW("webhook disabled", map[string]any{
+				"webhook_id": w.ID.String(),
+				"url":        w.URL,
+				"failures":   s.opts.DisableAfter,
+			})
+		}
+	}
+
+	if err := s.store.UpdateWebhookDelivery(ctx, d); err != nil {
+		s.log.ErrorF("webhooks: failed to record delivery %s: %v", d.ID, err)
+	}
+}
+
+// post sends the delivery and returns the status of the response, 0 when there was none.
+// Any status but 2xx is a failure.
+func (s *Sender) post(ctx context.Context, w *entity.Webhook, d entity.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", w.ID.String())
+	req.Header.Set("X-Event-ID", d.EventID.String())
+	req.Header.Set("X-Event-Type", string(d.EventType))
+	req.Header.Set("X-Signature", Sign(w.Secret, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("post event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Reading a bit of the body lets the connection be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("post event: unexpected status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// retryDelay is the pause after the given number of failed attempts.
+func (s *Sender) retryDelay(attempts int) time.Duration {
+	delay := s.opts.Backoff
+
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
+
+// Sign returns the X-Signature of the body: "sha256=" and the hex HMAC-SHA256 of the body keyed with the secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
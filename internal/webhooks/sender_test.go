@@ -0,0 +1,207 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"online-subscribe-rest-service/internal/entity"
+	"online-subscribe-rest-service/internal/repository/memory"
+	"online-subscribe-rest-service/pkg/logger"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// newTestSender returns a sender over a memory store whose client reaches the test servers.
+func newTestSender(t *testing.T, opts Options) (*Sender, *memory.SubscriptionRepo) {
+	t.Helper()
+
+	log, err := logger.New("mock")
+	if err != nil {
+		t.Fatalf("logger.New() error = %v", err)
+	}
+
+	store := memory.NewSubscriptionRepo()
+
+	s := NewSender(log, store, nil, opts)
+	s.client = &http.Client{Timeout: opts.Timeout}
+
+	return s, store
+}
+
+// queue registers a webhook at url and queues a delivery of one event for it.
+func queue(t *testing.T, store Store, url, secret string, payload string) entity.WebhookDelivery {
+	t.Helper()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	user := uuid.Must(uuid.NewV4())
+
+	id, err := store.(interface {
+		CreateWebhook(context.Context, entity.Webhook) (uuid.UUID, error)
+	}).CreateWebhook(ctx, entity.Webhook{URL: url, UserID: &user, Secret: secret, Enabled: true, CreatedAt: now})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+
+	d := entity.WebhookDelivery{
+		WebhookID:     id,
+		EventID:       uuid.Must(uuid.NewV4()),
+		EventType:     entity.SubscriptionCreated,
+		Payload:       []byte(payload),
+		Status:        entity.DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if d.ID, err = store.CreateWebhookDelivery(ctx, d); err != nil {
+		t.Fatalf("CreateWebhookDelivery() error = %v", err)
+	}
+
+	return d
+}
+
+func TestSendDueDoesNotWaitForSlowWebhooks(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server notices the client is gone only once the body was read.
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	fastDone := make(chan struct{})
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(fastDone)
+	}))
+	defer fast.Close()
+
+	s, store := newTestSender(t, Options{Timeout: 10 * time.Second, MaxAttempts: 3, Backoff: time.Second, DisableAfter: 5, Concurrency: 2})
+
+	queue(t, store, slow.URL, "0123456789abcdef", `{"n":1}`)
+	queue(t, store, fast.URL, "0123456789abcdef", `{"n":2}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		s.sendDue(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-fastDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the fast webhook waited for the slow one")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		body   string
+		want   string
+	}{
+		{
+			name:   "known vector",
+			secret: "key",
+			body:   "The quick brown fox jumps over the lazy dog",
+			want:   "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		},
+		{
+			name:   "empty body",
+			secret: "key",
+			body:   "",
+			want:   "sha256=5d5d139563c95b5967b9bd9a8c9b233a9dedb45072794cd232dc1b74832607d0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostSignsThePayload(t *testing.T) {
+	const secret = "0123456789abcdef"
+
+	type request struct {
+		body      []byte
+		signature string
+		eventID   string
+	}
+
+	got := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- request{body: body, signature: r.Header.Get("X-Signature"), eventID: r.Header.Get("X-Event-ID")}
+	}))
+	defer srv.Close()
+
+	s, store := newTestSender(t, Options{Timeout: 5 * time.Second, MaxAttempts: 3, Backoff: time.Second, DisableAfter: 5, Concurrency: 1})
+
+	d := queue(t, store, srv.URL, secret, `{"type":"subscription.created"}`)
+
+	s.sendDue(context.Background())
+
+	var req request
+	select {
+	case req = <-got:
+	default:
+		t.Fatal("the webhook was not called")
+	}
+
+	if string(req.body) != string(d.Payload) {
+		t.Errorf("body = %s, want %s", req.body, d.Payload)
+	}
+
+	if req.eventID != d.EventID.String() {
+		t.Errorf("X-Event-ID = %s, want %s", req.eventID, d.EventID)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(req.body)
+
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); !hmac.Equal([]byte(req.signature), []byte(want)) {
+		t.Errorf("X-Signature = %s, want %s", req.signature, want)
+	}
+}
+
+func TestSenderRefusesLoopback(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	log, err := logger.New("mock")
+	if err != nil {
+		t.Fatalf("logger.New() error = %v", err)
+	}
+
+	s := NewSender(log, memory.NewSubscriptionRepo(), nil, Options{Timeout: 5 * time.Second})
+
+	w := &entity.Webhook{ID: uuid.Must(uuid.NewV4()), URL: srv.URL, Secret: "0123456789abcdef"}
+	d := entity.WebhookDelivery{EventID: uuid.Must(uuid.NewV4()), EventType: entity.SubscriptionCreated, Payload: []byte(`{}`)}
+
+	if _, err := s.post(context.Background(), w, d); err == nil || !strings.Contains(err.Error(), "not public") {
+		t.Errorf("post() error = %v, want the address refused", err)
+	}
+
+	if called {
+		t.Error("the loopback server was called")
+	}
+}
@@ -2,5 +2,12 @@ package migrations
 
 import "embed"
 
-//go:embed *.sql
-var FS embed.FS
\ No newline at end of file
+// Postgres holds the migrations of the Postgres schema under postgres/.
+//
+//go:embed postgres/*.sql
+var Postgres embed.FS
+
+// SQLite holds the same schema written for SQLite under sqlite/.
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Prices used to be whole units of the currency, they are now stored in minor units (kopecks, cents).
+-- The old integer column couldn't hold fractions, so every stored price is taken to be a whole amount
+-- and scaled by the currency's minor unit, the same table entity.MinorUnits uses.
+alter table subscriptions
+   alter column price type bigint using price::bigint * case
+      when currency in ('JPY', 'KRW', 'VND', 'CLP', 'ISK', 'UGX') then 1
+      when currency in ('BHD', 'KWD', 'OMR', 'JOD', 'TND', 'LYD', 'IQD') then 1000
+      else 100
+   end;
+
+alter table subscriptions
+   rename column price to price_minor;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+-- Fractions of a unit are lost on the way back.
+alter table subscriptions
+   rename column price_minor to price;
+
+alter table subscriptions
+   alter column price type int using (price / case
+      when currency in ('JPY', 'KRW', 'VND', 'CLP', 'ISK', 'UGX') then 1
+      when currency in ('BHD', 'KWD', 'OMR', 'JOD', 'TND', 'LYD', 'IQD') then 1000
+      else 100
+   end)::int;
+
+-- +goose StatementEnd
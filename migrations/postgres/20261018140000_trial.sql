@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table subscriptions
+   add column trial_start date,
+   add column trial_end date,
+   add column trial_ending_flagged_at timestamptz,
+   add constraint subscriptions_trial_check check (trial_end >= trial_start);
+
+create index subscriptions_trial_end_idx on subscriptions (trial_end)
+where
+   trial_ending_flagged_at is null;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+alter table subscriptions
+   drop column trial_ending_flagged_at,
+   drop column trial_end,
+   drop column trial_start;
+
+-- +goose StatementEnd
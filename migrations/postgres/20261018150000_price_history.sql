@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+create table
+   subscription_prices (
+      subscription_id uuid not null references subscriptions (id) on delete cascade,
+      price_minor bigint not null check (price_minor > 0),
+      currency text not null,
+      effective_from date not null,
+      primary key (subscription_id, effective_from)
+   );
+
+-- Existing subscriptions have had their current price since they started.
+insert into
+   subscription_prices (subscription_id, price_minor, currency, effective_from)
+select
+   id,
+   price_minor,
+   currency,
+   start_date
+from
+   subscriptions;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table subscription_prices;
+
+-- +goose StatementEnd
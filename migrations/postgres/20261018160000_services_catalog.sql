@@ -0,0 +1,89 @@
+-- +goose Up
+-- +goose StatementBegin
+create table
+   services (
+      id uuid primary key,
+      name text not null,
+      category text not null default '',
+      homepage text not null default '',
+      default_price_minor bigint check (default_price_minor > 0),
+      default_currency text,
+      check ((default_price_minor is null) = (default_currency is null))
+   );
+
+-- service_names holds the canonical name (position 0) and the aliases of every service.
+-- name_key is the lower-cased trimmed name, so one name never points to two services.
+create table
+   service_names (
+      name_key text primary key,
+      service_id uuid not null references services (id) on delete cascade,
+      name text not null,
+      position int not null
+   );
+
+create index service_names_service_id_idx on service_names (service_id, position);
+
+-- Every distinct free-text name becomes a catalog entry, spellings that differ only in case
+-- or surrounding spaces share one named after the most used spelling.
+insert into
+   services (id, name)
+select
+   gen_random_uuid(),
+   service_name
+from
+   (
+      select
+         service_name,
+         row_number() over (
+            partition by
+               lower(btrim(service_name))
+            order by
+               count(*) desc,
+               service_name
+         ) as rank
+      from
+         subscriptions
+      group by
+         service_name
+   ) spellings
+where
+   rank = 1;
+
+insert into
+   service_names (name_key, service_id, name, position)
+select
+   lower(btrim(name)),
+   id,
+   name,
+   0
+from
+   services;
+
+alter table subscriptions
+   add column service_id uuid references services (id);
+
+update subscriptions s
+set
+   service_id = n.service_id,
+   service_name = n.name
+from
+   service_names n
+where
+   n.name_key = lower(btrim(s.service_name));
+
+alter table subscriptions
+   alter column service_id set not null;
+
+create index subscriptions_service_id_idx on subscriptions (service_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+alter table subscriptions
+   drop column service_id;
+
+drop table service_names;
+
+drop table services;
+
+-- +goose StatementEnd
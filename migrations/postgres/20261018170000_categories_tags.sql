@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Catalog categories become the fixed taxonomy, free-text values that aren't part of it are dropped.
+update services
+set
+   category = ''
+where
+   category not in ('streaming', 'music', 'cloud', 'software', 'gaming', 'news', 'education', 'fitness', 'other');
+
+alter table services
+   add constraint services_category_check check (
+      category in ('', 'streaming', 'music', 'cloud', 'software', 'gaming', 'news', 'education', 'fitness', 'other')
+   );
+
+alter table subscriptions
+   add column category text not null default 'other' check (
+      category in ('streaming', 'music', 'cloud', 'software', 'gaming', 'news', 'education', 'fitness', 'other')
+   );
+
+update subscriptions s
+set
+   category = sv.category
+from
+   services sv
+where
+   sv.id = s.service_id
+   and sv.category <> '';
+
+create index subscriptions_user_id_category_idx on subscriptions (user_id, category);
+
+create table
+   tags (
+      id uuid primary key,
+      user_id uuid not null,
+      name text not null,
+      unique (user_id, name)
+   );
+
+create table
+   subscription_tags (
+      subscription_id uuid not null references subscriptions (id) on delete cascade,
+      tag_id uuid not null references tags (id) on delete cascade,
+      primary key (subscription_id, tag_id)
+   );
+
+create index subscription_tags_tag_id_idx on subscription_tags (tag_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table subscription_tags;
+
+drop table tags;
+
+alter table subscriptions
+   drop column category;
+
+alter table services
+   drop constraint services_category_check;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An empty category is the user's overall budget.
+create table
+   budgets (
+      id uuid primary key,
+      user_id uuid not null,
+      period text not null check (period in ('month', 'year')),
+      category text not null default '' check (
+         category in ('', 'streaming', 'music', 'cloud', 'software', 'gaming', 'news', 'education', 'fitness', 'other')
+      ),
+      limit_minor bigint not null check (limit_minor > 0),
+      limit_currency text not null,
+      unique (user_id, period, category)
+   );
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table budgets;
+
+-- +goose StatementEnd
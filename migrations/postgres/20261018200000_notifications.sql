@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An empty events list sends every event to the address.
+create table
+   notification_preferences (
+      user_id uuid not null,
+      channel text not null check (channel in ('email', 'webhook')),
+      address text not null,
+      events text[] not null default '{}',
+      primary key (user_id, channel, address)
+   );
+
+create table
+   notification_deliveries (
+      id uuid primary key,
+      user_id uuid not null,
+      channel text not null,
+      address text not null,
+      event text not null,
+      subject text not null,
+      dedup_key text not null default '',
+      status text not null check (status in ('pending', 'sent', 'failed')),
+      attempts int not null default 0,
+      last_error text not null default '',
+      created_at timestamptz not null,
+      updated_at timestamptz not null
+   );
+
+-- A notification with a key reaches every address once.
+create unique index notification_deliveries_dedup_key_idx on notification_deliveries (user_id, channel, address, dedup_key)
+where
+   dedup_key <> '';
+
+create index notification_deliveries_user_id_idx on notification_deliveries (user_id, created_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table notification_deliveries;
+
+drop table notification_preferences;
+
+-- +goose StatementEnd
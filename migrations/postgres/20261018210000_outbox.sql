@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- payload is the whole event, the other columns serve the relay.
+create table
+   outbox_events (
+      seq bigserial primary key,
+      id uuid not null unique,
+      event_type text not null,
+      subscription_id uuid not null,
+      payload jsonb not null,
+      created_at timestamptz not null,
+      attempts int not null default 0,
+      last_error text not null default '',
+      next_attempt_at timestamptz not null,
+      published_at timestamptz
+   );
+
+create index outbox_events_pending_idx on outbox_events (seq)
+where
+   published_at is null;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table outbox_events;
+
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An empty events list sends every event, a null user_id the events of every user.
+create table
+   webhooks (
+      id uuid primary key,
+      url text not null,
+      events text[] not null default '{}',
+      user_id uuid,
+      secret text not null,
+      enabled boolean not null default true,
+      failures int not null default 0,
+      disabled_at timestamptz,
+      created_at timestamptz not null
+   );
+
+create table
+   webhook_deliveries (
+      id uuid primary key,
+      webhook_id uuid not null references webhooks (id) on delete cascade,
+      event_id uuid not null,
+      event_type text not null,
+      payload jsonb not null,
+      status text not null check (status in ('pending', 'sent', 'failed')),
+      attempts int not null default 0,
+      response_status int not null default 0,
+      last_error text not null default '',
+      next_attempt_at timestamptz not null,
+      created_at timestamptz not null,
+      updated_at timestamptz not null,
+      -- The outbox may publish an event twice, the webhook gets it once.
+      unique (webhook_id, event_id)
+   );
+
+create index webhook_deliveries_due_idx on webhook_deliveries (next_attempt_at)
+where
+   status = 'pending';
+
+create index webhook_deliveries_webhook_id_idx on webhook_deliveries (webhook_id, created_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table webhook_deliveries;
+
+drop table webhooks;
+
+-- +goose StatementEnd
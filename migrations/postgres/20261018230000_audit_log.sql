@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- changes maps the changed fields of the entity to {"before": ..., "after": ...}.
+create table
+   audit_log (
+      id bigserial primary key,
+      occurred_at timestamptz not null,
+      actor text not null,
+      request_id text not null default '',
+      action text not null check (action in ('create', 'update', 'delete', 'restore', 'purge')),
+      entity_type text not null,
+      entity_id uuid not null,
+      changes jsonb not null
+   );
+
+create index audit_log_entity_idx on audit_log (entity_type, entity_id, id);
+
+create index audit_log_actor_idx on audit_log (actor, id);
+
+create index audit_log_occurred_at_idx on audit_log (occurred_at);
+
+create function audit_log_append_only () returns trigger as $$
+begin
+   raise exception 'audit_log is append-only';
+end;
+$$ language plpgsql;
+
+create trigger audit_log_append_only before
+update
+or delete on audit_log for each row
+execute function audit_log_append_only ();
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table audit_log;
+
+drop function audit_log_append_only;
+
+-- +goose StatementEnd
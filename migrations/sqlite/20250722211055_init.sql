@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+create table
+   subscriptions (
+      id text primary key,
+      service_name text not null,
+      price integer not null,
+      user_id text not null,
+      start_date text not null,
+      end_date text
+   );
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE subscriptions;
+
+-- +goose StatementEnd
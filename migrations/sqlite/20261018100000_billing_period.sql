@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table subscriptions
+   add column billing_unit text not null default 'month' check (billing_unit in ('day', 'week', 'month', 'year'));
+
+alter table subscriptions
+   add column billing_count integer not null default 1 check (billing_count > 0);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+alter table subscriptions
+   drop column billing_count;
+
+alter table subscriptions
+   drop column billing_unit;
+
+-- +goose StatementEnd
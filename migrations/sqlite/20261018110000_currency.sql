@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table subscriptions
+   add column currency text not null default 'RUB';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+alter table subscriptions
+   drop column currency;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table subscriptions
+   add column status text not null default 'active' check (status in ('active', 'paused', 'cancelled', 'expired'));
+
+create table
+   subscription_status_history (
+      id integer primary key autoincrement,
+      subscription_id text not null references subscriptions (id) on delete cascade,
+      from_status text not null,
+      to_status text not null,
+      effective_date text not null,
+      changed_at text not null
+   );
+
+create index subscription_status_history_subscription_id_idx on subscription_status_history (subscription_id, id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table subscription_status_history;
+
+alter table subscriptions
+   drop column status;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table subscriptions
+   add column trial_start text;
+
+alter table subscriptions
+   add column trial_end text check (trial_end >= trial_start);
+
+alter table subscriptions
+   add column trial_ending_flagged_at text;
+
+create index subscriptions_trial_end_idx on subscriptions (trial_end)
+where
+   trial_ending_flagged_at is null;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop index subscriptions_trial_end_idx;
+
+alter table subscriptions
+   drop column trial_ending_flagged_at;
+
+alter table subscriptions
+   drop column trial_end;
+
+alter table subscriptions
+   drop column trial_start;
+
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Only the hash of a feed token is kept, the token itself is shown to the user once.
+create table
+   calendar_feeds (
+      user_id text primary key,
+      token_hash text not null unique,
+      created_at text not null default current_timestamp
+   );
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table calendar_feeds;
+
+-- +goose StatementEnd
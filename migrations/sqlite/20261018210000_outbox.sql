@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- payload is the whole event as JSON, the other columns serve the relay.
+create table
+   outbox_events (
+      seq integer primary key autoincrement,
+      id text not null unique,
+      event_type text not null,
+      subscription_id text not null,
+      payload text not null,
+      created_at text not null,
+      attempts int not null default 0,
+      last_error text not null default '',
+      next_attempt_at text not null,
+      published_at text
+   );
+
+create index outbox_events_pending_idx on outbox_events (seq)
+where
+   published_at is null;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table outbox_events;
+
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- events is a comma-separated list, an empty one sends every event, a null user_id the events of every user.
+create table
+   webhooks (
+      id text primary key,
+      url text not null,
+      events text not null default '',
+      user_id text,
+      secret text not null,
+      enabled integer not null default 1,
+      failures int not null default 0,
+      disabled_at text,
+      created_at text not null
+   );
+
+create table
+   webhook_deliveries (
+      id text primary key,
+      webhook_id text not null references webhooks (id) on delete cascade,
+      event_id text not null,
+      event_type text not null,
+      payload text not null,
+      status text not null check (status in ('pending', 'sent', 'failed')),
+      attempts int not null default 0,
+      response_status int not null default 0,
+      last_error text not null default '',
+      next_attempt_at text not null,
+      created_at text not null,
+      updated_at text not null,
+      -- The outbox may publish an event twice, the webhook gets it once.
+      unique (webhook_id, event_id)
+   );
+
+create index webhook_deliveries_due_idx on webhook_deliveries (next_attempt_at)
+where
+   status = 'pending';
+
+create index webhook_deliveries_webhook_id_idx on webhook_deliveries (webhook_id, created_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table webhook_deliveries;
+
+drop table webhooks;
+
+-- +goose StatementEnd
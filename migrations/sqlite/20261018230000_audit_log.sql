@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- changes maps the changed fields of the entity to {"before": ..., "after": ...}.
+create table
+   audit_log (
+      id integer primary key autoincrement,
+      occurred_at text not null,
+      actor text not null,
+      request_id text not null default '',
+      action text not null check (action in ('create', 'update', 'delete', 'restore', 'purge')),
+      entity_type text not null,
+      entity_id text not null,
+      changes text not null
+   );
+
+create index audit_log_entity_idx on audit_log (entity_type, entity_id, id);
+
+create index audit_log_actor_idx on audit_log (actor, id);
+
+create index audit_log_occurred_at_idx on audit_log (occurred_at);
+
+create trigger audit_log_no_update before
+update on audit_log begin
+select
+   raise (abort, 'audit_log is append-only');
+
+end;
+
+create trigger audit_log_no_delete before delete on audit_log begin
+select
+   raise (abort, 'audit_log is append-only');
+
+end;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table audit_log;
+
+-- +goose StatementEnd
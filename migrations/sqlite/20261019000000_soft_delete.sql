@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A deleted subscription stays in the trash with deleted_at set until the purge job removes it.
+alter table subscriptions
+   add column deleted_at text;
+
+create index subscriptions_deleted_at_idx on subscriptions (deleted_at)
+where
+   deleted_at is not null;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop index subscriptions_deleted_at_idx;
+
+delete from subscriptions
+where
+   deleted_at is not null;
+
+alter table subscriptions
+   drop column deleted_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every change of a subscription bumps its version, an update names the version it was based on.
+alter table subscriptions
+   add column version integer not null default 1;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+alter table subscriptions
+   drop column version;
+
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The dispatcher sends pending deliveries from the table, so they carry the message and the time of the next attempt.
+alter table notification_deliveries
+   add column body text not null default '';
+
+alter table notification_deliveries
+   add column data text not null default '';
+
+alter table notification_deliveries
+   add column next_attempt_at text not null default '';
+
+-- Pending deliveries logged before lost their message with the process that was sending them.
+update notification_deliveries
+set
+   status = 'failed',
+   last_error = 'message lost on restart'
+where
+   status = 'pending';
+
+update notification_deliveries
+set
+   next_attempt_at = updated_at;
+
+create index notification_deliveries_due_idx on notification_deliveries (next_attempt_at)
+where
+   status = 'pending';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop index notification_deliveries_due_idx;
+
+alter table notification_deliveries
+   drop column next_attempt_at;
+
+alter table notification_deliveries
+   drop column data;
+
+alter table notification_deliveries
+   drop column body;
+
+-- +goose StatementEnd
@@ -9,20 +9,126 @@ import (
 	"github.com/joho/godotenv"
 )
 
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+	StorageSQLite   = "sqlite"
+
+	PublisherLog  = "log"
+	PublisherHTTP = "http"
+)
+
 type Config struct {
-	HTTP     HTTP
-	Postgres Postgres
-	Logger   Logger
+	HTTP      HTTP
+	Storage   Storage
+	Postgres  Postgres
+	SQLite    SQLite
+	Logger    Logger
+	Currency  Currency
+	Trials    Trials
+	Trash     Trash
+	Notify    Notify
+	Scheduler Scheduler
+	Outbox    Outbox
+	Webhooks  Webhooks
 }
 
 type HTTP struct {
 	Port         int           `env:"HTTP_PORT"`
 	ReadTimeout  time.Duration `env:"HTTP_READ_TIMEOUT"`
 	WriteTimeout time.Duration `env:"HTTP_WRITE_TIMEOUT"`
+	// PublicURL is the address clients reach the service at, calendar feed links are built on it.
+	// When it is empty the links use the host of the request.
+	PublicURL string `env:"HTTP_PUBLIC_URL"`
+}
+
+type Storage struct {
+	// Driver selects where subscriptions are kept: postgres, sqlite for small self-hosted installs,
+	// or memory for demos without a database.
+	Driver string `env:"STORAGE_DRIVER" envDefault:"postgres"`
 }
 
 type Postgres struct {
-	DSN string `env:"POSTGRES_DSN"`
+	DSN               string        `env:"POSTGRES_DSN"`
+	MaxConns          int32         `env:"POSTGRES_MAX_CONNS" envDefault:"10"`
+	MinConns          int32         `env:"POSTGRES_MIN_CONNS" envDefault:"0"`
+	MaxConnLifetime   time.Duration `env:"POSTGRES_MAX_CONN_LIFETIME" envDefault:"1h"`
+	MaxConnIdleTime   time.Duration `env:"POSTGRES_MAX_CONN_IDLE_TIME" envDefault:"30m"`
+	HealthCheckPeriod time.Duration `env:"POSTGRES_HEALTH_CHECK_PERIOD" envDefault:"1m"`
+}
+
+type SQLite struct {
+	Path string `env:"SQLITE_PATH" envDefault:"subscriptions.db"`
+}
+
+type Currency struct {
+	Default   string `env:"DEFAULT_CURRENCY" envDefault:"RUB"`
+	RatesFile string `env:"EXCHANGE_RATES_FILE"`
+}
+
+type Trials struct {
+	// NoticeDays is how many days before its end a trial gets flagged.
+	NoticeDays int `env:"TRIAL_NOTICE_DAYS" envDefault:"3"`
+}
+
+type Trash struct {
+	// Retention is how long deleted subscriptions can be restored before the purge job removes them.
+	Retention time.Duration `env:"TRASH_RETENTION" envDefault:"720h"`
+}
+
+type Notify struct {
+	// SMTPHost enables e-mail notifications, MailHog listens on localhost:1025 and needs no credentials.
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     int    `env:"SMTP_PORT" envDefault:"1025"`
+	SMTPFrom     string `env:"SMTP_FROM" envDefault:"subscriptions@localhost"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	// SMTPTimeout bounds a whole e-mail send, from dialing the server to its last answer.
+	SMTPTimeout time.Duration `env:"SMTP_TIMEOUT" envDefault:"30s"`
+
+	WebhookTimeout time.Duration `env:"NOTIFY_WEBHOOK_TIMEOUT" envDefault:"10s"`
+	// MaxAttempts bounds the tries of a delivery, the pause between them starts at RetryBackoff and doubles.
+	MaxAttempts  int           `env:"NOTIFY_MAX_ATTEMPTS" envDefault:"5"`
+	RetryBackoff time.Duration `env:"NOTIFY_RETRY_BACKOFF" envDefault:"30s"`
+	PollInterval time.Duration `env:"NOTIFY_POLL_INTERVAL" envDefault:"1s"`
+
+	// RenewalNoticeDays is how many days ahead users are reminded of a charge.
+	RenewalNoticeDays int `env:"RENEWAL_NOTICE_DAYS" envDefault:"1"`
+}
+
+// Scheduler holds the cron expressions, in UTC, the background jobs run on.
+type Scheduler struct {
+	Expire   string `env:"SCHEDULE_EXPIRE" envDefault:"5 * * * *"`
+	Trials   string `env:"SCHEDULE_TRIALS" envDefault:"15 * * * *"`
+	Renewals string `env:"SCHEDULE_RENEWALS" envDefault:"30 * * * *"`
+	Purge    string `env:"SCHEDULE_PURGE" envDefault:"45 3 * * *"`
+	Outbox   string `env:"SCHEDULE_OUTBOX" envDefault:"50 3 * * *"`
+}
+
+type Outbox struct {
+	// Publisher is where subscription events go: log, or http to post them to WebhookURL.
+	Publisher      string        `env:"OUTBOX_PUBLISHER" envDefault:"log"`
+	WebhookURL     string        `env:"OUTBOX_WEBHOOK_URL"`
+	WebhookTimeout time.Duration `env:"OUTBOX_WEBHOOK_TIMEOUT" envDefault:"10s"`
+	PollInterval   time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	BatchSize      int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	// RetryBackoff is the pause before publishing a failed event again, it doubles with every failure.
+	RetryBackoff time.Duration `env:"OUTBOX_RETRY_BACKOFF" envDefault:"5s"`
+	// Retention is how long published events stay in the outbox before the outbox job removes them.
+	Retention time.Duration `env:"OUTBOX_RETENTION" envDefault:"168h"`
+}
+
+// Webhooks configures the delivery of subscription events to the webhooks registered through the API.
+type Webhooks struct {
+	Timeout time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	// MaxAttempts bounds the tries of a delivery, the pause between them starts at RetryBackoff and doubles.
+	MaxAttempts  int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	RetryBackoff time.Duration `env:"WEBHOOK_RETRY_BACKOFF" envDefault:"10s"`
+	// DisableAfter is how many attempts in a row a webhook may fail before it is disabled.
+	DisableAfter int           `env:"WEBHOOK_DISABLE_AFTER" envDefault:"20"`
+	PollInterval time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"1s"`
+	// Concurrency is how many webhooks are posted to at once.
+	Concurrency int `env:"WEBHOOK_CONCURRENCY" envDefault:"8"`
 }
 
 type Logger struct {
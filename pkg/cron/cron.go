@@ -0,0 +1,201 @@
+// Package cron parses cron expressions and works out when they fire next.
+//
+// An expression has five fields: minute, hour, day of month, month and day of week. A field is *,
+// a value, a range like 1-5, or a list of those separated by commas; */15 and 1-30/5 step through
+// the range. Months and days of week may be named (JAN, MON), Sunday is 0 or 7. The @yearly,
+// @monthly, @weekly, @daily and @hourly shorthands are understood too.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpression = errors.New("invalid cron expression")
+
+// maxYears bounds the search for the next time, an expression like "0 0 30 2 *" never fires.
+const maxYears = 5
+
+var shorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minutes    = field{name: "minute", min: 0, max: 59}
+	hours      = field{name: "hour", min: 0, max: 23}
+	daysOfMon  = field{name: "day of month", min: 1, max: 31}
+	months     = field{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	daysOfWeek = field{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// Schedule is a parsed cron expression, each field is a bit set of the values it matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// anyDom and anyDow keep whether the day fields were *: cron matches a day when both match,
+	// unless both are restricted, then either is enough.
+	anyDom, anyDow bool
+}
+
+// Parse parses the expression, errors wrap ErrInvalidExpression.
+func Parse(expr string) (Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if full, ok := shorthands[strings.ToLower(spec)]; ok {
+		spec = full
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return Schedule{}, fmt.Errorf("%w %q: want 5 fields, got %d", ErrInvalidExpression, expr, len(parts))
+	}
+
+	var (
+		s   Schedule
+		err error
+	)
+
+	for i, p := range []struct {
+		f    field
+		bits *uint64
+	}{
+		{minutes, &s.minute},
+		{hours, &s.hour},
+		{daysOfMon, &s.dom},
+		{months, &s.month},
+		{daysOfWeek, &s.dow},
+	} {
+		if *p.bits, err = p.f.parse(parts[i]); err != nil {
+			return Schedule{}, fmt.Errorf("%w %q: %v", ErrInvalidExpression, expr, err)
+		}
+	}
+
+	// Sunday is both 0 and 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	s.anyDom = parts[2] == "*" || strings.HasPrefix(parts[2], "*/")
+	s.anyDow = parts[4] == "*" || strings.HasPrefix(parts[4], "*/")
+
+	return s, nil
+}
+
+// Next returns the first time after t the schedule fires at, in t's location. It returns the zero time
+// when the schedule never fires, like on February 30th.
+func (s Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s Schedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDom || s.anyDow {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// parse turns a field of the expression into the bit set of values it matches.
+func (f field) parse(spec string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(spec, ",") {
+		rng, stepSpec, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepSpec); err != nil || step < 1 {
+				return 0, fmt.Errorf("%s: bad step %q", f.name, stepSpec)
+			}
+		}
+
+		from, to := f.min, f.max
+
+		if rng != "*" {
+			lo, hi, isRange := strings.Cut(rng, "-")
+
+			var err error
+			if from, err = f.value(lo); err != nil {
+				return 0, err
+			}
+
+			to = from
+			if isRange {
+				if to, err = f.value(hi); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				to = f.max
+			}
+
+			if from > to {
+				return 0, fmt.Errorf("%s: bad range %q", f.name, rng)
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func (f field) value(spec string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(spec, name) {
+			return f.min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(spec)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s: bad value %q, want %d-%d", f.name, spec, f.min, f.max)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,106 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{"* * * * *", false},
+		{"5 * * * *", false},
+		{"*/15 0-6 1,15 * mon-fri", false},
+		{"1-30/5 * * JAN,dec 0", false},
+		{"0 0 * * 7", false},
+		{"@daily", false},
+		{" @Hourly ", false},
+		{"", true},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"5-1 * * * *", true},
+		{"*/0 * * * *", true},
+		{"*/x * * * *", true},
+		{"* * * foo *", true},
+		{"@sometimes", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, ErrInvalidExpression) {
+				t.Errorf("Parse(%q) error = %v, want it to wrap ErrInvalidExpression", tt.expr, err)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		from string
+		want string
+	}{
+		{"every minute", "* * * * *", "2026-10-18T10:00:30Z", "2026-10-18T10:01:00Z"},
+		{"strictly after", "5 * * * *", "2026-10-18T10:05:00Z", "2026-10-18T11:05:00Z"},
+		{"later this hour", "5 * * * *", "2026-10-18T10:04:59Z", "2026-10-18T10:05:00Z"},
+		{"next hour", "5 * * * *", "2026-10-18T10:06:00Z", "2026-10-18T11:05:00Z"},
+		{"next day", "45 3 * * *", "2026-10-18T04:00:00Z", "2026-10-19T03:45:00Z"},
+		{"step", "*/15 * * * *", "2026-10-18T10:16:00Z", "2026-10-18T10:30:00Z"},
+		{"step over the hour", "*/15 * * * *", "2026-10-18T10:50:00Z", "2026-10-18T11:00:00Z"},
+		{"range with step", "10-30/10 * * * *", "2026-10-18T10:21:00Z", "2026-10-18T10:30:00Z"},
+		{"list", "0 9,18 * * *", "2026-10-18T10:00:00Z", "2026-10-18T18:00:00Z"},
+		{"day of week", "0 9 * * mon", "2026-10-18T10:00:00Z", "2026-10-19T09:00:00Z"},
+		{"sunday as 7", "0 9 * * 7", "2026-10-19T10:00:00Z", "2026-10-25T09:00:00Z"},
+		{"day of month", "0 0 31 * *", "2026-11-01T00:00:00Z", "2026-12-31T00:00:00Z"},
+		{"either restricted day matches", "0 0 13 * fri", "2026-10-18T00:00:00Z", "2026-10-23T00:00:00Z"},
+		{"both days when one is a step", "0 0 */2 * fri", "2026-10-18T00:00:00Z", "2026-10-23T00:00:00Z"},
+		{"named month", "0 0 1 feb *", "2026-10-18T00:00:00Z", "2027-02-01T00:00:00Z"},
+		{"leap day", "0 0 29 2 *", "2026-10-18T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"yearly", "@yearly", "2026-10-18T00:00:00Z", "2027-01-01T00:00:00Z"},
+		{"never", "0 0 30 2 *", "2026-10-18T00:00:00Z", "0001-01-01T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+
+			from, _ := time.Parse(time.RFC3339, tt.from)
+			want, _ := time.Parse(time.RFC3339, tt.want)
+
+			if got := s.Next(from); !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextKeepsLocation(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	s, err := Parse("0 9 * * *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got := s.Next(time.Date(2026, 10, 18, 10, 0, 0, 0, moscow))
+	if want := time.Date(2026, 10, 19, 9, 0, 0, 0, moscow); !got.Equal(want) || got.Location() != moscow {
+		t.Errorf("Next() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,101 @@
+// Package ical writes RFC 5545 calendars of all-day events, the subset calendar apps need to subscribe to a feed.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+	// maxLineOctets is the longest content line RFC 5545 allows before it has to be folded.
+	maxLineOctets = 75
+)
+
+// Event is an all-day event, UID has to stay the same for the same event across downloads of the feed.
+type Event struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+}
+
+// Calendar is a published calendar, ProdID identifies the product that generated it.
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Write encodes the calendar, stamp is the time the feed was generated at.
+func Write(w io.Writer, cal Calendar, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+
+	line := func(name, value string) {
+		writeLine(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", cal.ProdID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+
+	for _, event := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escape(event.UID))
+		line("DTSTAMP", stamp.UTC().Format(dateTimeLayout))
+		line("DTSTART;VALUE=DATE", event.Date.Format(dateLayout))
+		line("DTEND;VALUE=DATE", event.Date.AddDate(0, 0, 1).Format(dateLayout))
+		line("SUMMARY", escape(event.Summary))
+
+		if event.Description != "" {
+			line("DESCRIPTION", escape(event.Description))
+		}
+
+		// An all-day reminder shouldn't block the day in free/busy lookups.
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	return bw.Flush()
+}
+
+// writeLine ends the content line with CRLF, folding it into continuation lines that start
+// with a space so that no line is longer than 75 octets. Multi-byte characters are never split.
+func writeLine(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+
+		// The leading space of a continuation line counts towards its length.
+		limit = maxLineOctets - 1
+	}
+
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,142 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Netflix", "Netflix"},
+		{"Netflix, Premium", `Netflix\, Premium`},
+		{"a;b", `a\;b`},
+		{`C:\path`, `C:\\path`},
+		{"two\nlines", `two\nlines`},
+		{"two\r\nlines", `two\nlines`},
+		{`\,`, `\\\,`},
+		{"Яндекс Плюс", "Яндекс Плюс"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := escape(tt.in); got != tt.want {
+				t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{"short", "SUMMARY:Netflix", []string{"SUMMARY:Netflix"}},
+		{"exactly the limit", strings.Repeat("a", 75), []string{strings.Repeat("a", 75)}},
+		{"one over the limit", strings.Repeat("a", 76), []string{strings.Repeat("a", 75), " a"}},
+		{
+			"continuation lines are one shorter",
+			strings.Repeat("a", 75+74+1),
+			[]string{strings.Repeat("a", 75), " " + strings.Repeat("a", 74), " a"},
+		},
+		{
+			"multi-byte character is not split",
+			strings.Repeat("a", 74) + "ж",
+			[]string{strings.Repeat("a", 74), " ж"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := bufio.NewWriter(&buf)
+			writeLine(w, tt.line)
+			w.Flush()
+
+			want := strings.Join(tt.want, "\r\n") + "\r\n"
+			if buf.String() != want {
+				t.Errorf("writeLine() = %q, want %q", buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestWriteLineFolding(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("Подписка на Яндекс Плюс, ", 20)
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	writeLine(w, line)
+	w.Flush()
+
+	folded := strings.TrimSuffix(buf.String(), "\r\n")
+
+	for i, l := range strings.Split(folded, "\r\n") {
+		if len(l) > maxLineOctets {
+			t.Errorf("line %d is %d octets long, want at most %d", i, len(l), maxLineOctets)
+		}
+
+		if !utf8.ValidString(l) {
+			t.Errorf("line %d splits a character: %q", i, l)
+		}
+	}
+
+	if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != line {
+		t.Errorf("unfolded line = %q, want %q", unfolded, line)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	cal := Calendar{
+		ProdID: "-//subscriptions//RU",
+		Name:   "Подписки",
+		Events: []Event{
+			{UID: "1@subscriptions", Date: time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), Summary: "Netflix, 999.00 RUB"},
+			{UID: "2@subscriptions", Date: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), Summary: "Okko", Description: "Продление;\nгодовой план"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, cal, time.Date(2025, 7, 1, 12, 30, 0, 0, time.FixedZone("MSK", 3*60*60))); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//subscriptions//RU",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Подписки",
+		"BEGIN:VEVENT",
+		"UID:1@subscriptions",
+		"DTSTAMP:20250701T093000Z",
+		"DTSTART;VALUE=DATE:20250731",
+		"DTEND;VALUE=DATE:20250801",
+		`SUMMARY:Netflix\, 999.00 RUB`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:2@subscriptions",
+		"DTSTAMP:20250701T093000Z",
+		"DTSTART;VALUE=DATE:20251231",
+		"DTEND;VALUE=DATE:20260101",
+		"SUMMARY:Okko",
+		`DESCRIPTION:Продление\;\nгодовой план`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+
+	if buf.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AdvisoryLocker takes session-level advisory locks, so processes sharing a database can agree
+// on who does a piece of work. A lock lives on one pooled connection until it is released and
+// is dropped by Postgres when that connection is lost.
+type AdvisoryLocker struct {
+	pool *pgxpool.Pool
+}
+
+func NewAdvisoryLocker(pool *pgxpool.Pool) *AdvisoryLocker {
+	return &AdvisoryLocker{pool: pool}
+}
+
+// TryLock takes the advisory lock keyed by the hash of name without waiting for it.
+func (l *AdvisoryLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	key := lockKey(name)
+
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("pkg/postgres: TryLock: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "select pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		conn.Release()
+		return nil, false, fmt.Errorf("pkg/postgres: TryLock: %w", err)
+	}
+
+	if !locked {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		// The lock must not go back to the pool with the connection, so a connection
+		// that failed to release it is closed, which releases it too.
+		if _, err := conn.Exec(context.WithoutCancel(ctx), "select pg_advisory_unlock($1)", key); err != nil {
+			_ = conn.Conn().Close(context.WithoutCancel(ctx))
+		}
+
+		conn.Release()
+	}
+
+	return unlock, true, nil
+}
+
+// lockKey maps a lock name to the bigint key of an advisory lock.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+
+	return int64(h.Sum64())
+}
@@ -6,25 +6,50 @@ import (
 	"errors"
 	"fmt"
 	"online-subscribe-rest-service/migrations"
+	"online-subscribe-rest-service/pkg/config"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pressly/goose/v3"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
 
-func ConnectToPostgres(ctx context.Context, dsn string) (*pgx.Conn, error) {
-	conn, err := pgx.Connect(ctx, dsn)
+func ConnectToPostgres(ctx context.Context, cfg config.Postgres) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("pkg/postgres: ParseConfig: %w", err)
+	}
+
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = cfg.MaxConns
+	}
+
+	poolCfg.MinConns = cfg.MinConns
+
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+
+	if cfg.HealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("pkg/postgres: ConnectToPosgres: %w", err)
 	}
 
-	err = conn.Ping(ctx)
+	err = pool.Ping(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("pkg/postgres: conn.Ping: %w", err)
+		pool.Close()
+		return nil, fmt.Errorf("pkg/postgres: pool.Ping: %w", err)
 	}
 
-	return conn, nil
+	return pool, nil
 }
 
 func UpMigrations(dsn string) error {
@@ -33,7 +58,7 @@ func UpMigrations(dsn string) error {
 		return err
 	}
 
-	fs := migrations.FS
+	fs := migrations.Postgres
 	goose.SetBaseFS(fs)
 	goose.SetLogger(goose.NopLogger())
 
@@ -42,7 +67,7 @@ func UpMigrations(dsn string) error {
 		return err
 	}
 
-	err = goose.Up(db, ".")
+	err = goose.Up(db, "postgres")
 	if err != nil && !errors.Is(err, goose.ErrNoNextVersion) {
 		return err
 	}
@@ -0,0 +1,49 @@
+// Package publicnet keeps the requests made to user supplied URLs on the internet, away from the
+// service's own network.
+package publicnet
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// Addr reports whether addr may be reached from the internet, that is it is a unicast address
+// outside the loopback, private and link-local ranges.
+func Addr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// Transport dials public addresses only, so a URL whose name resolves to a loopback or private
+// address cannot reach the service's own network. It ignores the proxy environment for the same
+// reason: the proxy would dial on our behalf.
+func Transport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !Addr(addr) {
+				return fmt.Errorf("address %s is not public", host)
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return transport
+}
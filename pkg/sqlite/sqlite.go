@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"online-subscribe-rest-service/migrations"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+	"modernc.org/sqlite"
+)
+
+func init() {
+	// SQLite's lower() only folds ASCII, unicode_lower makes case-insensitive search work for "Яндекс" too.
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return args[0], nil
+		}
+
+		return strings.ToLower(s), nil
+	})
+}
+
+// Open opens the database file at path, creating it when missing.
+//
+// SQLite allows a single writer, so the pool keeps one connection: statements queue up
+// instead of failing with SQLITE_BUSY, and a transaction has the database to itself.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path)
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("pkg/sqlite: Open: %w", err)
+	}
+
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("pkg/sqlite: db.Ping: %w", err)
+	}
+
+	return db, nil
+}
+
+func UpMigrations(db *sql.DB) error {
+	fs := migrations.SQLite
+	goose.SetBaseFS(fs)
+	goose.SetLogger(goose.NopLogger())
+
+	err := goose.SetDialect("sqlite3")
+	if err != nil {
+		return err
+	}
+
+	err = goose.Up(db, "sqlite")
+	if err != nil && !errors.Is(err, goose.ErrNoNextVersion) {
+		return err
+	}
+
+	return nil
+}